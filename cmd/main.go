@@ -23,6 +23,13 @@ const (
 // @Description API Server for Library music service
 // @Host localhost:8090
 // @BasePath /
+// @SecurityDefinitions.apikey ApiKeyAuth
+// @In header
+// @Name X-API-Key
+// @SecurityDefinitions.apikey BearerAuth
+// @In header
+// @Name Authorization
+// @Description JWT or session token as "Bearer <token>"
 func main() {
 	if err := godotenv.Load(); err != nil {
 		panic("Error loading .env file: " + err.Error())
@@ -33,7 +40,7 @@ func main() {
 	storagePath := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
 		cfg.DB.Host, cfg.DB.Port, cfg.DB.Username, cfg.DB.DBName, cfg.DB.Password, cfg.DB.SSLMode)
 
-	application := app.New(log, storagePath, cfg.Server.Port)
+	application := app.New(log, storagePath, cfg)
 
 	log.Info("starting server")
 	go application.Server.MustRun()
@@ -7,6 +7,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 	"library-music/internal/app/server"
+	"library-music/internal/config"
 	"library-music/internal/handler"
 	"library-music/internal/storage"
 	"library-music/internal/storage/postgres"
@@ -16,26 +17,34 @@ import (
 type App struct {
 	Server *server.Server
 	DB     *sqlx.DB
+	// stopJobs stops the background jobs of the services.
+	stopJobs context.CancelFunc
 }
 
-func New(log *slog.Logger, storagePath string, port string) *App {
+func New(log *slog.Logger, storagePath string, cfg *config.Config) *App {
 	db, err := connectDB(storagePath)
 	if err != nil {
 		log.Warn(err.Error())
 	}
 
 	repos := storage.NewRepository(db)
-	srs := handler.NewService(log, repos)
-	handlers := handler.NewHandler(srs)
+	srs := handler.NewService(log, repos, cfg)
+	handlers := handler.NewHandler(log, srs)
 
-	srv := server.New(log, port, handlers.InitRouter())
+	jobs, stopJobs := context.WithCancel(context.Background())
+	srs.LinkHealth.Run(jobs)
+	go srs.Music.Backfill(jobs)
+
+	srv := server.New(log, cfg.Server.Port, handlers.InitRouter())
 	return &App{
-		Server: srv,
-		DB:     db,
+		Server:   srv,
+		DB:       db,
+		stopJobs: stopJobs,
 	}
 }
 
 func (a *App) Stop(ctx context.Context) {
+	a.stopJobs()
 	err := a.DB.Close()
 	if err != nil {
 		slog.Error(err.Error())
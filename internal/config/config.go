@@ -4,12 +4,17 @@ import (
 	"flag"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"time"
 )
 
 type Config struct {
 	Env    string    `yaml:"env" env-default:"local"`
 	Server CfgServer `yaml:"server"`
 	DB     CfgDB     `yaml:"db"`
+	Auth   CfgAuth   `yaml:"auth"`
+	Lyrics CfgLyrics `yaml:"lyrics"`
+	Links  CfgLinks  `yaml:"links"`
+	Media  CfgMedia  `yaml:"media"`
 }
 
 type CfgDB struct {
@@ -25,6 +30,49 @@ type CfgServer struct {
 	Port string `yaml:"port"`
 }
 
+type CfgAuth struct {
+	APIKeys    []CfgAPIKey   `yaml:"api_keys"`
+	JWT        CfgJWT        `yaml:"jwt"`
+	SessionTTL time.Duration `yaml:"session_ttl" env-default:"720h"`
+}
+
+type CfgAPIKey struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+	Role string `yaml:"role"`
+}
+
+type CfgJWT struct {
+	HS256SecretFile    string `yaml:"hs256_secret_file"`
+	RS256PublicKeyFile string `yaml:"rs256_public_key_file"`
+	Issuer             string `yaml:"issuer"`
+	Audience           string `yaml:"audience"`
+}
+
+type CfgLyrics struct {
+	// ExplicitWordsFile lists words that mark lyrics as explicit, one per line.
+	ExplicitWordsFile string `yaml:"explicit_words_file" env:"EXPLICIT_WORDS_FILE"`
+}
+
+type CfgLinks struct {
+	// CheckInterval is how often every link is checked; 0 turns the
+	// background check off, leaving the on-demand one.
+	CheckInterval time.Duration `yaml:"check_interval" env-default:"24h"`
+	// CheckTimeout bounds the check of a single link.
+	CheckTimeout time.Duration `yaml:"check_timeout" env-default:"10s"`
+	CheckWorkers int           `yaml:"check_workers" env-default:"4"`
+	MaxRedirects int           `yaml:"max_redirects" env-default:"5"`
+}
+
+type CfgMedia struct {
+	// Dir is where uploaded files are stored.
+	Dir string `yaml:"dir" env:"MEDIA_DIR" env-default:"./media"`
+	// BaseURL is the URL the stored files are served under.
+	BaseURL        string `yaml:"base_url" env-default:"/api/public/media"`
+	MaxImagePixels int    `yaml:"max_image_pixels" env-default:"40000000"`
+	ThumbnailSizes []int  `yaml:"thumbnail_sizes" env-default:"64,256,512"`
+}
+
 func MustLoad() *Config {
 	path := fetchConfigPath()
 	if path == "" {
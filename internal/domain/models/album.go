@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Album struct {
+	Id          int          `json:"id" db:"id"`
+	Title       string       `json:"title" db:"title"`
+	Group       Group        `json:"group" db:"group"`
+	ReleaseDate *time.Time   `json:"releaseDate" db:"release_date"`
+	CoverLink   *string      `json:"coverLink" db:"cover_link"`
+	Tracks      []AlbumTrack `json:"tracks" db:"-"`
+}
+
+type AlbumTrack struct {
+	AlbumId     int    `json:"albumId" db:"album_id"`
+	MusicId     int    `json:"musicId" db:"music_id"`
+	Song        string `json:"song" db:"song"`
+	DiscNumber  int    `json:"discNumber" db:"disc_number"`
+	TrackNumber int    `json:"trackNumber" db:"track_number"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// Artwork is the cover of a song or the image of a group, whichever of
+// MusicId and GroupId is set. Key is where its files are in the blob
+// store; Sizes are the sizes of its square-bounded thumbnails.
+type Artwork struct {
+	Id      int    `db:"id"`
+	MusicId int    `db:"music_id"`
+	GroupId int    `db:"group_id"`
+	Key     string `db:"key"`
+	Ext     string `db:"ext"`
+	Width   int    `db:"width"`
+	Height  int    `db:"height"`
+	Sizes   []int  `db:"-"`
+}
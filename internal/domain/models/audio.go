@@ -0,0 +1,9 @@
+package models
+
+// Audio is the audio file of a song, stored in the blob store under Key.
+type Audio struct {
+	MusicId     int    `db:"music_id"`
+	Key         string `db:"key"`
+	ContentType string `db:"content_type"`
+	Size        int64  `db:"size"`
+}
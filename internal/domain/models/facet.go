@@ -0,0 +1,20 @@
+package models
+
+// FacetCount is the number of matching songs sharing one facet value.
+// Id is set for facets backed by a table, such as groups and genres.
+type FacetCount struct {
+	Id    int    `json:"id,omitempty"`
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// MusicFacets aggregates a filtered song listing. Total counts every
+// listed row, so it can be used to compute the number of pages.
+type MusicFacets struct {
+	Total   int          `json:"total"`
+	Groups  []FacetCount `json:"groups"`
+	Years   []FacetCount `json:"years"`
+	Decades []FacetCount `json:"decades"`
+	Genres  []FacetCount `json:"genres"`
+	Tags    []FacetCount `json:"tags"`
+}
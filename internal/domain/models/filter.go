@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// MusicFilter narrows down a song listing; zero-valued fields are not applied.
+type MusicFilter struct {
+	Song        string
+	Group       string
+	Text        string
+	Link        string
+	ReleaseDate time.Time
+	AlbumId     int
+	// GenreId also matches songs of all its subgenres.
+	GenreId int
+	// Tags must all be present, either on the song or on its group.
+	Tags []string
+	// Lang also matches regional variants, so "en" matches "en-GB".
+	Lang     string
+	Explicit *bool
+	// PersonId matches songs crediting the person, in Role when it is set.
+	PersonId int
+	Role     string
+	// MemberId matches songs of the groups the person was a member of,
+	// during MemberYear when it is set.
+	MemberId   int
+	MemberYear int
+	// Provider matches songs with a link to the provider.
+	Provider string
+}
@@ -0,0 +1,7 @@
+package models
+
+type Genre struct {
+	Id       int    `json:"id" db:"id"`
+	Name     string `json:"name" db:"name"`
+	ParentId *int   `json:"parentId" db:"parent_id"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// MusicLink is a link to a song on a streaming or lyrics site. ProviderId
+// is the id the provider gives the song, such as a YouTube video id.
+type MusicLink struct {
+	Id         int    `json:"id" db:"id"`
+	MusicId    int    `json:"musicId" db:"music_id"`
+	Provider   string `json:"provider" db:"provider" example:"youtube"`
+	URL        string `json:"url" db:"url" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	ProviderId string `json:"providerId,omitempty" db:"provider_id" example:"Xsp3_a-PMTw"`
+	LinkCheck
+}
+
+// LinkCheck is the outcome of the last check of a link; CheckedAt is nil
+// for links never checked. CheckError is why no status code was received.
+type LinkCheck struct {
+	StatusCode  int        `json:"statusCode,omitempty" db:"status_code" example:"200"`
+	RedirectURL string     `json:"redirectUrl,omitempty" db:"redirect_url"`
+	CheckError  string     `json:"checkError,omitempty" db:"check_error"`
+	CheckedAt   *time.Time `json:"checkedAt,omitempty" db:"checked_at"`
+}
+
+// BrokenLink is a link whose last check failed, with the song it is for.
+type BrokenLink struct {
+	MusicLink
+	Song  string `json:"song" db:"song"`
+	Group string `json:"group" db:"group"`
+}
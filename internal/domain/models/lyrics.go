@@ -0,0 +1,77 @@
+package models
+
+type LyricSection struct {
+	Position int      `json:"position" db:"position"`
+	Type     string   `json:"type" db:"type"`
+	Ordinal  int      `json:"ordinal" db:"ordinal"`
+	Label    string   `json:"label" db:"label"`
+	Lines    []string `json:"lines" db:"-"`
+}
+
+// Lyrics is the text of a song with its stored sections. Sections are
+// empty for songs added before lyrics were split into sections.
+type Lyrics struct {
+	MusicId int
+	// Lang is the BCP 47 tag of the original text, empty when unknown.
+	Lang     string
+	Text     string
+	Sections []LyricSection
+}
+
+type Translation struct {
+	Lang string `json:"lang" db:"lang"`
+	Text string `json:"text" db:"text"`
+}
+
+type TimedWord struct {
+	StartMs int    `json:"startMs"`
+	Text    string `json:"text"`
+}
+
+type TimedLine struct {
+	StartMs int    `json:"startMs" db:"start_ms"`
+	Text    string `json:"text" db:"text"`
+	// Words is set when word-level timings were uploaded.
+	Words []TimedWord `json:"words,omitempty" db:"-"`
+}
+
+// SyncedLyrics are the timed lines of a song, ordered by start time.
+type SyncedLyrics struct {
+	MusicId int
+	Song    string
+	Group   string
+	Lines   []TimedLine
+}
+
+// LyricMatch is a line of a song containing a searched phrase, with up
+// to the requested number of lines around it, crossing section bounds.
+type LyricMatch struct {
+	MusicId int      `db:"music_id"`
+	Song    string   `db:"song"`
+	Group   string   `db:"group"`
+	Verse   int      `db:"verse"`
+	Line    int      `db:"line"`
+	Text    string   `db:"text"`
+	Before  []string `db:"-"`
+	After   []string `db:"-"`
+}
+
+// LyricStats are the word and line counts of one song or, summed, of the
+// songs of a group.
+type LyricStats struct {
+	Songs         int `db:"songs"`
+	Words         int `db:"words"`
+	UniqueWords   int `db:"unique_words"`
+	Lines         int `db:"lines"`
+	RepeatedLines int `db:"repeated_lines"`
+	LineChars     int `db:"line_chars"`
+	// Counts are all the words of a song when stored, and only the most
+	// frequent words that are not stopwords when read.
+	Counts []WordCount `db:"-"`
+}
+
+type WordCount struct {
+	Word     string `json:"word" db:"word"`
+	Count    int    `json:"count" db:"count"`
+	Stopword bool   `json:"-" db:"stopword"`
+}
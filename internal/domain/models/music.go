@@ -3,10 +3,39 @@ package models
 import "time"
 
 type Music struct {
-	Id          int       `json:"id" db:"id"`
-	Song        string    `json:"song" db:"song"`
+	Id   int    `json:"id" db:"id"`
+	Song string `json:"song" db:"song"`
+	// SongKey is the normalized Song, equal for spellings of one title.
+	SongKey     string    `json:"-" db:"song_key"`
 	Group       Group     `json:"group" db:"group"`
 	Text        string    `json:"text" db:"text_song"`
 	Link        string    `json:"link" db:"link" example:"https://example.com"`
 	ReleaseDate time.Time `json:"releaseDate" db:"release_date" example:"DD.MM.YYYY"`
+	// Lang and Explicit are detected from Text rather than set directly,
+	// unless LangManual tells an editor set Lang by hand.
+	Lang       string `json:"lang" db:"lang"`
+	LangManual bool   `json:"-" db:"lang_manual"`
+	Explicit   bool   `json:"explicit" db:"explicit"`
+	// Album places a newly added song on an album; it is not a music column.
+	Album *AlbumTrack `json:"album,omitempty" db:"-"`
+	// Sections are the parsed Text, stored alongside it.
+	Sections []LyricSection `json:"sections,omitempty" db:"-"`
+	// Stats are computed from Text and cached alongside it.
+	Stats *LyricStats `json:"-" db:"-"`
+	// Links are kept in their own table; Link is among them.
+	Links []MusicLink `json:"links,omitempty" db:"-"`
+	// Cover and GroupImage are the artwork of the song and of its group.
+	Cover      *Artwork `json:"-" db:"-"`
+	GroupImage *Artwork `json:"-" db:"-"`
+	// Audio is the audio file of the song, if one was uploaded.
+	Audio *Audio `json:"-" db:"-"`
+}
+
+// SongTitle is a song as compared when looking for duplicates.
+type SongTitle struct {
+	Id      int    `db:"id"`
+	Song    string `db:"song"`
+	SongKey string `db:"song_key"`
+	GroupId int    `db:"group_id"`
+	Group   string `db:"group"`
 }
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// Credit roles a person can have on a song.
+const (
+	CreditComposer  = "composer"
+	CreditLyricist  = "lyricist"
+	CreditProducer  = "producer"
+	CreditPerformer = "performer"
+)
+
+type Person struct {
+	Id   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+}
+
+type Credit struct {
+	MusicId  int    `json:"musicId" db:"music_id"`
+	Song     string `json:"song" db:"song"`
+	Group    string `json:"group" db:"group"`
+	PersonId int    `json:"personId" db:"person_id"`
+	Person   string `json:"person" db:"person"`
+	Role     string `json:"role" db:"role" example:"composer"`
+}
+
+// Membership is a period a person spent in a group; a nil bound means the
+// period is open or unknown on that side.
+type Membership struct {
+	Id       int        `db:"id"`
+	GroupId  int        `db:"group_id"`
+	Group    string     `db:"group"`
+	PersonId int        `db:"person_id"`
+	Person   string     `db:"person"`
+	From     *time.Time `db:"joined_on"`
+	To       *time.Time `db:"left_on"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type Playlist struct {
+	Id          int             `json:"id" db:"id"`
+	UserId      int             `json:"userId" db:"user_id"`
+	Name        string          `json:"name" db:"name"`
+	PublicToken *string         `json:"publicToken,omitempty" db:"public_token"`
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updatedAt" db:"updated_at"`
+	TrackCount  int             `json:"trackCount" db:"track_count"`
+	Tracks      []PlaylistTrack `json:"tracks" db:"-"`
+}
+
+// PlaylistTrack references a song by id; MusicId is nil once the song is deleted,
+// Song and Group then keep the names it had when it was added.
+type PlaylistTrack struct {
+	Position int       `json:"position" db:"position"`
+	MusicId  *int      `json:"musicId" db:"music_id"`
+	Song     string    `json:"song" db:"song"`
+	Group    string    `json:"group" db:"group_name"`
+	AddedAt  time.Time `json:"addedAt" db:"added_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// Relation types read as "music is <type> related", e.g. a cover_of the original.
+const (
+	RelationCoverOf       = "cover_of"
+	RelationRemixOf       = "remix_of"
+	RelationLiveVersionOf = "live_version_of"
+	RelationSamples       = "samples"
+	RelationTranslationOf = "translation_of"
+)
+
+type Relation struct {
+	MusicId   int    `json:"musicId" db:"music_id"`
+	RelatedId int    `json:"relatedId" db:"related_id"`
+	Type      string `json:"type" db:"type" example:"cover_of"`
+}
+
+// RelationNode is a song of a relation graph; Depth is the number of
+// relations between it and the song the graph was fetched for.
+type RelationNode struct {
+	Id    int    `json:"id" db:"id"`
+	Song  string `json:"song" db:"song"`
+	Group string `json:"group" db:"group"`
+	Depth int    `json:"depth" db:"depth"`
+}
+
+type RelationGraph struct {
+	Nodes []RelationNode `json:"nodes"`
+	Edges []Relation     `json:"edges"`
+}
@@ -0,0 +1,13 @@
+package models
+
+type Tag struct {
+	Id   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+}
+
+// TagCount is the number of songs and groups carrying a tag.
+type TagCount struct {
+	Name   string `json:"name" db:"name"`
+	Songs  int    `json:"songs" db:"songs"`
+	Groups int    `json:"groups" db:"groups"`
+}
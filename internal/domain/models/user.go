@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type User struct {
+	Id           int       `json:"id" db:"id"`
+	Login        string    `json:"login" db:"login"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	Role         Role      `json:"role" db:"role"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+}
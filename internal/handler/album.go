@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/album"
+	"net/http"
+	"strconv"
+)
+
+// @Summary AddAlbum
+// @Tags albums
+// @Description A method for creating an album; an unknown group is created
+// @ID create-album
+// @Accept json
+// @Produce json
+// @Param input body services.AlbumToAdd true "Album info"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/albums/add [post]
+func (h *Handler) AddAlbum(c *gin.Context) {
+	var input services.AlbumToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	id, err := h.service.Album.Add(input)
+	if err != nil {
+		albumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: id,
+	})
+}
+
+// @Summary UpdateAlbum
+// @Tags albums
+// @Description A method for fully updating album parameters
+// @ID update-album
+// @Accept json
+// @Produce json
+// @Param id query int true "Id album"
+// @Param input body services.AlbumToAdd true "Album info"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/albums/update [put]
+func (h *Handler) UpdateAlbum(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.AlbumToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Album.Update(input, id); err != nil {
+		albumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary DeleteAlbum
+// @Tags albums
+// @Description A method for deleting an album; its songs are kept
+// @ID delete-album
+// @Produce json
+// @Param id query int true "Id album"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/albums/delete [delete]
+func (h *Handler) DeleteAlbum(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Album.Delete(id); err != nil {
+		albumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetAlbum
+// @Tags albums
+// @Description A method for getting an album with its track listing
+// @ID get-album
+// @Produce json
+// @Param id query int true "Id album"
+// @Success 200 {object} services.AlbumToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/albums/get [get]
+func (h *Handler) GetAlbum(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Album.Get(id)
+	if err != nil {
+		albumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary GetAllAlbums
+// @Tags albums
+// @Description A method for getting albums, optionally of one group
+// @ID get-all-albums
+// @Produce json
+// @Param page query int true "Page number"
+// @Param countAlbums query int true "Count albums"
+// @Param group query string false "Music group"
+// @Success 200 {object} responses.SuccessAlbums
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/albums/getAll [get]
+func (h *Handler) GetAllAlbums(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	countAlbums, err := strconv.Atoi(c.Query("countAlbums"))
+	if err != nil || countAlbums < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	albums, err := h.service.Album.GetAll(c.Query("group"), countAlbums, page)
+	if err != nil {
+		albumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessAlbums{
+		Albums: albums,
+	})
+}
+
+// @Summary AddAlbumTrack
+// @Tags albums
+// @Description A method for placing an existing song on an album
+// @ID add-album-track
+// @Accept json
+// @Produce json
+// @Param id query int true "Id album"
+// @Param input body services.AlbumTrackToAdd true "Song and track position"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/albums/addTrack [post]
+func (h *Handler) AddAlbumTrack(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.AlbumTrackToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Album.AddTrack(id, input); err != nil {
+		albumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary RemoveAlbumTrack
+// @Tags albums
+// @Description A method for removing a song from an album
+// @ID remove-album-track
+// @Produce json
+// @Param id query int true "Id album"
+// @Param musicId query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/albums/removeTrack [delete]
+func (h *Handler) RemoveAlbumTrack(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	musicId, err := strconv.Atoi(c.Query("musicId"))
+	if err != nil || musicId < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidID)
+		return
+	}
+
+	if err = h.service.Album.RemoveTrack(id, musicId); err != nil {
+		albumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func albumError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, album.ErrAlbumNotFound), errors.Is(err, album.ErrMusicNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, album.ErrTrackTaken):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
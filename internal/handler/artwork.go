@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services/artwork"
+	"library-music/pkg/blob"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// maxImageSize bounds uploaded images.
+const maxImageSize = 10 << 20
+
+var (
+	ErrUnsupportedImage = responses.Error{Code: "unsupported_image", Message: "file is not a jpeg, png, gif or webp image"}
+	ErrImageTooLarge    = responses.Error{Code: "image_too_large", Message: "image has too many pixels"}
+)
+
+// @Summary UploadMusicCover
+// @Tags artwork
+// @Description A method for uploading the cover of a song, replacing its cover; thumbnails are made in several sizes
+// @ID upload-music-cover
+// @Accept mpfd
+// @Produce json
+// @Param id query int true "Id song"
+// @Param file formData file true "JPEG, PNG, GIF or WebP image, up to 10 MB"
+// @Success 200 {object} services.ImageToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/artwork/music [post]
+func (h *Handler) UploadMusicCover(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	data, ok := formFile(c, maxImageSize)
+	if !ok {
+		return
+	}
+
+	image, err := h.service.Artwork.SetForMusic(id, data)
+	if err != nil {
+		artworkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// @Summary DeleteMusicCover
+// @Tags artwork
+// @Description A method for deleting the cover of a song
+// @ID delete-music-cover
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/artwork/music [delete]
+func (h *Handler) DeleteMusicCover(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Artwork.DeleteForMusic(id); err != nil {
+		artworkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary UploadGroupImage
+// @Tags artwork
+// @Description A method for uploading the image of a group, replacing its image; thumbnails are made in several sizes
+// @ID upload-group-image
+// @Accept mpfd
+// @Produce json
+// @Param id query int true "Id group"
+// @Param file formData file true "JPEG, PNG, GIF or WebP image, up to 10 MB"
+// @Success 200 {object} services.ImageToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/artwork/group [post]
+func (h *Handler) UploadGroupImage(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	data, ok := formFile(c, maxImageSize)
+	if !ok {
+		return
+	}
+
+	image, err := h.service.Artwork.SetForGroup(id, data)
+	if err != nil {
+		artworkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// @Summary DeleteGroupImage
+// @Tags artwork
+// @Description A method for deleting the image of a group
+// @ID delete-group-image
+// @Produce json
+// @Param id query int true "Id group"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/artwork/group [delete]
+func (h *Handler) DeleteGroupImage(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Artwork.DeleteForGroup(id); err != nil {
+		artworkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetMedia
+// @Tags artwork
+// @Description A method for getting a stored image or thumbnail linked from a song or group
+// @ID get-media
+// @Produce octet-stream
+// @Param key path string true "Key of the file"
+// @Success 200 {file} file
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /api/public/media/{key} [get]
+func (h *Handler) GetMedia(c *gin.Context) {
+	// Audio shares the store but is only streamed to readers.
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if !strings.HasPrefix(key, artwork.KeyPrefix) {
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+		return
+	}
+	file, info, err := h.service.Media.Open(key)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) || errors.Is(err, blob.ErrInvalidKey) {
+			responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+			return
+		}
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+	defer file.Close()
+
+	// Keys are never reused for other content, so files can be cached for good.
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime, file)
+}
+
+func artworkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, artwork.ErrMusicNotFound), errors.Is(err, artwork.ErrGroupNotFound),
+		errors.Is(err, artwork.ErrArtworkNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, artwork.ErrUnsupportedFormat):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrUnsupportedImage)
+	case errors.Is(err, artwork.ErrImageTooLarge):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrImageTooLarge)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"library-music/pkg/blob"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetMediaServesOnlyArtwork(t *testing.T) {
+	store, err := blob.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"artwork/3f2a/original.png", "audio/3f2a.mp3"} {
+		if err = store.Put(key, strings.NewReader(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &Service{Media: store})
+	router := gin.New()
+	router.GET("/media/*key", h.GetMedia)
+
+	tests := []struct {
+		key    string
+		status int
+	}{
+		{key: "artwork/3f2a/original.png", status: http.StatusOK},
+		{key: "artwork/3f2a/missing.jpg", status: http.StatusNotFound},
+		{key: "audio/3f2a.mp3", status: http.StatusNotFound},
+		{key: "artwork/../audio/3f2a.mp3", status: http.StatusNotFound},
+		{key: "artwork", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/media/"+tt.key, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("GET %s status = %d, want %d", tt.key, rec.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.key {
+				t.Errorf("GET %s body = %q, want %q", tt.key, rec.Body.String(), tt.key)
+			}
+		})
+	}
+}
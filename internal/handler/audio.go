@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services/audio"
+	"library-music/internal/services/tagimport"
+	"net/http"
+	"path"
+)
+
+// maxAudioSize bounds uploaded audio files.
+const maxAudioSize = 200 << 20
+
+var (
+	ErrUnsupportedAudio = responses.Error{Code: "unsupported_audio", Message: "file is not an mp3, aac, m4a, flac, ogg or wav file"}
+	ErrNoAudioTags      = responses.Error{Code: "no_audio_tags", Message: "file has no readable ID3v2 tags or Vorbis comments"}
+	ErrMissingAudioTags = responses.Error{Code: "missing_audio_tags", Message: "tags need a title, an artist and a year or a known album to add the song"}
+)
+
+// @Summary UploadAudio
+// @Tags audio
+// @Description A method for uploading the audio file of a song, replacing its audio file; the type is detected from the content
+// @ID upload-audio
+// @Accept mpfd
+// @Produce json
+// @Param id query int true "Id song"
+// @Param file formData file true "MP3, AAC, M4A, FLAC, Ogg or WAV file, up to 200 MB"
+// @Success 200 {object} services.AudioToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/audio/upload [post]
+func (h *Handler) UploadAudio(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	file, ok := openFormFile(c, maxAudioSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	uploaded, err := h.service.Audio.Upload(id, file)
+	if err != nil {
+		audioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, uploaded)
+}
+
+// @Summary ImportAudio
+// @Tags audio
+// @Description A method for importing a tagged MP3, FLAC or Ogg file: the song and group named by its tags are created when missing, fields the song lacks are filled in from the tags, and tags differing from stored fields are reported as conflicts rather than applied. The file is stored as the audio of the song, unless the song already has audio, which is kept and reported as a conflict
+// @ID import-audio
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "MP3 with ID3v2 tags, or FLAC or Ogg with Vorbis comments, up to 200 MB"
+// @Success 200 {object} services.ImportResult
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/audio/import [post]
+func (h *Handler) ImportAudio(c *gin.Context) {
+	file, ok := openFormFile(c, maxAudioSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	res, err := h.service.TagImport.Import(file)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary DeleteAudio
+// @Tags audio
+// @Description A method for deleting the audio file of a song
+// @ID delete-audio
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/audio/delete [delete]
+func (h *Handler) DeleteAudio(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Audio.Delete(id); err != nil {
+		audioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary StreamAudio
+// @Tags audio
+// @Description A method for streaming the audio file of a song; byte ranges are supported for seeking
+// @ID stream-audio
+// @Produce audio/mpeg
+// @Param id query int true "Id song"
+// @Param Range header string false "Byte range, such as bytes=0-1023"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 416 {string} string
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/audio/stream [get]
+func (h *Handler) StreamAudio(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	file, stored, info, err := h.service.Audio.Open(id)
+	if err != nil {
+		audioError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// ServeContent answers Range and If-Range requests, and keeps the
+	// stored type instead of guessing one from the content.
+	c.Header("Content-Type", stored.ContentType)
+	c.Header("Accept-Ranges", "bytes")
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, path.Base(stored.Key), info.ModTime, file)
+}
+
+func audioError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, audio.ErrMusicNotFound), errors.Is(err, audio.ErrAudioNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, audio.ErrUnsupportedFormat):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrUnsupportedAudio)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
+
+func importError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tagimport.ErrNoTags):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrNoAudioTags)
+	case errors.Is(err, tagimport.ErrMissingTags):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrMissingAudioTags)
+	case errors.Is(err, tagimport.ErrUnsupportedFormat):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrUnsupportedAudio)
+	case errors.Is(err, tagimport.ErrMusicAlreadyExists):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services/music"
+	"net/http"
+	"strconv"
+)
+
+// maxChordProSize bounds uploaded ChordPro documents.
+const maxChordProSize = 1 << 20
+
+var ErrInvalidChordPro = responses.Error{Code: "invalid_chordpro", Message: "file is not valid chordpro"}
+
+var chordProContentTypes = map[string]string{
+	music.FormatLyrics:   "text/plain; charset=utf-8",
+	music.FormatSheet:    "text/plain; charset=utf-8",
+	music.FormatHTML:     "text/html; charset=utf-8",
+	music.FormatChordPro: "text/plain; charset=utf-8",
+}
+
+// @Summary UploadChordPro
+// @Tags chordpro
+// @Description A method for uploading a ChordPro document of a song; its plain lyrics replace the song text
+// @ID upload-chordpro
+// @Accept mpfd
+// @Produce json
+// @Param id query int true "Id song"
+// @Param file formData file true "ChordPro file"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/chordpro/upload [post]
+func (h *Handler) UploadChordPro(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	data, ok := formFile(c, maxChordProSize)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Music.SetChordPro(id, string(data)); err != nil {
+		chordProError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary RenderChordPro
+// @Tags chordpro
+// @Description A method for rendering the ChordPro document of a song as plain lyrics, a text or HTML chord sheet, or ChordPro, optionally transposed and arranged for a capo
+// @ID render-chordpro
+// @Produce plain,html
+// @Param id query int true "Id song"
+// @Param format query string false "lyrics, text (default), html or chordpro"
+// @Param transpose query int false "Semitones to transpose by, may be negative"
+// @Param capo query int false "Capo fret to arrange the chords for, 0 to 11"
+// @Success 200 {string} string
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/chordpro/render [get]
+func (h *Handler) RenderChordPro(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	transpose, err := strconv.Atoi(c.DefaultQuery("transpose", "0"))
+	if err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	capo := -1
+	if v := c.Query("capo"); v != "" {
+		if capo, err = strconv.Atoi(v); err != nil || capo < 0 {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	format := c.DefaultQuery("format", music.FormatSheet)
+	text, err := h.service.Music.RenderChordPro(id, format, transpose, capo)
+	if err != nil {
+		chordProError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, chordProContentTypes[format], []byte(text))
+}
+
+// @Summary DeleteChordPro
+// @Tags chordpro
+// @Description A method for deleting the ChordPro document of a song; the song text is kept
+// @ID delete-chordpro
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/chordpro/delete [delete]
+func (h *Handler) DeleteChordPro(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Music.DeleteChordPro(id); err != nil {
+		chordProError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func chordProError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, music.ErrMusicNotFound), errors.Is(err, music.ErrChordProNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, music.ErrInvalidChordPro):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidChordPro)
+	case errors.Is(err, music.ErrUnsupportedFormat):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrUnsupportedFormat)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/music"
+	"net/http"
+	"strconv"
+)
+
+var ErrDifferentGroups = responses.Error{Code: "different_groups", Message: "songs to merge are of different groups"}
+
+const (
+	defaultDuplicateScore = 0.8
+	defaultDuplicateLimit = 50
+)
+
+// @Summary GetDuplicates
+// @Tags music
+// @Description A method for listing probable duplicate songs of the same group, comparing titles without case, punctuation, diacritics, featured artists and remaster notes
+// @ID get-duplicates
+// @Produce json
+// @Param minScore query number false "Lowest similarity of titles, from 0 to 1" default(0.8)
+// @Param limit query int false "Count pairs" default(50)
+// @Success 200 {object} responses.SuccessDuplicates
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/duplicates [get]
+func (h *Handler) GetDuplicates(c *gin.Context) {
+	minScore, err := strconv.ParseFloat(c.DefaultQuery("minScore", strconv.FormatFloat(defaultDuplicateScore, 'f', -1, 64)), 64)
+	if err != nil || minScore < 0 || minScore > 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultDuplicateLimit)))
+	if err != nil || limit < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	pairs, err := h.service.Music.GetDuplicates(minScore, limit)
+	if err != nil {
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessDuplicates{
+		Duplicates: pairs,
+	})
+}
+
+// @Summary MergeMusic
+// @Tags music
+// @Description A method for merging a duplicate song of the same group into another one, taking the chosen fields from the duplicate; playlists, favorites, albums, tags, genres, credits, translations and relations are moved to the song kept
+// @ID merge-music
+// @Accept json
+// @Produce json
+// @Param input body services.MusicToMerge true "Songs to merge"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/merge [post]
+func (h *Handler) MergeMusic(c *gin.Context) {
+	var input services.MusicToMerge
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Music.Merge(input); err != nil {
+		switch {
+		case errors.Is(err, music.ErrMusicNotFound):
+			responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+			return
+		case errors.Is(err, music.ErrDifferentGroups):
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrDifferentGroups)
+			return
+		case errors.Is(err, music.ErrMusicAlreadyExists):
+			responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+			return
+		}
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/genre"
+	"net/http"
+)
+
+var (
+	ErrGenreInUse = responses.Error{Code: "genre_in_use", Message: "genre has subgenres"}
+	ErrGenreCycle = responses.Error{Code: "genre_cycle", Message: "genre cannot be nested under itself"}
+)
+
+// @Summary AddGenre
+// @Tags genres
+// @Description A method for adding a genre, optionally as a subgenre of another one
+// @ID create-genre
+// @Accept json
+// @Produce json
+// @Param input body services.GenreToAdd true "Genre info"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/genres/add [post]
+func (h *Handler) AddGenre(c *gin.Context) {
+	var input services.GenreToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	id, err := h.service.Genre.Add(input)
+	if err != nil {
+		genreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: id,
+	})
+}
+
+// @Summary UpdateGenre
+// @Tags genres
+// @Description A method for renaming a genre or moving it in the hierarchy
+// @ID update-genre
+// @Accept json
+// @Produce json
+// @Param id query int true "Id genre"
+// @Param input body services.GenreToAdd true "Genre info"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/genres/update [put]
+func (h *Handler) UpdateGenre(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.GenreToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Genre.Update(input, id); err != nil {
+		genreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary DeleteGenre
+// @Tags genres
+// @Description A method for deleting a genre without subgenres
+// @ID delete-genre
+// @Produce json
+// @Param id query int true "Id genre"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/genres/delete [delete]
+func (h *Handler) DeleteGenre(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Genre.Delete(id); err != nil {
+		genreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetGenres
+// @Tags genres
+// @Description A method for getting the genre hierarchy
+// @ID get-genres
+// @Produce json
+// @Success 200 {object} responses.SuccessGenres
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/genres/getAll [get]
+func (h *Handler) GetGenres(c *gin.Context) {
+	genres, err := h.service.Genre.GetTree()
+	if err != nil {
+		genreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessGenres{
+		Genres: genres,
+	})
+}
+
+// @Summary SetSongGenres
+// @Tags genres
+// @Description A method for replacing the genres of a song
+// @ID set-song-genres
+// @Accept json
+// @Produce json
+// @Param id query int true "Id song"
+// @Param input body services.MusicGenresToSet true "Genre ids"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/genres/setForSong [put]
+func (h *Handler) SetSongGenres(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.MusicGenresToSet
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Genre.SetMusicGenres(id, input.GenreIds); err != nil {
+		genreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func genreError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, genre.ErrGenreNotFound), errors.Is(err, genre.ErrMusicNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, genre.ErrGenreAlreadyExists):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+	case errors.Is(err, genre.ErrGenreInUse):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrGenreInUse)
+	case errors.Is(err, genre.ErrGenreCycle):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrGenreCycle)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
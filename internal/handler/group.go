@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/group"
+	"net/http"
+)
+
+// @Summary AddGroupAlias
+// @Tags groups
+// @Description A method for adding another name a group is known by; songs and albums can then be looked up by it, in any case
+// @ID add-group-alias
+// @Accept json
+// @Produce json
+// @Param id query int true "Id group"
+// @Param input body services.AliasToAdd true "Alias"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/groups/addAlias [post]
+func (h *Handler) AddGroupAlias(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.AliasToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Group.AddAlias(id, input.Name); err != nil {
+		groupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary RemoveGroupAlias
+// @Tags groups
+// @Description A method for removing an alias of a group
+// @ID remove-group-alias
+// @Produce json
+// @Param name query string true "Alias"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/groups/removeAlias [delete]
+func (h *Handler) RemoveGroupAlias(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	if err := h.service.Group.RemoveAlias(name); err != nil {
+		groupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetGroupAliases
+// @Tags groups
+// @Description A method for getting the aliases of a group
+// @ID get-group-aliases
+// @Produce json
+// @Param id query int true "Id group"
+// @Success 200 {object} responses.SuccessAliases
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/groups/aliases [get]
+func (h *Handler) GetGroupAliases(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	aliases, err := h.service.Group.GetAliases(id)
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessAliases{
+		Aliases: aliases,
+	})
+}
+
+// @Summary MergeGroups
+// @Tags groups
+// @Description A method for merging a duplicate group into another one: its songs, albums, tags, members and aliases are moved, and its name becomes an alias
+// @ID merge-groups
+// @Accept json
+// @Produce json
+// @Param input body services.GroupsToMerge true "Group to merge and group to keep"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/groups/merge [post]
+func (h *Handler) MergeGroups(c *gin.Context) {
+	var input services.GroupsToMerge
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Group.Merge(input.FromId, input.ToId); err != nil {
+		groupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func groupError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, group.ErrGroupNotFound), errors.Is(err, group.ErrAliasNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, group.ErrAliasAlreadyExists):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+	case errors.Is(err, group.ErrMergeIntoSelf):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -5,32 +5,181 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "library-music/docs"
+	"library-music/internal/domain/models"
+	"log/slog"
 )
 
 type Handler struct {
+	log     *slog.Logger
 	service *Service
 }
 
-func NewHandler(service *Service) *Handler {
+func NewHandler(log *slog.Logger, service *Service) *Handler {
 	return &Handler{
+		log:     log,
 		service: service,
 	}
 }
 
 func (h *Handler) InitRouter() *gin.Engine {
 	router := gin.New()
+	router.Use(requestID(), h.logRequests())
 
 	router.GET("swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	api := router.Group("/api")
+	accounts := router.Group("/api/auth")
 	{
-		api.POST("/add", h.AddMusic)
-		api.PUT("/update", h.UpdateMusic)
-		api.PATCH("/update", h.UpdatePartialMusic)
-		api.DELETE("/delete", h.DeleteMusic)
-		api.GET("/getMusic", h.GetMusic)
-		api.GET("/getAllMusic", h.GetAllMusic)
-		api.GET("/getTextMusic", h.GetTextMusic)
+		accounts.POST("/register", h.Register)
+		accounts.POST("/login", h.Login)
+		accounts.POST("/logout", h.authenticate(), h.Logout)
+	}
+
+	public := router.Group("/api/public")
+	{
+		public.GET("/playlist", h.GetSharedPlaylist)
+		public.GET("/media/*key", h.GetMedia)
+	}
+
+	api := router.Group("/api", h.authenticate())
+	{
+		api.POST("/add", requireRole(models.RoleEditor), h.AddMusic)
+		api.PUT("/update", requireRole(models.RoleEditor), h.UpdateMusic)
+		api.PATCH("/update", requireRole(models.RoleEditor), h.UpdatePartialMusic)
+		api.DELETE("/delete", requireRole(models.RoleAdmin), h.DeleteMusic)
+		api.GET("/getMusic", requireRole(models.RoleReader), h.GetMusic)
+		api.GET("/getAllMusic", requireRole(models.RoleReader), h.GetAllMusic)
+		api.GET("/getTextMusic", requireRole(models.RoleReader), h.GetTextMusic)
+		api.GET("/getLyricsStructure", requireRole(models.RoleReader), h.GetLyricsStructure)
+		api.GET("/searchLyrics", requireRole(models.RoleReader), h.SearchLyrics)
+		api.GET("/getLyricsStats", requireRole(models.RoleReader), h.GetLyricsStats)
+		api.GET("/getGroupLyricsStats", requireRole(models.RoleReader), h.GetGroupLyricsStats)
+		api.GET("/duplicates", requireRole(models.RoleEditor), h.GetDuplicates)
+		api.POST("/merge", requireRole(models.RoleAdmin), h.MergeMusic)
+
+		me := api.Group("/me", requireRole(models.RoleReader))
+		{
+			me.GET("/favorites", h.GetFavorites)
+			me.POST("/favorites", h.AddFavorite)
+			me.DELETE("/favorites", h.RemoveFavorite)
+			me.GET("/library", h.GetLibrary)
+			me.POST("/library", h.AddToLibrary)
+			me.DELETE("/library", h.RemoveFromLibrary)
+		}
+
+		playlists := api.Group("/playlists", requireRole(models.RoleReader))
+		{
+			playlists.POST("/create", h.CreatePlaylist)
+			playlists.GET("/getAll", h.GetPlaylists)
+			playlists.GET("/get", h.GetPlaylist)
+			playlists.PATCH("/rename", h.RenamePlaylist)
+			playlists.DELETE("/delete", h.DeletePlaylist)
+			playlists.POST("/addTrack", h.AddPlaylistTrack)
+			playlists.DELETE("/removeTrack", h.RemovePlaylistTrack)
+			playlists.PATCH("/moveTrack", h.MovePlaylistTrack)
+			playlists.POST("/duplicate", h.DuplicatePlaylist)
+			playlists.POST("/share", h.SharePlaylist)
+			playlists.DELETE("/share", h.UnsharePlaylist)
+		}
+
+		albums := api.Group("/albums")
+		{
+			albums.POST("/add", requireRole(models.RoleEditor), h.AddAlbum)
+			albums.PUT("/update", requireRole(models.RoleEditor), h.UpdateAlbum)
+			albums.DELETE("/delete", requireRole(models.RoleAdmin), h.DeleteAlbum)
+			albums.GET("/get", requireRole(models.RoleReader), h.GetAlbum)
+			albums.GET("/getAll", requireRole(models.RoleReader), h.GetAllAlbums)
+			albums.POST("/addTrack", requireRole(models.RoleEditor), h.AddAlbumTrack)
+			albums.DELETE("/removeTrack", requireRole(models.RoleEditor), h.RemoveAlbumTrack)
+		}
+
+		lyrics := api.Group("/lyrics")
+		{
+			lyrics.POST("/uploadLRC", requireRole(models.RoleEditor), h.UploadLRC)
+			lyrics.GET("/synced", requireRole(models.RoleReader), h.GetSyncedLyrics)
+			lyrics.DELETE("/deleteSynced", requireRole(models.RoleEditor), h.DeleteSyncedLyrics)
+			lyrics.PUT("/language", requireRole(models.RoleEditor), h.SetSongLanguage)
+			lyrics.PUT("/translation", requireRole(models.RoleEditor), h.SetTranslation)
+			lyrics.DELETE("/translation", requireRole(models.RoleEditor), h.DeleteTranslation)
+			lyrics.GET("/translations", requireRole(models.RoleReader), h.GetTranslations)
+		}
+
+		chords := api.Group("/chordpro")
+		{
+			chords.POST("/upload", requireRole(models.RoleEditor), h.UploadChordPro)
+			chords.GET("/render", requireRole(models.RoleReader), h.RenderChordPro)
+			chords.DELETE("/delete", requireRole(models.RoleEditor), h.DeleteChordPro)
+		}
+
+		genres := api.Group("/genres")
+		{
+			genres.POST("/add", requireRole(models.RoleEditor), h.AddGenre)
+			genres.PUT("/update", requireRole(models.RoleEditor), h.UpdateGenre)
+			genres.DELETE("/delete", requireRole(models.RoleAdmin), h.DeleteGenre)
+			genres.GET("/getAll", requireRole(models.RoleReader), h.GetGenres)
+			genres.PUT("/setForSong", requireRole(models.RoleEditor), h.SetSongGenres)
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.POST("/addToSong", requireRole(models.RoleEditor), h.AddSongTags)
+			tags.DELETE("/removeFromSong", requireRole(models.RoleEditor), h.RemoveSongTag)
+			tags.POST("/addToGroup", requireRole(models.RoleEditor), h.AddGroupTags)
+			tags.DELETE("/removeFromGroup", requireRole(models.RoleEditor), h.RemoveGroupTag)
+			tags.GET("/counts", requireRole(models.RoleReader), h.GetTagCounts)
+		}
+
+		links := api.Group("/links")
+		{
+			links.POST("/add", requireRole(models.RoleEditor), h.AddLink)
+			links.DELETE("/delete", requireRole(models.RoleEditor), h.DeleteLink)
+			links.GET("/get", requireRole(models.RoleReader), h.GetLinks)
+			links.POST("/check", requireRole(models.RoleEditor), h.CheckLinks)
+			links.GET("/broken", requireRole(models.RoleEditor), h.GetBrokenLinks)
+		}
+
+		artwork := api.Group("/artwork", requireRole(models.RoleEditor))
+		{
+			artwork.POST("/music", h.UploadMusicCover)
+			artwork.DELETE("/music", h.DeleteMusicCover)
+			artwork.POST("/group", h.UploadGroupImage)
+			artwork.DELETE("/group", h.DeleteGroupImage)
+		}
+
+		audio := api.Group("/audio")
+		{
+			audio.POST("/upload", requireRole(models.RoleEditor), h.UploadAudio)
+			audio.POST("/import", requireRole(models.RoleEditor), h.ImportAudio)
+			audio.DELETE("/delete", requireRole(models.RoleEditor), h.DeleteAudio)
+			audio.GET("/stream", requireRole(models.RoleReader), h.StreamAudio)
+		}
+
+		relations := api.Group("/relations")
+		{
+			relations.POST("/link", requireRole(models.RoleEditor), h.LinkSongs)
+			relations.DELETE("/unlink", requireRole(models.RoleEditor), h.UnlinkSongs)
+			relations.GET("/graph", requireRole(models.RoleReader), h.GetRelationGraph)
+		}
+
+		groups := api.Group("/groups")
+		{
+			groups.POST("/addAlias", requireRole(models.RoleEditor), h.AddGroupAlias)
+			groups.DELETE("/removeAlias", requireRole(models.RoleEditor), h.RemoveGroupAlias)
+			groups.GET("/aliases", requireRole(models.RoleReader), h.GetGroupAliases)
+			groups.POST("/merge", requireRole(models.RoleAdmin), h.MergeGroups)
+		}
+
+		people := api.Group("/people")
+		{
+			people.POST("/add", requireRole(models.RoleEditor), h.AddPerson)
+			people.DELETE("/delete", requireRole(models.RoleAdmin), h.DeletePerson)
+			people.GET("/get", requireRole(models.RoleReader), h.GetPerson)
+			people.POST("/addCredit", requireRole(models.RoleEditor), h.AddCredit)
+			people.DELETE("/removeCredit", requireRole(models.RoleEditor), h.RemoveCredit)
+			people.GET("/credits", requireRole(models.RoleReader), h.GetCredits)
+			people.POST("/addMember", requireRole(models.RoleEditor), h.AddGroupMember)
+			people.DELETE("/removeMember", requireRole(models.RoleEditor), h.RemoveGroupMember)
+			people.GET("/members", requireRole(models.RoleReader), h.GetGroupMembers)
+		}
 	}
 
 	return router
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/linkhealth"
+	"library-music/internal/services/music"
+	"net/http"
+	"strconv"
+)
+
+var (
+	ErrInvalidLink      = responses.Error{Code: "invalid_link", Message: "link is not a link to a song"}
+	ErrProviderMismatch = responses.Error{Code: "provider_mismatch", Message: "link does not point to the provider"}
+	ErrCheckRunning     = responses.Error{Code: "check_running", Message: "a check of every link is already running"}
+)
+
+// @Summary AddLink
+// @Tags links
+// @Description A method for adding a link to a song on YouTube, Spotify, Apple Music, Bandcamp, a lyrics site or elsewhere; the provider is detected from the link when omitted
+// @ID add-link
+// @Accept json
+// @Produce json
+// @Param id query int true "Id song"
+// @Param input body services.LinkToAdd true "Link and its provider"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/links/add [post]
+func (h *Handler) AddLink(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.LinkToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	linkId, err := h.service.Music.AddLink(id, input)
+	if err != nil {
+		linkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: linkId,
+	})
+}
+
+// @Summary DeleteLink
+// @Tags links
+// @Description A method for deleting a link of a song
+// @ID delete-link
+// @Produce json
+// @Param id query int true "Id song"
+// @Param linkId query int true "Id link"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/links/delete [delete]
+func (h *Handler) DeleteLink(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	linkId, err := strconv.Atoi(c.Query("linkId"))
+	if err != nil || linkId < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidID)
+		return
+	}
+
+	if err = h.service.Music.DeleteLink(id, linkId); err != nil {
+		linkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetLinks
+// @Tags links
+// @Description A method for getting the links of a song with their providers and provider ids
+// @ID get-links
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessLinks
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/links/get [get]
+func (h *Handler) GetLinks(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.service.Music.GetLinks(id)
+	if err != nil {
+		linkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessLinks{
+		Links: links,
+	})
+}
+
+// @Summary CheckLinks
+// @Tags links
+// @Description A method for checking whether links still work. With id, the links of the song are checked and returned; without it, every link is checked in the background
+// @ID check-links
+// @Produce json
+// @Param id query int false "Id song"
+// @Success 200 {object} responses.SuccessLinks
+// @Success 202 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/links/check [post]
+func (h *Handler) CheckLinks(c *gin.Context) {
+	if c.Query("id") == "" {
+		if err := h.service.LinkHealth.Start(); err != nil {
+			linkHealthError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, responses.SuccessStatus{
+			Status: "started",
+		})
+		return
+	}
+
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.service.LinkHealth.CheckMusic(c.Request.Context(), id)
+	if err != nil {
+		linkHealthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessLinks{
+		Links: links,
+	})
+}
+
+// @Summary GetBrokenLinks
+// @Tags links
+// @Description A method for getting the links whose last check failed or answered with an error status, with their songs
+// @ID get-broken-links
+// @Produce json
+// @Param page query int true "Page number"
+// @Param countLinks query int true "Count links"
+// @Success 200 {object} responses.SuccessBrokenLinks
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/links/broken [get]
+func (h *Handler) GetBrokenLinks(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	countLinks, err := strconv.Atoi(c.Query("countLinks"))
+	if err != nil || countLinks < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	links, err := h.service.LinkHealth.GetBroken(countLinks, page)
+	if err != nil {
+		linkHealthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessBrokenLinks{
+		Links: links,
+	})
+}
+
+func linkHealthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, linkhealth.ErrLinkNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, linkhealth.ErrCheckRunning):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrCheckRunning)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
+
+func linkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, music.ErrMusicNotFound), errors.Is(err, music.ErrLinkNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, music.ErrLinkAlreadyExists):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+	case errors.Is(err, music.ErrInvalidLink):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidLink)
+	case errors.Is(err, music.ErrProviderMismatch):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrProviderMismatch)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/lyrics"
+	"net/http"
+)
+
+// maxLRCSize bounds uploaded LRC files; real ones stay well below it.
+const maxLRCSize = 1 << 20
+
+var (
+	ErrInvalidLanguage   = responses.Error{Code: "invalid_language", Message: "invalid BCP 47 language tag"}
+	ErrInvalidLRC        = responses.Error{Code: "invalid_lrc", Message: "file is not valid lrc"}
+	ErrUnsupportedFormat = responses.Error{Code: "unsupported_format", Message: "unsupported format"}
+)
+
+var lyricsContentTypes = map[string]string{
+	lyrics.FormatLRC: "text/plain; charset=utf-8",
+	lyrics.FormatVTT: "text/vtt; charset=utf-8",
+}
+
+// @Summary UploadLRC
+// @Tags lyrics
+// @Description A method for uploading line or word-level (enhanced) LRC timings of a song, replacing previous ones
+// @ID upload-lrc
+// @Accept mpfd
+// @Produce json
+// @Param id query int true "Id song"
+// @Param file formData file true "LRC file"
+// @Success 200 {object} responses.SuccessLines
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/lyrics/uploadLRC [post]
+func (h *Handler) UploadLRC(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	data, ok := formFile(c, maxLRCSize)
+	if !ok {
+		return
+	}
+
+	lines, err := h.service.Lyrics.UploadLRC(id, string(data))
+	if err != nil {
+		lyricsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessLines{
+		Lines: lines,
+	})
+}
+
+// @Summary GetSyncedLyrics
+// @Tags lyrics
+// @Description A method for getting the timed lyrics of a song as JSON, LRC or WebVTT
+// @ID get-synced-lyrics
+// @Produce json,plain
+// @Param id query int true "Id song"
+// @Param format query string false "json (default), lrc or vtt"
+// @Success 200 {object} services.SyncedLyricsToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/lyrics/synced [get]
+func (h *Handler) GetSyncedLyrics(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format == "json" {
+		res, err := h.service.Lyrics.GetSynced(id)
+		if err != nil {
+			lyricsError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	text, err := h.service.Lyrics.ExportSynced(id, format)
+	if err != nil {
+		lyricsError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, lyricsContentTypes[format], []byte(text))
+}
+
+// @Summary DeleteSyncedLyrics
+// @Tags lyrics
+// @Description A method for deleting the timed lyrics of a song; the text is kept
+// @ID delete-synced-lyrics
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/lyrics/deleteSynced [delete]
+func (h *Handler) DeleteSyncedLyrics(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Lyrics.DeleteTimings(id); err != nil {
+		lyricsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func lyricsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, lyrics.ErrMusicNotFound), errors.Is(err, lyrics.ErrLyricsNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, lyrics.ErrInvalidLRC):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidLRC)
+	case errors.Is(err, lyrics.ErrUnsupportedFormat):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrUnsupportedFormat)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
+
+// @Summary SetSongLanguage
+// @Tags lyrics
+// @Description A method for setting the BCP 47 language tag of the original song text by hand, which later text edits keep instead of detecting the language; an empty tag clears it
+// @ID set-song-language
+// @Accept json
+// @Produce json
+// @Param id query int true "Id song"
+// @Param input body services.LanguageToSet true "Language"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/lyrics/language [put]
+func (h *Handler) SetSongLanguage(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.LanguageToSet
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Music.SetLanguage(id, input.Lang); err != nil {
+		textError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary SetTranslation
+// @Tags lyrics
+// @Description A method for adding a translation of the song text, replacing any in the same language
+// @ID set-translation
+// @Accept json
+// @Produce json
+// @Param id query int true "Id song"
+// @Param input body services.TranslationToSet true "Translation"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/lyrics/translation [put]
+func (h *Handler) SetTranslation(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.TranslationToSet
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Music.SetTranslation(id, input); err != nil {
+		textError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary DeleteTranslation
+// @Tags lyrics
+// @Description A method for deleting a translation of the song text
+// @ID delete-translation
+// @Produce json
+// @Param id query int true "Id song"
+// @Param lang query string true "BCP 47 tag of the translation"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/lyrics/translation [delete]
+func (h *Handler) DeleteTranslation(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Music.DeleteTranslation(id, c.Query("lang")); err != nil {
+		textError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetTranslations
+// @Tags lyrics
+// @Description A method for getting all translations of the song text
+// @ID get-translations
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessTranslations
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/lyrics/translations [get]
+func (h *Handler) GetTranslations(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	translations, err := h.service.Music.GetTranslations(id)
+	if err != nil {
+		textError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessTranslations{
+		Translations: translations,
+	})
+}
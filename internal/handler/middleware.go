@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/domain/models"
+	"library-music/internal/handler/responses"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// requestID propagates the caller's X-Request-ID or assigns a new one,
+// so that problem responses can be correlated with server logs.
+func requestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+
+		c.Set(responses.RequestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+const (
+	apiKeyHeader = "X-API-Key"
+	principalKey = "principal"
+)
+
+var (
+	ErrUnauthenticated = responses.Error{Code: "unauthenticated", Message: "authentication required"}
+	ErrForbidden       = responses.Error{Code: "forbidden", Message: "insufficient role"}
+)
+
+// authenticate resolves the caller from an X-API-Key header or an
+// "Authorization: Bearer" token and stores the principal in the context.
+func (h *Handler) authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var bearer string
+		if scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			bearer = strings.TrimSpace(token)
+		}
+
+		principal, err := h.service.Auth.Authenticate(c.GetHeader(apiKeyHeader), bearer)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="library-music"`)
+			responses.NewErrorResponse(c, http.StatusUnauthorized, ErrUnauthenticated)
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+func requireRole(role models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := principalFrom(c)
+		if !ok {
+			responses.NewErrorResponse(c, http.StatusUnauthorized, ErrUnauthenticated)
+			return
+		}
+
+		if !principal.Role.Allows(role) {
+			responses.NewErrorResponse(c, http.StatusForbidden, ErrForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+func principalFrom(c *gin.Context) (models.Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return models.Principal{}, false
+	}
+	principal, ok := value.(models.Principal)
+	return principal, ok
+}
+
+// logRequests writes one line per request, including the authenticated
+// principal when there is one.
+func (h *Handler) logRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		attrs := []any{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("request_id", c.GetString(responses.RequestIDKey)),
+		}
+
+		if principal, ok := principalFrom(c); ok {
+			attrs = append(attrs,
+				slog.String("principal", principal.Subject),
+				slog.String("role", string(principal.Role)),
+				slog.String("auth_method", principal.Method),
+			)
+		}
+		h.log.Info("request", attrs...)
+	}
+}
@@ -2,24 +2,42 @@ package handler
 
 import (
 	"errors"
-	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"library-music/internal/domain/models"
 	"library-music/internal/handler/responses"
 	"library-music/internal/services"
 	"library-music/internal/services/music"
+	"library-music/internal/services/tag"
+	"library-music/pkg/lyrics"
 	"net/http"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	ErrInvalidArguments = "invalid arguments"
-	ErrAlreadyExists    = "already exists"
-	ErrRecordNotFound   = "record not found"
-	ErrInternalServer   = "internal server error"
-	ErrBadRequest       = "Bad request"
+	defaultSearchContext = 2
+	maxSearchContext     = 10
+)
+
+// pageSizeParams names the query parameter holding the page size of each
+// lyrics pagination mode.
+var pageSizeParams = map[string]string{
+	lyrics.ModeVerses: "countVerse",
+	lyrics.ModeLines:  "countLines",
+	lyrics.ModeChars:  "countChars",
+}
+
+var (
+	ErrInvalidArguments   = responses.Error{Code: "invalid_arguments", Message: "invalid arguments"}
+	ErrInvalidID          = responses.Error{Code: "invalid_id", Message: "invalid id"}
+	ErrAlreadyExists      = responses.Error{Code: "already_exists", Message: "already exists"}
+	ErrRecordNotFound     = responses.Error{Code: "record_not_found", Message: "record not found"}
+	ErrInternalServer     = responses.Error{Code: "internal_server_error", Message: "internal server error"}
+	ErrBadRequest         = responses.Error{Code: "bad_request", Message: "Bad request"}
+	ErrMissingReleaseDate = responses.Error{Code: "missing_release_date", Message: "song and album have no release date"}
 )
 
 // @Summary AddMusic
@@ -31,13 +49,17 @@ const (
 // @Param input body services.MusicToAdd true "Music info to add"
 // @Success 200 {object} responses.SuccessID
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
 // @Failure 409 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/add [post]
 func (h *Handler) AddMusic(ctx *gin.Context) {
 	var input services.MusicToAdd
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		responses.NewErrorResponse(ctx, http.StatusBadRequest, ErrInvalidArguments)
+	if !bindAndValidate(ctx, &input) {
 		return
 	}
 
@@ -47,10 +69,13 @@ func (h *Handler) AddMusic(ctx *gin.Context) {
 		return
 	}
 
-	releaseDate, err := time.Parse("02.01.2006", songDetails.ReleaseDate)
-	if err != nil {
-		responses.NewErrorResponse(ctx, http.StatusInternalServerError, ErrInternalServer)
-		return
+	var releaseDate time.Time
+	if songDetails.ReleaseDate != "" || input.AlbumId == 0 {
+		releaseDate, err = time.Parse("02.01.2006", songDetails.ReleaseDate)
+		if err != nil {
+			responses.NewErrorResponse(ctx, http.StatusInternalServerError, ErrInternalServer)
+			return
+		}
 	}
 
 	msc := models.Music{
@@ -63,17 +88,33 @@ func (h *Handler) AddMusic(ctx *gin.Context) {
 		ReleaseDate: releaseDate,
 	}
 
+	if input.AlbumId != 0 {
+		msc.Album = &models.AlbumTrack{
+			AlbumId:     input.AlbumId,
+			DiscNumber:  max(input.DiscNumber, 1),
+			TrackNumber: input.TrackNumber,
+		}
+	}
+
 	if err = validateParams(msc); err != nil {
-		responses.NewErrorResponse(ctx, http.StatusBadRequest, ErrInvalidArguments)
+		responses.NewValidationErrorResponse(ctx, http.StatusBadRequest, ErrInvalidArguments, err)
 		return
 	}
 
 	id, err := h.service.Music.Add(msc)
 	if err != nil {
-		if errors.Is(err, music.ErrMusicAlreadyExists) {
+		if errors.Is(err, music.ErrMusicAlreadyExists) || errors.Is(err, music.ErrTrackTaken) {
 			responses.NewErrorResponse(ctx, http.StatusConflict, ErrAlreadyExists)
 			return
 		}
+		if errors.Is(err, music.ErrAlbumNotFound) {
+			responses.NewErrorResponse(ctx, http.StatusNotFound, ErrRecordNotFound)
+			return
+		}
+		if errors.Is(err, music.ErrMissingReleaseDate) {
+			responses.NewErrorResponse(ctx, http.StatusBadRequest, ErrMissingReleaseDate)
+			return
+		}
 		responses.NewErrorResponse(ctx, http.StatusInternalServerError, ErrInternalServer)
 		return
 	}
@@ -97,7 +138,11 @@ func (h *Handler) AddMusic(ctx *gin.Context) {
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Failure 409 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/update [put]
 func (h *Handler) UpdateMusic(c *gin.Context) {
 	id, err := strconv.Atoi(c.Query("id"))
@@ -114,7 +159,7 @@ func (h *Handler) UpdateMusic(c *gin.Context) {
 
 	err = validateParams(input)
 	if err != nil {
-		responses.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		responses.NewValidationErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments, err)
 		return
 	}
 
@@ -133,12 +178,16 @@ func (h *Handler) UpdateMusic(c *gin.Context) {
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Failure 409 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/update [patch]
 func (h *Handler) UpdatePartialMusic(c *gin.Context) {
 	id, err := strconv.Atoi(c.Query("id"))
 	if err != nil || id < 0 {
-		responses.NewErrorResponse(c, http.StatusBadRequest, "invalid id")
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidID)
 		return
 	}
 
@@ -150,7 +199,7 @@ func (h *Handler) UpdatePartialMusic(c *gin.Context) {
 
 	err = validateParams(input)
 	if err != nil {
-		responses.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		responses.NewValidationErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments, err)
 		return
 	}
 
@@ -190,7 +239,11 @@ func (h *Handler) defaultUpdate(c *gin.Context, upd services.MusicToUpdate, id i
 // @Success 200 {object} responses.SuccessStatus
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/delete [delete]
 func (h *Handler) DeleteMusic(c *gin.Context) {
 	id, err := strconv.Atoi(c.Query("id"))
@@ -221,15 +274,30 @@ func (h *Handler) DeleteMusic(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param page query int true "Page number"
-// @Param song query string false "Song name"
-// @Param group query string false "Music group"
+// @Param song query string false "Song name, in Cyrillic or Latin spelling"
+// @Param group query string false "Music group, in Cyrillic or Latin spelling"
 // @Param link query string false "Link song"
 // @Param text query string false "Text song"
 // @Param releaseDate query string false "Release date" example:"DD.MM.YYYY"
+// @Param albumId query int false "Id album"
+// @Param genreId query int false "Id genre, subgenres included"
+// @Param tags query string false "Comma-separated tags the song or its group must all carry"
+// @Param lang query string false "BCP 47 tag of the detected lyrics language, matching regional variants"
+// @Param explicit query bool false "Only songs with (true) or without (false) explicit lyrics"
+// @Param personId query int false "Id person credited on the song"
+// @Param role query string false "Credit role, of personId when set" Enums(composer, lyricist, producer, performer)
+// @Param memberId query int false "Id person who was a member of the group of the song"
+// @Param memberYear query int false "Year the person of memberId was a member of the group"
+// @Param provider query string false "Only songs with a link to the provider" Enums(youtube, spotify, apple_music, bandcamp, lyrics, other)
+// @Param facets query bool false "Also count matches per group, year, decade, genre and tag"
 // @Param countSongs query int true "Count songs"
 // @Success 200 {object} responses.SuccessMusics
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/getAllMusic/{page} [get]
 func (h *Handler) GetAllMusic(c *gin.Context) {
 	page, err := strconv.Atoi(c.Query("page"))
@@ -245,8 +313,64 @@ func (h *Handler) GetAllMusic(c *gin.Context) {
 		ReleaseDate: c.Query("releaseDate"),
 	}
 
+	if albumId := c.Query("albumId"); albumId != "" {
+		filters.AlbumId, err = strconv.Atoi(albumId)
+		if err != nil {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	if genreId := c.Query("genreId"); genreId != "" {
+		filters.GenreId, err = strconv.Atoi(genreId)
+		if err != nil {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	if tags := c.Query("tags"); tags != "" {
+		filters.Tags = tag.NormalizeAll(strings.Split(tags, ","))
+	}
+
+	if personId := c.Query("personId"); personId != "" {
+		filters.PersonId, err = strconv.Atoi(personId)
+		if err != nil {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	if memberId := c.Query("memberId"); memberId != "" {
+		filters.MemberId, err = strconv.Atoi(memberId)
+		if err != nil {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	if memberYear := c.Query("memberYear"); memberYear != "" {
+		filters.MemberYear, err = strconv.Atoi(memberYear)
+		if err != nil {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	filters.Role = c.Query("role")
+	filters.Provider = c.Query("provider")
+	filters.Lang = c.Query("lang")
+	if v := c.Query("explicit"); v != "" {
+		isExplicit, err := strconv.ParseBool(v)
+		if err != nil {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+		filters.Explicit = &isExplicit
+	}
+
 	if err = validateParams(filters); err != nil {
-		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		responses.NewValidationErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments, err)
 		return
 	}
 
@@ -256,7 +380,20 @@ func (h *Handler) GetAllMusic(c *gin.Context) {
 		return
 	}
 
-	musics, err := h.service.Music.GetAll(filters, countSongs, page)
+	withFacets, err := strconv.ParseBool(c.DefaultQuery("facets", "false"))
+	if err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	var res responses.SuccessMusics
+	if withFacets {
+		var facets models.MusicFacets
+		res.Music, facets, err = h.service.Music.GetAllWithFacets(filters, countSongs, page)
+		res.Facets = &facets
+	} else {
+		res.Music, err = h.service.Music.GetAll(filters, countSongs, page)
+	}
 	if err != nil {
 		if errors.Is(err, music.ErrMusicNotFound) {
 			responses.NewErrorResponse(c, http.StatusBadRequest, ErrRecordNotFound)
@@ -266,9 +403,7 @@ func (h *Handler) GetAllMusic(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, responses.SuccessMusics{
-		Music: musics,
-	})
+	c.JSON(http.StatusOK, res)
 }
 
 // @Summary GetMusic
@@ -277,11 +412,15 @@ func (h *Handler) GetAllMusic(c *gin.Context) {
 // @ID get-music
 // @Accept json
 // @Produce json
-// @Param group query string true "Music group"
-// @Param song query string true "Song name"
+// @Param group query string true "Music group, in Cyrillic or Latin spelling"
+// @Param song query string true "Song name, in Cyrillic or Latin spelling"
 // @Success 200 {object} models.Music
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/getMusic [get]
 func (h *Handler) GetMusic(c *gin.Context) {
 	song := c.Query("song")
@@ -301,17 +440,27 @@ func (h *Handler) GetMusic(c *gin.Context) {
 
 // @Summary GetTextMusic
 // @Tags music
-// @Description A method for getting the lyrics of a song
+// @Description A method for getting a page of the lyrics of a song, paginated by verses, lines or a character budget
 // @ID get-text-music
 // @Accept json
 // @Produce json
 // @Param page query int true "Page number"
-// @Param song query string true "Song name"
-// @Param group query string true "Music group"
-// @Param countVerse query int true "Count verse"
-// @Success 200 {object} responses.SuccessText
+// @Param song query string true "Song name, in Cyrillic or Latin spelling"
+// @Param group query string true "Music group, in Cyrillic or Latin spelling"
+// @Param mode query string false "verses (default), lines or chars"
+// @Param countVerse query int false "Verses per page, required in verses mode"
+// @Param countLines query int false "Lines per page, required in lines mode"
+// @Param countChars query int false "Characters per page, required in chars mode"
+// @Param lang query string false "BCP 47 tag of the translation to return"
+// @Param sideBySide query bool false "Pair each original verse with its translation in lang, responding with services.VersePairsPage; verses mode only"
+// @Success 200 {object} services.TextPage
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/getTextMusic [get]
 func (h *Handler) GetTextMusic(c *gin.Context) {
 	page, err := strconv.Atoi(c.Query("page"))
@@ -322,13 +471,71 @@ func (h *Handler) GetTextMusic(c *gin.Context) {
 
 	song := c.Query("song")
 	group := c.Query("group")
-	countVerse, err := strconv.Atoi(c.Query("countVerse"))
-	if err != nil || countVerse <= 0 {
+	mode := c.DefaultQuery("mode", lyrics.ModeVerses)
+	sizeParam, ok := pageSizeParams[mode]
+	if !ok {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	size, err := strconv.Atoi(c.Query(sizeParam))
+	if err != nil || size <= 0 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	lang := c.Query("lang")
+	sideBySide, err := strconv.ParseBool(c.DefaultQuery("sideBySide", "false"))
+	if err != nil || (sideBySide && (lang == "" || mode != lyrics.ModeVerses)) {
 		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
 		return
 	}
 
-	text, err := h.service.Music.GetText(song, group, countVerse, page)
+	if sideBySide {
+		res, err := h.service.Music.GetTextSideBySide(song, group, lang, size, page)
+		if err != nil {
+			textError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res, err := h.service.Music.GetText(song, group, lang, mode, size, page)
+	if err != nil {
+		textError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary GetLyricsStructure
+// @Tags music
+// @Description A method for getting the lyrics of a song split into verses, choruses, bridges, intros and outros
+// @ID get-lyrics-structure
+// @Produce json
+// @Param song query string true "Song name, in Cyrillic or Latin spelling"
+// @Param group query string true "Music group, in Cyrillic or Latin spelling"
+// @Success 200 {object} services.LyricsToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/getLyricsStructure [get]
+func (h *Handler) GetLyricsStructure(c *gin.Context) {
+	song := c.Query("song")
+	group := c.Query("group")
+	if song == "" || group == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	res, err := h.service.Music.GetStructure(song, group)
 	if err != nil {
 		if errors.Is(err, music.ErrMusicNotFound) {
 			responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
@@ -338,16 +545,111 @@ func (h *Handler) GetTextMusic(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, responses.SuccessText{
-		Text: text,
-	})
+	c.JSON(http.StatusOK, res)
 }
 
-func validateParams(value interface{}) error {
-	validate := validator.New()
-	err := validate.Struct(value)
+// @Summary SearchLyrics
+// @Tags music
+// @Description A method for finding the lines of lyrics containing a phrase, with the lines around them and where they appear in GetTextMusic
+// @ID search-lyrics
+// @Produce json
+// @Param q query string true "Phrase to search for, case-insensitive"
+// @Param context query int false "Lines of context before and after each match, 0 to 10" default(2)
+// @Param countVerse query int false "Verses per GetTextMusic page, to report the page of each match"
+// @Param page query int true "Page number"
+// @Param countMatches query int true "Count matches"
+// @Success 200 {object} responses.SuccessLyricMatches
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/searchLyrics [get]
+func (h *Handler) SearchLyrics(c *gin.Context) {
+	phrase := strings.TrimSpace(c.Query("q"))
+	if phrase == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	context, err := strconv.Atoi(c.DefaultQuery("context", strconv.Itoa(defaultSearchContext)))
+	if err != nil || context < 0 || context > maxSearchContext {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	countVerse, err := strconv.Atoi(c.DefaultQuery("countVerse", "0"))
+	if err != nil || countVerse < 0 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	countMatches, err := strconv.Atoi(c.Query("countMatches"))
+	if err != nil || countMatches <= 0 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	res, err := h.service.Music.SearchLyrics(phrase, context, countVerse, countMatches, page)
 	if err != nil {
-		return fmt.Errorf(ErrInvalidArguments)
+		if errors.Is(err, music.ErrMusicNotFound) {
+			responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+			return
+		}
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessLyricMatches{Matches: res})
+}
+
+var validate = newValidator()
+
+// validateParams returns validator.ValidationErrors with fields named after
+// their json tags, ready to be reported by responses.NewValidationErrorResponse.
+func validateParams(value interface{}) error {
+	return validate.Struct(value)
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	// maxbytes bounds the length of a string in bytes rather than runes,
+	// as bcrypt does for passwords.
+	_ = v.RegisterValidation("maxbytes", func(fl validator.FieldLevel) bool {
+		limit, err := strconv.Atoi(fl.Param())
+		return err == nil && len(fl.Field().String()) <= limit
+	})
+	return v
+}
+
+func textError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, music.ErrMusicNotFound), errors.Is(err, music.ErrTranslationNotFound),
+		errors.Is(err, music.ErrPageOutOfRange):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, music.ErrInvalidLanguage):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidLanguage)
+	case errors.Is(err, music.ErrInvalidPagination):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
 	}
-	return nil
 }
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"library-music/internal/handler/responses"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+// queryID reads a positive "id" query parameter, aborting the request when it is missing or malformed.
+func queryID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil || id < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidID)
+		return 0, false
+	}
+	return id, true
+}
+
+// bindAndValidate decodes the JSON body into input and validates it,
+// aborting the request with a problem response on failure.
+func bindAndValidate(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return false
+	}
+
+	if err := validateParams(input); err != nil {
+		responses.NewValidationErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments, err)
+		return false
+	}
+	return true
+}
+
+// formFile reads the multipart "file" field, aborting the request when it
+// is missing or larger than maxSize bytes.
+func formFile(c *gin.Context, maxSize int64) ([]byte, bool) {
+	file, ok := openFormFile(c, maxSize)
+	if !ok {
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize))
+	if err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return nil, false
+	}
+	return data, true
+}
+
+// multipartOverhead is room for the boundaries and part headers of a
+// form around its file.
+const multipartOverhead = 1 << 20
+
+// openFormFile opens the multipart "file" field for streaming, aborting
+// the request when it is missing or larger than maxSize bytes. The body
+// is cut off past the limit, so larger uploads are never read in full.
+// The caller closes the file.
+func openFormFile(c *gin.Context, maxSize int64) (multipart.File, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil || header.Size > maxSize {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return nil, false
+	}
+	return file, true
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"io"
+	"library-music/internal/services"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// countingBody counts the bytes of a request body read by the server.
+type countingBody struct {
+	r io.Reader
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error { return nil }
+
+func TestOpenFormFileLimitsBody(t *testing.T) {
+	const maxSize = 1 << 10
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/upload", func(c *gin.Context) {
+		file, ok := openFormFile(c, maxSize)
+		if !ok {
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		c.String(http.StatusOK, "%d", len(data))
+	})
+
+	tests := []struct {
+		name   string
+		size   int
+		status int
+	}{
+		{name: "within the limit", size: maxSize, status: http.StatusOK},
+		{name: "just over the limit", size: maxSize + 1, status: http.StatusBadRequest},
+		{name: "far over the limit", size: 8 * multipartOverhead, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form bytes.Buffer
+			w := multipart.NewWriter(&form)
+			part, err := w.CreateFormFile("file", "upload.bin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = part.Write(bytes.Repeat([]byte{'x'}, tt.size)); err != nil {
+				t.Fatal(err)
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			body := &countingBody{r: &form}
+			req := httptest.NewRequest(http.MethodPost, "/upload", body)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, strings.TrimSpace(rec.Body.String()))
+			}
+			// The reader may run a buffer past the limit, but no further.
+			if limit := int64(maxSize + multipartOverhead + 64<<10); body.n > limit {
+				t.Errorf("read %d bytes of the body, want at most %d", body.n, limit)
+			}
+		})
+	}
+}
+
+func TestValidateMemberYear(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  services.MusicFilterParams
+		wantErr bool
+	}{
+		{name: "member", params: services.MusicFilterParams{MemberId: 3}},
+		{name: "member during a year", params: services.MusicFilterParams{MemberId: 3, MemberYear: 1985}},
+		{name: "year without a member", params: services.MusicFilterParams{MemberYear: 1985}, wantErr: true},
+		{name: "year out of range", params: services.MusicFilterParams{MemberId: 3, MemberYear: 10000}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateParams(tt.params); (err != nil) != tt.wantErr {
+				t.Errorf("validateParams() error = %v, want an error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
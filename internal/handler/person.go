@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/person"
+	"net/http"
+	"strconv"
+)
+
+var ErrInvalidMembershipDate = responses.Error{Code: "invalid_membership_date", Message: "membership ends before it starts"}
+
+// @Summary AddPerson
+// @Tags people
+// @Description A method for adding a person who can be credited on songs and be a member of groups
+// @ID create-person
+// @Accept json
+// @Produce json
+// @Param input body services.PersonToAdd true "Person info"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/add [post]
+func (h *Handler) AddPerson(c *gin.Context) {
+	var input services.PersonToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	id, err := h.service.Person.Add(input)
+	if err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: id,
+	})
+}
+
+// @Summary DeletePerson
+// @Tags people
+// @Description A method for deleting a person with their credits and memberships
+// @ID delete-person
+// @Produce json
+// @Param id query int true "Id person"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/delete [delete]
+func (h *Handler) DeletePerson(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Person.Delete(id); err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetPerson
+// @Tags people
+// @Description A method for getting a person with their song credits and group memberships
+// @ID get-person
+// @Produce json
+// @Param id query int true "Id person"
+// @Success 200 {object} services.PersonToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/get [get]
+func (h *Handler) GetPerson(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Person.Get(id)
+	if err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary AddCredit
+// @Tags people
+// @Description A method for crediting a person on a song as composer, lyricist, producer or performer
+// @ID add-credit
+// @Accept json
+// @Produce json
+// @Param id query int true "Id song"
+// @Param input body services.CreditToAdd true "Person and role"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/addCredit [post]
+func (h *Handler) AddCredit(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.CreditToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Person.AddCredit(id, input); err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary RemoveCredit
+// @Tags people
+// @Description A method for removing a credit of a person on a song
+// @ID remove-credit
+// @Produce json
+// @Param id query int true "Id song"
+// @Param personId query int true "Id person"
+// @Param role query string true "Role" Enums(composer, lyricist, producer, performer)
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/removeCredit [delete]
+func (h *Handler) RemoveCredit(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	personId, err := strconv.Atoi(c.Query("personId"))
+	if err != nil || personId < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidID)
+		return
+	}
+
+	role := c.Query("role")
+	if role == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	if err = h.service.Person.RemoveCredit(id, personId, role); err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetCredits
+// @Tags people
+// @Description A method for getting the people credited on a song
+// @ID get-credits
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessCredits
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/credits [get]
+func (h *Handler) GetCredits(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	credits, err := h.service.Person.GetCredits(id)
+	if err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessCredits{
+		Credits: credits,
+	})
+}
+
+// @Summary AddGroupMember
+// @Tags people
+// @Description A method for recording that a person was a member of a group, optionally between two dates
+// @ID add-group-member
+// @Accept json
+// @Produce json
+// @Param id query int true "Id group"
+// @Param input body services.MembershipToAdd true "Person and membership period"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/addMember [post]
+func (h *Handler) AddGroupMember(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.MembershipToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	membershipId, err := h.service.Person.AddMember(id, input)
+	if err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: membershipId,
+	})
+}
+
+// @Summary RemoveGroupMember
+// @Tags people
+// @Description A method for removing a membership period of a person in a group
+// @ID remove-group-member
+// @Produce json
+// @Param id query int true "Id membership"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/removeMember [delete]
+func (h *Handler) RemoveGroupMember(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Person.RemoveMember(id); err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetGroupMembers
+// @Tags people
+// @Description A method for getting the members of a group, optionally only those who were in it during a year
+// @ID get-group-members
+// @Produce json
+// @Param id query int true "Id group"
+// @Param year query int false "Year the members were in the group"
+// @Success 200 {object} responses.SuccessMembers
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/people/members [get]
+func (h *Handler) GetGroupMembers(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	year := 0
+	if v := c.Query("year"); v != "" {
+		var err error
+		year, err = strconv.Atoi(v)
+		if err != nil || year < 1 || year > 9999 {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	members, err := h.service.Person.GetMembers(id, year)
+	if err != nil {
+		personError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessMembers{
+		Members: members,
+	})
+}
+
+func personError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, person.ErrPersonNotFound), errors.Is(err, person.ErrMusicNotFound),
+		errors.Is(err, person.ErrGroupNotFound), errors.Is(err, person.ErrCreditNotFound),
+		errors.Is(err, person.ErrMembershipNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, person.ErrPersonAlreadyExists), errors.Is(err, person.ErrCreditAlreadyExists):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+	case errors.Is(err, person.ErrInvalidMembershipDate):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidMembershipDate)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -0,0 +1,443 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/playlist"
+	"net/http"
+	"strconv"
+)
+
+var ErrInvalidPosition = responses.Error{Code: "invalid_position", Message: "position is out of range"}
+
+// @Summary CreatePlaylist
+// @Tags playlists
+// @Description A method for creating an empty playlist owned by the current user
+// @ID create-playlist
+// @Accept json
+// @Produce json
+// @Param input body services.PlaylistToCreate true "Playlist name"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/playlists/create [post]
+func (h *Handler) CreatePlaylist(c *gin.Context) {
+	if _, ok := sessionUser(c); !ok {
+		return
+	}
+
+	var input services.PlaylistToCreate
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	id, err := h.service.Playlist.Create(principal, input.Name)
+	if err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: id,
+	})
+}
+
+// @Summary GetPlaylists
+// @Tags playlists
+// @Description A method for getting the playlists of the current user
+// @ID get-playlists
+// @Produce json
+// @Success 200 {object} responses.SuccessPlaylists
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/playlists/getAll [get]
+func (h *Handler) GetPlaylists(c *gin.Context) {
+	if _, ok := sessionUser(c); !ok {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	playlists, err := h.service.Playlist.GetAll(principal)
+	if err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessPlaylists{
+		Playlists: playlists,
+	})
+}
+
+// @Summary GetPlaylist
+// @Tags playlists
+// @Description A method for getting a playlist with its tracks
+// @ID get-playlist
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Success 200 {object} services.PlaylistToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/get [get]
+func (h *Handler) GetPlaylist(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	res, err := h.service.Playlist.Get(principal, id)
+	if err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary GetSharedPlaylist
+// @Tags playlists
+// @Description A method for getting a playlist shared by its owner; no authentication is required
+// @ID get-shared-playlist
+// @Produce json
+// @Param token query string true "Public token"
+// @Success 200 {object} services.PlaylistToGet
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /api/public/playlist [get]
+func (h *Handler) GetSharedPlaylist(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+		return
+	}
+
+	res, err := h.service.Playlist.GetShared(token)
+	if err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary RenamePlaylist
+// @Tags playlists
+// @Description A method for renaming a playlist
+// @ID rename-playlist
+// @Accept json
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Param input body services.PlaylistToCreate true "New name"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/rename [patch]
+func (h *Handler) RenamePlaylist(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.PlaylistToCreate
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	if err := h.service.Playlist.Rename(principal, id, input.Name); err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary DeletePlaylist
+// @Tags playlists
+// @Description A method for deleting a playlist
+// @ID delete-playlist
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/delete [delete]
+func (h *Handler) DeletePlaylist(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	if err := h.service.Playlist.Delete(principal, id); err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary AddPlaylistTrack
+// @Tags playlists
+// @Description A method for adding a song to a playlist, at the end unless a position is given
+// @ID add-playlist-track
+// @Accept json
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Param input body services.PlaylistTrackToAdd true "Song and position"
+// @Success 200 {object} responses.SuccessPosition
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/addTrack [post]
+func (h *Handler) AddPlaylistTrack(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.PlaylistTrackToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	position, err := h.service.Playlist.AddTrack(principal, id, input)
+	if err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessPosition{
+		Position: position,
+	})
+}
+
+// @Summary RemovePlaylistTrack
+// @Tags playlists
+// @Description A method for removing the track at a position from a playlist
+// @ID remove-playlist-track
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Param position query int true "Track position"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/removeTrack [delete]
+func (h *Handler) RemovePlaylistTrack(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	position, err := strconv.Atoi(c.Query("position"))
+	if err != nil || position < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidPosition)
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	if err = h.service.Playlist.RemoveTrack(principal, id, position); err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary MovePlaylistTrack
+// @Tags playlists
+// @Description A method for moving a track to another position in a playlist
+// @ID move-playlist-track
+// @Accept json
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Param input body services.PlaylistTrackToMove true "Old and new position"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/moveTrack [patch]
+func (h *Handler) MovePlaylistTrack(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.PlaylistTrackToMove
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	if err := h.service.Playlist.MoveTrack(principal, id, input); err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary DuplicatePlaylist
+// @Tags playlists
+// @Description A method for copying a playlist with its tracks into a new playlist of the current user
+// @ID duplicate-playlist
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Param name query string false "Name of the copy"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/playlists/duplicate [post]
+func (h *Handler) DuplicatePlaylist(c *gin.Context) {
+	if _, ok := sessionUser(c); !ok {
+		return
+	}
+
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	name := c.Query("name")
+	if len(name) > 200 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	newId, err := h.service.Playlist.Duplicate(principal, id, name)
+	if err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: newId,
+	})
+}
+
+// @Summary SharePlaylist
+// @Tags playlists
+// @Description A method for publishing a playlist under a new public token
+// @ID share-playlist
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Success 200 {object} responses.SuccessToken
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/share [post]
+func (h *Handler) SharePlaylist(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	token, err := h.service.Playlist.Share(principal, id)
+	if err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessToken{
+		Token: token,
+	})
+}
+
+// @Summary UnsharePlaylist
+// @Tags playlists
+// @Description A method for revoking the public token of a playlist
+// @ID unshare-playlist
+// @Produce json
+// @Param id query int true "Id playlist"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/playlists/share [delete]
+func (h *Handler) UnsharePlaylist(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	principal, _ := principalFrom(c)
+	if err := h.service.Playlist.Unshare(principal, id); err != nil {
+		playlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func playlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, playlist.ErrPlaylistNotFound), errors.Is(err, playlist.ErrMusicNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, playlist.ErrInvalidPosition):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidPosition)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
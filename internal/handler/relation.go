@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/domain/models"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/relation"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultRelationDepth = 1
+	maxRelationDepth     = 5
+)
+
+var ErrSelfRelation = responses.Error{Code: "self_relation", Message: "song cannot be related to itself"}
+
+// @Summary LinkSongs
+// @Tags relations
+// @Description A method for recording that a song is a cover, remix, live version or translation of another song, or samples it
+// @ID link-songs
+// @Accept json
+// @Produce json
+// @Param id query int true "Id song"
+// @Param input body services.RelationToAdd true "Related song and relation type"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/relations/link [post]
+func (h *Handler) LinkSongs(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.RelationToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := h.service.Relation.Link(id, input); err != nil {
+		relationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary UnlinkSongs
+// @Tags relations
+// @Description A method for removing a relation between two songs
+// @ID unlink-songs
+// @Produce json
+// @Param id query int true "Id song"
+// @Param relatedId query int true "Id related song"
+// @Param type query string true "Relation type" Enums(cover_of, remix_of, live_version_of, samples, translation_of)
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/relations/unlink [delete]
+func (h *Handler) UnlinkSongs(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	relatedId, err := strconv.Atoi(c.Query("relatedId"))
+	if err != nil || relatedId < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidID)
+		return
+	}
+
+	typ := c.Query("type")
+	if typ == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	err = h.service.Relation.Unlink(models.Relation{MusicId: id, RelatedId: relatedId, Type: typ})
+	if err != nil {
+		relationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary GetRelationGraph
+// @Tags relations
+// @Description A method for getting the songs related to a song, directly or through other songs, with the relations between them
+// @ID get-relation-graph
+// @Produce json
+// @Param id query int true "Id song"
+// @Param depth query int false "Relations to follow from the song, 1 to 5" default(1)
+// @Success 200 {object} models.RelationGraph
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/relations/graph [get]
+func (h *Handler) GetRelationGraph(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(defaultRelationDepth)))
+	if err != nil || depth < 1 || depth > maxRelationDepth {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	graph, err := h.service.Relation.GetGraph(id, depth)
+	if err != nil {
+		relationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+func relationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, relation.ErrMusicNotFound), errors.Is(err, relation.ErrRelationNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	case errors.Is(err, relation.ErrRelationAlreadyExists):
+		responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+	case errors.Is(err, relation.ErrSelfRelation):
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrSelfRelation)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -1,13 +1,88 @@
 package responses
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strings"
 )
 
+const (
+	ProblemContentType = "application/problem+json"
+	RequestIDKey       = "requestId"
+)
+
+// Error pairs a stable machine-readable code with its human-readable message.
+type Error struct {
+	Code    string
+	Message string
+}
+
+// ErrorResponse is an RFC 7807 problem details object.
 type ErrorResponse struct {
-	Message string `json:"message"`
+	Type      string       `json:"type" example:"about:blank"`
+	Title     string       `json:"title" example:"Bad Request"`
+	Status    int          `json:"status" example:"400"`
+	Detail    string       `json:"detail,omitempty" example:"invalid arguments"`
+	Instance  string       `json:"instance,omitempty" example:"/api/update"`
+	Code      string       `json:"code" example:"invalid_arguments"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field      string `json:"field" example:"link"`
+	Constraint string `json:"constraint" example:"url"`
+	Param      string `json:"param,omitempty"`
+}
+
+func NewErrorResponse(c *gin.Context, statusCode int, e Error) {
+	abortWithProblem(c, newProblem(c, statusCode, e))
+}
+
+// NewValidationErrorResponse reports every field rejected by the validator.
+// Errors of any other kind are reported as a plain problem with e.
+func NewValidationErrorResponse(c *gin.Context, statusCode int, e Error, err error) {
+	problem := newProblem(c, statusCode, e)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		problem.Errors = make([]FieldError, len(validationErrors))
+		for i, fe := range validationErrors {
+			problem.Errors[i] = FieldError{
+				Field:      fieldPath(fe),
+				Constraint: fe.Tag(),
+				Param:      fe.Param(),
+			}
+		}
+	}
+	abortWithProblem(c, problem)
+}
+
+func newProblem(c *gin.Context, statusCode int, e Error) ErrorResponse {
+	return ErrorResponse{
+		Type:      "about:blank",
+		Title:     http.StatusText(statusCode),
+		Status:    statusCode,
+		Detail:    e.Message,
+		Instance:  c.Request.URL.Path,
+		Code:      e.Code,
+		RequestID: c.GetString(RequestIDKey),
+	}
+}
+
+func abortWithProblem(c *gin.Context, problem ErrorResponse) {
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
 }
 
-func NewErrorResponse(c *gin.Context, statusCode int, message string) {
-	c.AbortWithStatusJSON(statusCode, ErrorResponse{message})
+// fieldPath drops the top-level struct name from the validator namespace,
+// so "MusicToUpdate.link" becomes "link".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
 }
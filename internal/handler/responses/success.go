@@ -1,6 +1,7 @@
 package responses
 
 import (
+	"library-music/internal/domain/models"
 	"library-music/internal/services"
 )
 
@@ -13,9 +14,66 @@ type SuccessStatus struct {
 }
 
 type SuccessMusics struct {
-	Music []services.MusicToGet `json:"songs"`
+	Music  []services.MusicToGet `json:"songs"`
+	Facets *models.MusicFacets   `json:"facets,omitempty"`
 }
 
-type SuccessText struct {
-	Text string `json:"text"`
+type SuccessLyricMatches struct {
+	Matches []services.LyricMatchToGet `json:"matches"`
+}
+
+type SuccessDuplicates struct {
+	Duplicates []services.DuplicatePair `json:"duplicates"`
+}
+
+type SuccessPosition struct {
+	Position int `json:"position"`
+}
+
+type SuccessToken struct {
+	Token string `json:"token"`
+}
+
+type SuccessPlaylists struct {
+	Playlists []services.PlaylistToGet `json:"playlists"`
+}
+
+type SuccessAlbums struct {
+	Albums []services.AlbumToGet `json:"albums"`
+}
+
+type SuccessGenres struct {
+	Genres []services.GenreToGet `json:"genres"`
+}
+
+type SuccessTagCounts struct {
+	Tags []models.TagCount `json:"tags"`
+}
+
+type SuccessLines struct {
+	Lines int `json:"lines"`
+}
+
+type SuccessTranslations struct {
+	Translations []models.Translation `json:"translations"`
+}
+
+type SuccessLinks struct {
+	Links []models.MusicLink `json:"links"`
+}
+
+type SuccessBrokenLinks struct {
+	Links []models.BrokenLink `json:"links"`
+}
+
+type SuccessAliases struct {
+	Aliases []string `json:"aliases"`
+}
+
+type SuccessCredits struct {
+	Credits []models.Credit `json:"credits"`
+}
+
+type SuccessMembers struct {
+	Members []services.MembershipToGet `json:"members"`
 }
@@ -1,12 +1,32 @@
 package handler
 
 import (
+	"context"
+	"io"
+	"library-music/internal/config"
 	"library-music/internal/domain/models"
 	"library-music/internal/services"
+	"library-music/internal/services/album"
+	"library-music/internal/services/artwork"
+	"library-music/internal/services/audio"
+	"library-music/internal/services/auth"
 	"library-music/internal/services/externalApi"
+	"library-music/internal/services/genre"
+	"library-music/internal/services/group"
+	"library-music/internal/services/linkhealth"
+	"library-music/internal/services/lyrics"
 	"library-music/internal/services/music"
+	"library-music/internal/services/person"
+	"library-music/internal/services/playlist"
+	"library-music/internal/services/relation"
+	"library-music/internal/services/tag"
+	"library-music/internal/services/tagimport"
+	"library-music/internal/services/user"
 	"library-music/internal/storage"
+	"library-music/pkg/blob"
+	"library-music/pkg/explicit"
 	"log/slog"
+	"net/http"
 )
 
 type Music interface {
@@ -14,22 +34,202 @@ type Music interface {
 	Delete(id int) error
 	Update(music services.MusicToUpdate, id int) error
 	GetAll(params services.MusicFilterParams, countSongs, page int) ([]services.MusicToGet, error)
+	GetAllWithFacets(params services.MusicFilterParams, countSongs, page int) ([]services.MusicToGet, models.MusicFacets, error)
 	Get(song, group string) (services.MusicToGet, error)
-	GetText(song, group string, countVerse, page int) (string, error)
+	GetText(song, group, lang, mode string, size, page int) (services.TextPage, error)
+	GetTextSideBySide(song, group, lang string, countVerse, page int) (services.VersePairsPage, error)
+	GetStructure(song, group string) (services.LyricsToGet, error)
+	SearchLyrics(phrase string, context, countVerse, countMatches, page int) ([]services.LyricMatchToGet, error)
+	GetStats(id, top int) (services.LyricStatsToGet, error)
+	GetGroupStats(group string, top int) (services.LyricStatsToGet, error)
+	GetDuplicates(minScore float64, limit int) ([]services.DuplicatePair, error)
+	Merge(merge services.MusicToMerge) error
+	SetChordPro(id int, document string) error
+	DeleteChordPro(id int) error
+	RenderChordPro(id int, format string, transpose, capo int) (string, error)
+	SetLanguage(id int, lang string) error
+	SetTranslation(id int, translation services.TranslationToSet) error
+	DeleteTranslation(id int, lang string) error
+	GetTranslations(id int) ([]models.Translation, error)
+	AddLink(id int, link services.LinkToAdd) (int, error)
+	DeleteLink(id, linkId int) error
+	GetLinks(id int) ([]models.MusicLink, error)
+	Backfill(ctx context.Context)
 }
 
 type ExternalApi interface {
 	Info(song, group string) (services.SongDetail, error)
 }
 
+type User interface {
+	Register(credentials services.UserCredentials) (int, error)
+	Login(credentials services.UserCredentials) (services.Session, error)
+	Logout(token string) error
+	AddFavorite(userId, musicId int) error
+	RemoveFavorite(userId, musicId int) error
+	GetFavorites(userId, countSongs, page int) ([]services.MusicToGet, error)
+	AddToLibrary(userId, musicId int) error
+	RemoveFromLibrary(userId, musicId int) error
+	GetLibrary(userId, countSongs, page int) ([]services.MusicToGet, error)
+}
+
+type Playlist interface {
+	Create(owner models.Principal, name string) (int, error)
+	Get(principal models.Principal, id int) (services.PlaylistToGet, error)
+	GetAll(owner models.Principal) ([]services.PlaylistToGet, error)
+	GetShared(token string) (services.PlaylistToGet, error)
+	Rename(principal models.Principal, id int, name string) error
+	Delete(principal models.Principal, id int) error
+	AddTrack(principal models.Principal, id int, track services.PlaylistTrackToAdd) (int, error)
+	RemoveTrack(principal models.Principal, id, position int) error
+	MoveTrack(principal models.Principal, id int, move services.PlaylistTrackToMove) error
+	Duplicate(principal models.Principal, id int, name string) (int, error)
+	Share(principal models.Principal, id int) (string, error)
+	Unshare(principal models.Principal, id int) error
+}
+
+type Album interface {
+	Add(album services.AlbumToAdd) (int, error)
+	Update(album services.AlbumToAdd, id int) error
+	Delete(id int) error
+	Get(id int) (services.AlbumToGet, error)
+	GetAll(group string, countAlbums, page int) ([]services.AlbumToGet, error)
+	AddTrack(albumId int, track services.AlbumTrackToAdd) error
+	RemoveTrack(albumId, musicId int) error
+}
+
+type Genre interface {
+	Add(genre services.GenreToAdd) (int, error)
+	Update(genre services.GenreToAdd, id int) error
+	Delete(id int) error
+	GetTree() ([]services.GenreToGet, error)
+	SetMusicGenres(musicId int, genreIds []int) error
+}
+
+type Tag interface {
+	AddToMusic(musicId int, names []string) error
+	AddToGroup(groupId int, names []string) error
+	RemoveFromMusic(musicId int, name string) error
+	RemoveFromGroup(groupId int, name string) error
+	GetCounts(limit int) ([]models.TagCount, error)
+}
+
+type Lyrics interface {
+	UploadLRC(musicId int, data string) (int, error)
+	DeleteTimings(musicId int) error
+	GetSynced(musicId int) (services.SyncedLyricsToGet, error)
+	ExportSynced(musicId int, format string) (string, error)
+}
+
+type Relation interface {
+	Link(musicId int, relation services.RelationToAdd) error
+	Unlink(relation models.Relation) error
+	GetGraph(musicId, depth int) (models.RelationGraph, error)
+}
+
+type Person interface {
+	Add(person services.PersonToAdd) (int, error)
+	Delete(id int) error
+	Get(id int) (services.PersonToGet, error)
+	AddCredit(musicId int, credit services.CreditToAdd) error
+	RemoveCredit(musicId, personId int, role string) error
+	GetCredits(musicId int) ([]models.Credit, error)
+	AddMember(groupId int, membership services.MembershipToAdd) (int, error)
+	RemoveMember(id int) error
+	GetMembers(groupId, year int) ([]services.MembershipToGet, error)
+}
+
+type Group interface {
+	AddAlias(groupId int, name string) error
+	RemoveAlias(name string) error
+	GetAliases(groupId int) ([]string, error)
+	Merge(fromId, toId int) error
+}
+
+type LinkHealth interface {
+	Run(ctx context.Context)
+	Start() error
+	CheckMusic(ctx context.Context, musicId int) ([]models.MusicLink, error)
+	GetBroken(countLinks, page int) ([]models.BrokenLink, error)
+}
+
+type Artwork interface {
+	SetForMusic(musicId int, data []byte) (services.ImageToGet, error)
+	SetForGroup(groupId int, data []byte) (services.ImageToGet, error)
+	DeleteForMusic(musicId int) error
+	DeleteForGroup(groupId int) error
+}
+
+type Audio interface {
+	Upload(musicId int, r io.Reader) (services.AudioToGet, error)
+	Delete(musicId int) error
+	Open(musicId int) (io.ReadSeekCloser, models.Audio, blob.Info, error)
+}
+
+type TagImport interface {
+	Import(r io.ReadSeeker) (services.ImportResult, error)
+}
+
+type Media interface {
+	Open(key string) (io.ReadSeekCloser, blob.Info, error)
+}
+
+type Auth interface {
+	Authenticate(apiKey, bearer string) (models.Principal, error)
+}
+
 type Service struct {
 	Music       Music
 	ExternalApi ExternalApi
+	User        User
+	Playlist    Playlist
+	Album       Album
+	Genre       Genre
+	Tag         Tag
+	Lyrics      Lyrics
+	Relation    Relation
+	Person      Person
+	Group       Group
+	LinkHealth  LinkHealth
+	Artwork     Artwork
+	Audio       Audio
+	TagImport   TagImport
+	Media       Media
+	Auth        Auth
 }
 
-func NewService(log *slog.Logger, repos *storage.Repository) *Service {
+func NewService(log *slog.Logger, repos *storage.Repository, cfg *config.Config) *Service {
+	users := user.New(log, repos.User, cfg.Auth.SessionTTL)
+	words, err := explicit.LoadFile(cfg.Lyrics.ExplicitWordsFile)
+	if err != nil {
+		panic("error loading explicit word list: " + err.Error())
+	}
+	media, err := blob.NewLocal(cfg.Media.Dir)
+	if err != nil {
+		panic("error opening media storage: " + err.Error())
+	}
+
+	songs := music.New(log, repos.Music, media, words, cfg.Media.BaseURL)
+	artworks := artwork.New(log, repos.Artwork, media, cfg.Media)
+	audios := audio.New(log, repos.Audio, media)
+
 	return &Service{
-		Music:       music.New(log, repos.Music),
+		Music:       songs,
 		ExternalApi: externalApi.New(log),
+		User:        users,
+		Playlist:    playlist.New(log, repos.Playlist),
+		Album:       album.New(log, repos.Album),
+		Genre:       genre.New(log, repos.Genre),
+		Tag:         tag.New(log, repos.Tag),
+		Lyrics:      lyrics.New(log, repos.Lyrics),
+		Relation:    relation.New(log, repos.Relation),
+		Person:      person.New(log, repos.Person),
+		Group:       group.New(log, repos.Group, media),
+		LinkHealth:  linkhealth.New(log, repos.Music, &http.Client{}, cfg.Links),
+		Artwork:     artworks,
+		Audio:       audios,
+		TagImport:   tagimport.New(log, repos.Music, repos.Album, songs, artworks, audios),
+		Media:       media,
+		Auth:        auth.MustNew(log, cfg.Auth, users),
 	}
 }
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services/music"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultTopWords = 10
+	maxTopWords     = 100
+)
+
+// @Summary GetLyricsStats
+// @Tags music
+// @Description A method for getting word and line statistics of the lyrics of a song; stopwords are left out of the top words
+// @ID get-lyrics-stats
+// @Produce json
+// @Param id query int true "Id song"
+// @Param top query int false "Count of most frequent words, 1 to 100" default(10)
+// @Success 200 {object} services.LyricStatsToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/getLyricsStats [get]
+func (h *Handler) GetLyricsStats(c *gin.Context) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	top, ok := queryTop(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Music.GetStats(id, top)
+	if err != nil {
+		statsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary GetGroupLyricsStats
+// @Tags music
+// @Description A method for getting word and line statistics of the lyrics of all the songs of a group
+// @ID get-group-lyrics-stats
+// @Produce json
+// @Param group query string true "Music group"
+// @Param top query int false "Count of most frequent words, 1 to 100" default(10)
+// @Success 200 {object} services.LyricStatsToGet
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/getGroupLyricsStats [get]
+func (h *Handler) GetGroupLyricsStats(c *gin.Context) {
+	group := c.Query("group")
+	if group == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	top, ok := queryTop(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Music.GetGroupStats(group, top)
+	if err != nil {
+		statsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func queryTop(c *gin.Context) (int, bool) {
+	top, err := strconv.Atoi(c.DefaultQuery("top", strconv.Itoa(defaultTopWords)))
+	if err != nil || top < 1 || top > maxTopWords {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return 0, false
+	}
+	return top, true
+}
+
+func statsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, music.ErrMusicNotFound), errors.Is(err, music.ErrGroupNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/tag"
+	"net/http"
+	"strconv"
+)
+
+const defaultTagCountLimit = 100
+
+// @Summary AddSongTags
+// @Tags tags
+// @Description A method for tagging a song; tags are lower-cased and created on first use
+// @ID add-song-tags
+// @Accept json
+// @Produce json
+// @Param id query int true "Id song"
+// @Param input body services.TagsToAdd true "Tags"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/tags/addToSong [post]
+func (h *Handler) AddSongTags(c *gin.Context) {
+	h.addTags(c, h.service.Tag.AddToMusic)
+}
+
+// @Summary RemoveSongTag
+// @Tags tags
+// @Description A method for removing a tag from a song
+// @ID remove-song-tag
+// @Produce json
+// @Param id query int true "Id song"
+// @Param tag query string true "Tag"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/tags/removeFromSong [delete]
+func (h *Handler) RemoveSongTag(c *gin.Context) {
+	h.removeTag(c, h.service.Tag.RemoveFromMusic)
+}
+
+// @Summary AddGroupTags
+// @Tags tags
+// @Description A method for tagging a group; tags are lower-cased and created on first use
+// @ID add-group-tags
+// @Accept json
+// @Produce json
+// @Param id query int true "Id group"
+// @Param input body services.TagsToAdd true "Tags"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/tags/addToGroup [post]
+func (h *Handler) AddGroupTags(c *gin.Context) {
+	h.addTags(c, h.service.Tag.AddToGroup)
+}
+
+// @Summary RemoveGroupTag
+// @Tags tags
+// @Description A method for removing a tag from a group
+// @ID remove-group-tag
+// @Produce json
+// @Param id query int true "Id group"
+// @Param tag query string true "Tag"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/tags/removeFromGroup [delete]
+func (h *Handler) RemoveGroupTag(c *gin.Context) {
+	h.removeTag(c, h.service.Tag.RemoveFromGroup)
+}
+
+// @Summary GetTagCounts
+// @Tags tags
+// @Description A method for getting the most used tags with the number of songs and groups carrying them
+// @ID get-tag-counts
+// @Produce json
+// @Param limit query int false "Maximum number of tags, 100 by default"
+// @Success 200 {object} responses.SuccessTagCounts
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/tags/counts [get]
+func (h *Handler) GetTagCounts(c *gin.Context) {
+	limit := defaultTagCountLimit
+	if v := c.Query("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+			return
+		}
+	}
+
+	counts, err := h.service.Tag.GetCounts(limit)
+	if err != nil {
+		tagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessTagCounts{
+		Tags: counts,
+	})
+}
+
+func (h *Handler) addTags(c *gin.Context, apply func(ownerId int, names []string) error) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	var input services.TagsToAdd
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	if err := apply(id, input.Tags); err != nil {
+		tagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func (h *Handler) removeTag(c *gin.Context, apply func(ownerId int, name string) error) {
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	name := c.Query("tag")
+	if name == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	if err := apply(id, name); err != nil {
+		tagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func tagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tag.ErrTagNotFound), errors.Is(err, tag.ErrMusicNotFound), errors.Is(err, tag.ErrGroupNotFound):
+		responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+	default:
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+	}
+}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-music/internal/domain/models"
+	"library-music/internal/handler/responses"
+	"library-music/internal/services"
+	"library-music/internal/services/user"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidCredentials = responses.Error{Code: "invalid_credentials", Message: "invalid login or password"}
+	ErrSessionRequired    = responses.Error{Code: "session_required", Message: "this endpoint requires a user session"}
+)
+
+// @Summary Register
+// @Tags users
+// @Description A method for creating a user account
+// @ID register
+// @Accept json
+// @Produce json
+// @Param input body services.UserCredentials true "Login and password"
+// @Success 200 {object} responses.SuccessID
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /api/auth/register [post]
+func (h *Handler) Register(c *gin.Context) {
+	var input services.UserCredentials
+	if !bindAndValidate(c, &input) {
+		return
+	}
+
+	id, err := h.service.User.Register(input)
+	if err != nil {
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			responses.NewErrorResponse(c, http.StatusConflict, ErrAlreadyExists)
+			return
+		}
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessID{
+		ID: id,
+	})
+}
+
+// @Summary Login
+// @Tags users
+// @Description A method for exchanging a login and password for a session token
+// @ID login
+// @Accept json
+// @Produce json
+// @Param input body services.UserCredentials true "Login and password"
+// @Success 200 {object} services.Session
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /api/auth/login [post]
+func (h *Handler) Login(c *gin.Context) {
+	var input services.UserCredentials
+	if err := c.ShouldBindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	session, err := h.service.User.Login(input)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			responses.NewErrorResponse(c, http.StatusUnauthorized, ErrInvalidCredentials)
+			return
+		}
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// @Summary Logout
+// @Tags users
+// @Description A method for revoking the current session token; requests authenticated by an API key have no session to revoke
+// @ID logout
+// @Produce json
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/auth/logout [post]
+func (h *Handler) Logout(c *gin.Context) {
+	// An API key wins over a bearer token, which then was not the one
+	// checked and must not be revoked.
+	principal, ok := principalFrom(c)
+	scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+	token = strings.TrimSpace(token)
+	if !ok || principal.Method != models.AuthMethodSession || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrSessionRequired)
+		return
+	}
+
+	if err := h.service.User.Logout(token); err != nil {
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+// @Summary AddFavorite
+// @Tags users
+// @Description A method for marking a song as a favorite of the current user
+// @ID add-favorite
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/me/favorites [post]
+func (h *Handler) AddFavorite(c *gin.Context) {
+	h.changeCollection(c, h.service.User.AddFavorite)
+}
+
+// @Summary RemoveFavorite
+// @Tags users
+// @Description A method for removing a song from the favorites of the current user
+// @ID remove-favorite
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/me/favorites [delete]
+func (h *Handler) RemoveFavorite(c *gin.Context) {
+	h.changeCollection(c, h.service.User.RemoveFavorite)
+}
+
+// @Summary GetFavorites
+// @Tags users
+// @Description A method for getting the favorite songs of the current user
+// @ID get-favorites
+// @Produce json
+// @Param page query int true "Page number"
+// @Param countSongs query int true "Count songs"
+// @Success 200 {object} responses.SuccessMusics
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/me/favorites [get]
+func (h *Handler) GetFavorites(c *gin.Context) {
+	h.getCollection(c, h.service.User.GetFavorites)
+}
+
+// @Summary AddToLibrary
+// @Tags users
+// @Description A method for adding a song to the library of the current user
+// @ID add-to-library
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/me/library [post]
+func (h *Handler) AddToLibrary(c *gin.Context) {
+	h.changeCollection(c, h.service.User.AddToLibrary)
+}
+
+// @Summary RemoveFromLibrary
+// @Tags users
+// @Description A method for removing a song from the library of the current user
+// @ID remove-from-library
+// @Produce json
+// @Param id query int true "Id song"
+// @Success 200 {object} responses.SuccessStatus
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/me/library [delete]
+func (h *Handler) RemoveFromLibrary(c *gin.Context) {
+	h.changeCollection(c, h.service.User.RemoveFromLibrary)
+}
+
+// @Summary GetLibrary
+// @Tags users
+// @Description A method for getting the library of the current user
+// @ID get-library
+// @Produce json
+// @Param page query int true "Page number"
+// @Param countSongs query int true "Count songs"
+// @Success 200 {object} responses.SuccessMusics
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security BearerAuth
+// @Router /api/me/library [get]
+func (h *Handler) GetLibrary(c *gin.Context) {
+	h.getCollection(c, h.service.User.GetLibrary)
+}
+
+func (h *Handler) changeCollection(c *gin.Context, apply func(userId, musicId int) error) {
+	userId, ok := sessionUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := queryID(c)
+	if !ok {
+		return
+	}
+
+	if err := apply(userId, id); err != nil {
+		if errors.Is(err, user.ErrMusicNotFound) {
+			responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+			return
+		}
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessStatus{
+		Status: "success",
+	})
+}
+
+func (h *Handler) getCollection(c *gin.Context, fetch func(userId, countSongs, page int) ([]services.MusicToGet, error)) {
+	userId, ok := sessionUser(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	countSongs, err := strconv.Atoi(c.Query("countSongs"))
+	if err != nil || countSongs < 1 {
+		responses.NewErrorResponse(c, http.StatusBadRequest, ErrInvalidArguments)
+		return
+	}
+
+	musics, err := fetch(userId, countSongs, page)
+	if err != nil {
+		if errors.Is(err, user.ErrMusicNotFound) {
+			responses.NewErrorResponse(c, http.StatusNotFound, ErrRecordNotFound)
+			return
+		}
+		responses.NewErrorResponse(c, http.StatusInternalServerError, ErrInternalServer)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessMusics{
+		Music: musics,
+	})
+}
+
+// sessionUser returns the id of the user behind the current session and
+// aborts the request when the caller authenticated some other way.
+func sessionUser(c *gin.Context) (int, bool) {
+	principal, ok := principalFrom(c)
+	if !ok || principal.UserId == 0 {
+		responses.NewErrorResponse(c, http.StatusForbidden, ErrSessionRequired)
+		return 0, false
+	}
+	return principal.UserId, true
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"library-music/internal/domain/models"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// keyOrSession accepts the API key "key" and the session token "session",
+// preferring the key as the auth service does.
+type keyOrSession struct{}
+
+func (keyOrSession) Authenticate(apiKey, bearer string) (models.Principal, error) {
+	switch {
+	case apiKey == "key":
+		return models.Principal{Subject: "ci", Role: models.RoleAdmin, Method: models.AuthMethodAPIKey}, nil
+	case bearer == "session":
+		return models.Principal{Subject: "reader", Role: models.RoleReader, Method: models.AuthMethodSession, UserId: 1}, nil
+	}
+	return models.Principal{}, io.EOF
+}
+
+// logouts records the tokens revoked.
+type logouts struct {
+	User
+	tokens []string
+}
+
+func (l *logouts) Logout(token string) error {
+	l.tokens = append(l.tokens, token)
+	return nil
+}
+
+func TestLogoutRevokesOnlySessions(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKey  string
+		auth    string
+		status  int
+		revoked []string
+	}{
+		{name: "session", auth: "Bearer session", status: http.StatusOK, revoked: []string{"session"}},
+		{name: "api key", apiKey: "key", status: http.StatusBadRequest},
+		{name: "api key with a bearer token", apiKey: "key", auth: "Bearer session", status: http.StatusBadRequest},
+		{name: "nothing", status: http.StatusUnauthorized},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &logouts{}
+			h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &Service{User: users, Auth: keyOrSession{}})
+			router := gin.New()
+			router.POST("/logout", h.authenticate(), h.Logout)
+
+			req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(apiKeyHeader, tt.apiKey)
+			}
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("POST /logout status = %d, want %d", rec.Code, tt.status)
+			}
+			if !reflect.DeepEqual(users.tokens, tt.revoked) {
+				t.Errorf("revoked tokens %v, want %v", users.tokens, tt.revoked)
+			}
+		})
+	}
+}
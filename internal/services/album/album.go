@@ -0,0 +1,183 @@
+package album
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/album"
+	"library-music/pkg/mapper"
+	"log/slog"
+	"strconv"
+)
+
+var (
+	ErrAlbumNotFound = errors.New("album not found")
+	ErrMusicNotFound = errors.New("music not found")
+	ErrTrackTaken    = errors.New("track already on album")
+)
+
+type Album struct {
+	log    *slog.Logger
+	repo   Repo
+	mapper mapper.AlbumMapper
+}
+
+func New(log *slog.Logger, repo Repo) *Album {
+	return &Album{
+		log:    log,
+		repo:   repo,
+		mapper: mapper.AlbumMapper{},
+	}
+}
+
+func (s *Album) Add(album services.AlbumToAdd) (int, error) {
+	const op = "album.Add"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("title", album.Title),
+		slog.String("group", album.Group),
+	)
+
+	data, err := s.mapper.AddToAlbum(album)
+	if err != nil {
+		log.Warn("error mapping", slog.String("err", err.Error()))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("start adding an album")
+	id, err := s.repo.Add(data)
+	if err != nil {
+		return 0, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully added an album", slog.String("id", strconv.Itoa(id)))
+	return id, nil
+}
+
+func (s *Album) Update(album services.AlbumToAdd, id int) error {
+	const op = "album.Update"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	data, err := s.mapper.AddToAlbum(album)
+	if err != nil {
+		log.Warn("error mapping", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("start updating an album")
+	if err = s.repo.Update(data, id); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully updated an album")
+	return nil
+}
+
+func (s *Album) Delete(id int) error {
+	const op = "album.Delete"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start deleting an album")
+	if err := s.repo.Delete(id); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully deleted an album")
+	return nil
+}
+
+func (s *Album) Get(id int) (services.AlbumToGet, error) {
+	const op = "album.Get"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start fetching an album")
+	res, err := s.repo.GetById(id)
+	if err != nil {
+		return services.AlbumToGet{}, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully fetched an album")
+	return s.mapper.AlbumForGet(res), nil
+}
+
+func (s *Album) GetAll(group string, countAlbums, page int) ([]services.AlbumToGet, error) {
+	const op = "album.GetAll"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("group", group),
+	)
+
+	log.Info("start fetching albums")
+	res, err := s.repo.GetAll(group, countAlbums, page)
+	if err != nil {
+		return nil, s.wrapErr(log, op, err)
+	}
+
+	arr := make([]services.AlbumToGet, len(res))
+	for i, v := range res {
+		arr[i] = s.mapper.AlbumForGet(v)
+	}
+	log.Info("successfully fetched albums")
+	return arr, nil
+}
+
+func (s *Album) AddTrack(albumId int, track services.AlbumTrackToAdd) error {
+	const op = "album.AddTrack"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("albumId", strconv.Itoa(albumId)),
+		slog.String("musicId", strconv.Itoa(track.MusicId)),
+	)
+
+	log.Info("start adding a track")
+	err := s.repo.AddTrack(models.AlbumTrack{
+		AlbumId:     albumId,
+		MusicId:     track.MusicId,
+		DiscNumber:  max(track.DiscNumber, 1),
+		TrackNumber: track.TrackNumber,
+	})
+	if err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully added a track")
+	return nil
+}
+
+func (s *Album) RemoveTrack(albumId, musicId int) error {
+	const op = "album.RemoveTrack"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("albumId", strconv.Itoa(albumId)),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start removing a track")
+	if err := s.repo.RemoveTrack(albumId, musicId); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully removed a track")
+	return nil
+}
+
+func (s *Album) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, albumrepo.ErrAlbumNotFound):
+		log.Warn("album not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrAlbumNotFound)
+	case errors.Is(err, albumrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, albumrepo.ErrTrackTaken):
+		log.Warn("track already on album", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrTrackTaken)
+	default:
+		log.Error("album operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
@@ -0,0 +1,15 @@
+package album
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	Add(album models.Album) (int, error)
+	Update(album models.Album, id int) error
+	Delete(id int) error
+	GetById(id int) (models.Album, error)
+	GetAll(group string, countAlbums, page int) ([]models.Album, error)
+	AddTrack(track models.AlbumTrack) error
+	RemoveTrack(albumId, musicId int) error
+}
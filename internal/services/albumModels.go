@@ -0,0 +1,30 @@
+package services
+
+type AlbumToAdd struct {
+	Title       string `json:"title" validate:"required,max=300"`
+	Group       string `json:"group,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty" validate:"omitempty,datetime=02.01.2006" example:"DD.MM.YYYY"`
+	CoverLink   string `json:"coverLink,omitempty" validate:"omitempty,url" example:"https://example.com/cover.jpg"`
+}
+
+type AlbumTrackToAdd struct {
+	MusicId     int `json:"musicId" validate:"required,min=1"`
+	DiscNumber  int `json:"discNumber,omitempty" validate:"min=0" example:"1"`
+	TrackNumber int `json:"trackNumber" validate:"required,min=1" example:"1"`
+}
+
+type AlbumToGet struct {
+	Id          int               `json:"id"`
+	Title       string            `json:"title"`
+	Group       string            `json:"group,omitempty"`
+	ReleaseDate string            `json:"releaseDate,omitempty" example:"16.07.2006"`
+	CoverLink   string            `json:"coverLink,omitempty"`
+	Tracks      []AlbumTrackToGet `json:"tracks,omitempty"`
+}
+
+type AlbumTrackToGet struct {
+	MusicId     int    `json:"musicId"`
+	Song        string `json:"song"`
+	DiscNumber  int    `json:"discNumber"`
+	TrackNumber int    `json:"trackNumber"`
+}
@@ -0,0 +1,202 @@
+package artwork
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"library-music/internal/config"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/artwork"
+	"library-music/pkg/blob"
+	"library-music/pkg/imaging"
+	"library-music/pkg/mapper"
+	"log/slog"
+	"strconv"
+)
+
+// KeyPrefix starts the blob keys of artwork files, the only files served
+// without authentication.
+const KeyPrefix = "artwork/"
+
+var (
+	ErrMusicNotFound     = errors.New("music not found")
+	ErrGroupNotFound     = errors.New("group not found")
+	ErrArtworkNotFound   = errors.New("artwork not found")
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image is too large")
+)
+
+type Artwork struct {
+	log       *slog.Logger
+	repo      Repo
+	store     blob.Store
+	mapper    mapper.MusicMapper
+	maxPixels int
+	sizes     []int
+}
+
+// New creates the artwork service keeping images in store, with
+// thumbnails of the configured sizes.
+func New(log *slog.Logger, repo Repo, store blob.Store, cfg config.CfgMedia) *Artwork {
+	return &Artwork{
+		log:       log,
+		repo:      repo,
+		store:     store,
+		mapper:    mapper.MusicMapper{MediaURL: cfg.BaseURL},
+		maxPixels: cfg.MaxImagePixels,
+		sizes:     cfg.ThumbnailSizes,
+	}
+}
+
+// SetForMusic makes an image the cover of a song, replacing its cover.
+func (s *Artwork) SetForMusic(musicId int, data []byte) (services.ImageToGet, error) {
+	const op = "artwork.SetForMusic"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+	return s.set(log, op, models.Artwork{MusicId: musicId}, data)
+}
+
+// SetForGroup makes an image the image of a group, replacing its image.
+func (s *Artwork) SetForGroup(groupId int, data []byte) (services.ImageToGet, error) {
+	const op = "artwork.SetForGroup"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("groupId", strconv.Itoa(groupId)),
+	)
+	return s.set(log, op, models.Artwork{GroupId: groupId}, data)
+}
+
+func (s *Artwork) DeleteForMusic(musicId int) error {
+	const op = "artwork.DeleteForMusic"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+	return s.delete(log, op, musicId, 0)
+}
+
+func (s *Artwork) DeleteForGroup(groupId int) error {
+	const op = "artwork.DeleteForGroup"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("groupId", strconv.Itoa(groupId)),
+	)
+	return s.delete(log, op, 0, groupId)
+}
+
+// set stores the image and its thumbnails under a new key before
+// recording it, so the artwork it replaces stays whole until then.
+func (s *Artwork) set(log *slog.Logger, op string, artwork models.Artwork, data []byte) (services.ImageToGet, error) {
+	img, err := imaging.Decode(data, s.maxPixels)
+	if err != nil {
+		log.Warn("invalid image", slog.String("err", err.Error()))
+		if errors.Is(err, imaging.ErrTooManyPixels) {
+			return services.ImageToGet{}, fmt.Errorf("%s: %w", op, ErrImageTooLarge)
+		}
+		return services.ImageToGet{}, fmt.Errorf("%s: %w", op, ErrUnsupportedFormat)
+	}
+
+	key, err := newKey()
+	if err != nil {
+		log.Error("failed to generate a key", slog.String("err", err.Error()))
+		return services.ImageToGet{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	artwork.Key = key
+	artwork.Ext = img.Ext
+	artwork.Width = img.Width
+	artwork.Height = img.Height
+	artwork.Sizes = s.sizes
+
+	log.Info("start storing an image")
+	if err = s.put(key, img, data); err != nil {
+		s.cleanup(log, key)
+		log.Error("failed to store an image", slog.String("err", err.Error()))
+		return services.ImageToGet{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	old, err := s.repo.Set(artwork)
+	if err != nil {
+		s.cleanup(log, key)
+		return services.ImageToGet{}, s.wrapErr(log, op, err)
+	}
+
+	if old != "" {
+		if err = s.store.Delete(old); err != nil {
+			log.Warn("failed to delete replaced artwork", slog.String("key", old), slog.String("err", err.Error()))
+		}
+	}
+	log.Info("successfully stored an image")
+	return *s.mapper.ArtworkForGet(&artwork), nil
+}
+
+func (s *Artwork) delete(log *slog.Logger, op string, musicId, groupId int) error {
+	log.Info("start deleting artwork")
+	key, err := s.repo.Delete(musicId, groupId)
+	if err != nil {
+		return s.wrapErr(log, op, err)
+	}
+
+	if err = s.store.Delete(key); err != nil {
+		log.Warn("failed to delete artwork files", slog.String("key", key), slog.String("err", err.Error()))
+	}
+	log.Info("successfully deleted artwork")
+	return nil
+}
+
+// put stores the image as uploaded and a thumbnail of it per size.
+func (s *Artwork) put(key string, img imaging.Image, data []byte) error {
+	if err := s.store.Put(key+"/original."+img.Ext, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	for _, size := range s.sizes {
+		var thumb bytes.Buffer
+		if err := img.WriteThumbnail(&thumb, size); err != nil {
+			return err
+		}
+		if err := s.store.Put(key+"/"+strconv.Itoa(size)+".jpg", &thumb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanup removes the files stored for an artwork that was not recorded.
+func (s *Artwork) cleanup(log *slog.Logger, key string) {
+	if err := s.store.Delete(key); err != nil {
+		log.Warn("failed to delete unused artwork files", slog.String("key", key), slog.String("err", err.Error()))
+	}
+}
+
+func (s *Artwork) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, artworkrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, artworkrepo.ErrGroupNotFound):
+		log.Warn("group not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	case errors.Is(err, artworkrepo.ErrArtworkNotFound):
+		log.Warn("artwork not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrArtworkNotFound)
+	default:
+		log.Error("artwork operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// newKey names the files of an artwork at random, so an uploaded image
+// never has the URL of the one it replaces and caches need no busting.
+func newKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return KeyPrefix + hex.EncodeToString(b), nil
+}
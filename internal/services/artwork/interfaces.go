@@ -0,0 +1,10 @@
+package artwork
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	Set(artwork models.Artwork) (string, error)
+	Delete(musicId, groupId int) (string, error)
+}
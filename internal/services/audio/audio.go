@@ -0,0 +1,180 @@
+package audio
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/audio"
+	"library-music/pkg/audiofmt"
+	"library-music/pkg/blob"
+	"library-music/pkg/mapper"
+	"log/slog"
+	"strconv"
+)
+
+var (
+	ErrMusicNotFound     = errors.New("music not found")
+	ErrAudioNotFound     = errors.New("audio not found")
+	ErrUnsupportedFormat = errors.New("unsupported audio format")
+)
+
+type Audio struct {
+	log    *slog.Logger
+	repo   Repo
+	store  blob.Store
+	mapper mapper.MusicMapper
+}
+
+func New(log *slog.Logger, repo Repo, store blob.Store) *Audio {
+	return &Audio{
+		log:    log,
+		repo:   repo,
+		store:  store,
+		mapper: mapper.MusicMapper{},
+	}
+}
+
+// Upload stores the audio file of a song read from r, replacing the one
+// it had. The format is sniffed from the first bytes of the file.
+func (s *Audio) Upload(musicId int, r io.Reader) (services.AudioToGet, error) {
+	const op = "audio.Upload"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(audiofmt.SniffLen)
+	format, ok := audiofmt.Sniff(head)
+	if !ok {
+		log.Warn("unsupported audio format")
+		return services.AudioToGet{}, fmt.Errorf("%s: %w", op, ErrUnsupportedFormat)
+	}
+
+	key, err := newKey(format.Ext)
+	if err != nil {
+		log.Error("failed to generate a key", slog.String("err", err.Error()))
+		return services.AudioToGet{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("start storing an audio file")
+	counter := &countingReader{r: br}
+	if err = s.store.Put(key, counter); err != nil {
+		s.cleanup(log, key)
+		log.Error("failed to store an audio file", slog.String("err", err.Error()))
+		return services.AudioToGet{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	file := models.Audio{
+		MusicId:     musicId,
+		Key:         key,
+		ContentType: format.ContentType,
+		Size:        counter.n,
+	}
+	old, err := s.repo.Set(file)
+	if err != nil {
+		s.cleanup(log, key)
+		return services.AudioToGet{}, s.wrapErr(log, op, err)
+	}
+
+	if old != "" {
+		if err = s.store.Delete(old); err != nil {
+			log.Warn("failed to delete replaced audio file", slog.String("err", err.Error()))
+		}
+	}
+	log.Info("successfully stored an audio file")
+	return *s.mapper.AudioForGet(&file), nil
+}
+
+func (s *Audio) Delete(musicId int) error {
+	const op = "audio.Delete"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start deleting an audio file")
+	key, err := s.repo.Delete(musicId)
+	if err != nil {
+		return s.wrapErr(log, op, err)
+	}
+
+	if err = s.store.Delete(key); err != nil {
+		log.Warn("failed to delete audio file", slog.String("err", err.Error()))
+	}
+	log.Info("successfully deleted an audio file")
+	return nil
+}
+
+// Open returns the audio file of a song for streaming; the caller closes it.
+func (s *Audio) Open(musicId int) (io.ReadSeekCloser, models.Audio, blob.Info, error) {
+	const op = "audio.Open"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	file, err := s.repo.Get(musicId)
+	if err != nil {
+		return nil, models.Audio{}, blob.Info{}, s.wrapErr(log, op, err)
+	}
+
+	r, info, err := s.store.Open(file.Key)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			log.Error("audio file is missing from storage")
+			return nil, models.Audio{}, blob.Info{}, fmt.Errorf("%s: %w", op, ErrAudioNotFound)
+		}
+		log.Error("failed to open audio file", slog.String("err", err.Error()))
+		return nil, models.Audio{}, blob.Info{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return r, file, info, nil
+}
+
+// cleanup removes a stored file that was not recorded.
+func (s *Audio) cleanup(log *slog.Logger, key string) {
+	if err := s.store.Delete(key); err != nil {
+		log.Warn("failed to delete unused audio file", slog.String("err", err.Error()))
+	}
+}
+
+func (s *Audio) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, audiorepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, audiorepo.ErrAudioNotFound):
+		log.Warn("audio not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrAudioNotFound)
+	default:
+		log.Error("audio operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// newKey names audio files at random, so a replaced file never shares
+// its key with the file replacing it.
+func newKey(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "audio/" + hex.EncodeToString(b) + "." + ext, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,11 @@
+package audio
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	Set(audio models.Audio) (string, error)
+	Delete(musicId int) (string, error)
+	Get(musicId int) (models.Audio, error)
+}
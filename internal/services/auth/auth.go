@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"library-music/internal/config"
+	"library-music/internal/domain/models"
+	"library-music/pkg/jwt"
+	"log/slog"
+	"strings"
+)
+
+var (
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type apiKey struct {
+	name string
+	hash [sha256.Size]byte
+	role models.Role
+}
+
+// Sessions resolves opaque session tokens issued on login.
+type Sessions interface {
+	AuthenticateSession(token string) (models.Principal, error)
+}
+
+type Auth struct {
+	log      *slog.Logger
+	apiKeys  []apiKey
+	verifier *jwt.Verifier
+	sessions Sessions
+}
+
+func New(log *slog.Logger, cfg config.CfgAuth, sessions Sessions) (*Auth, error) {
+	keys := make([]apiKey, 0, len(cfg.APIKeys))
+	for _, k := range cfg.APIKeys {
+		role, ok := models.ParseRole(k.Role)
+		if !ok {
+			return nil, fmt.Errorf("api key %q: unknown role %q", k.Name, k.Role)
+		}
+
+		if k.Key == "" {
+			return nil, fmt.Errorf("api key %q: empty key", k.Name)
+		}
+
+		keys = append(keys, apiKey{
+			name: k.Name,
+			hash: sha256.Sum256([]byte(k.Key)),
+			role: role,
+		})
+	}
+
+	opts := []jwt.Option{
+		jwt.WithIssuer(cfg.JWT.Issuer),
+		jwt.WithAudience(cfg.JWT.Audience),
+	}
+
+	if cfg.JWT.HS256SecretFile != "" {
+		secret, err := jwt.LoadHS256Secret(cfg.JWT.HS256SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading HS256 secret: %w", err)
+		}
+		opts = append(opts, jwt.WithHS256Secret(secret))
+	}
+
+	if cfg.JWT.RS256PublicKeyFile != "" {
+		key, err := jwt.LoadRSAPublicKey(cfg.JWT.RS256PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading RS256 public key: %w", err)
+		}
+		opts = append(opts, jwt.WithRS256PublicKey(key))
+	}
+
+	return &Auth{
+		log:      log,
+		apiKeys:  keys,
+		verifier: jwt.NewVerifier(opts...),
+		sessions: sessions,
+	}, nil
+}
+
+func MustNew(log *slog.Logger, cfg config.CfgAuth, sessions Sessions) *Auth {
+	a, err := New(log, cfg, sessions)
+	if err != nil {
+		panic("error configuring authentication: " + err.Error())
+	}
+	return a
+}
+
+// Authenticate resolves either an API key or a bearer token into a principal.
+// The API key takes precedence when both are present. Bearer tokens shaped
+// like a JWT are verified as such, anything else is looked up as a session.
+func (s *Auth) Authenticate(key, bearer string) (models.Principal, error) {
+	const op = "auth.Authenticate"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	switch {
+	case key != "":
+		principal, err := s.authenticateAPIKey(key)
+		if err != nil {
+			log.Warn("rejected api key")
+			return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return principal, nil
+	case bearer != "":
+		principal, err := s.authenticateBearer(bearer)
+		if err != nil {
+			log.Warn("rejected bearer token", slog.String("err", err.Error()))
+			return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return principal, nil
+	default:
+		return models.Principal{}, fmt.Errorf("%s: %w", op, ErrUnauthenticated)
+	}
+}
+
+func (s *Auth) authenticateAPIKey(key string) (models.Principal, error) {
+	hash := sha256.Sum256([]byte(key))
+	for _, k := range s.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			return models.Principal{
+				Subject: k.name,
+				Role:    k.role,
+				Method:  models.AuthMethodAPIKey,
+			}, nil
+		}
+	}
+	return models.Principal{}, ErrInvalidCredentials
+}
+
+func (s *Auth) authenticateBearer(token string) (models.Principal, error) {
+	if strings.Count(token, ".") == 2 {
+		return s.authenticateJWT(token)
+	}
+
+	principal, err := s.sessions.AuthenticateSession(token)
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	return principal, nil
+}
+
+func (s *Auth) authenticateJWT(token string) (models.Principal, error) {
+	if !s.verifier.Enabled() {
+		return models.Principal{}, ErrInvalidCredentials
+	}
+
+	claims, err := s.verifier.Verify(token)
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	role, ok := highestRole(claims)
+	if !ok || claims.Subject == "" {
+		return models.Principal{}, ErrInvalidCredentials
+	}
+
+	return models.Principal{
+		Subject: claims.Subject,
+		Role:    role,
+		Method:  models.AuthMethodJWT,
+	}, nil
+}
+
+func highestRole(claims jwt.Claims) (models.Role, bool) {
+	candidates := append([]string{claims.Role}, claims.Roles...)
+
+	var best models.Role
+	found := false
+	for _, c := range candidates {
+		role, ok := models.ParseRole(c)
+		if !ok {
+			continue
+		}
+		if !found || !best.Allows(role) {
+			best = role
+			found = true
+		}
+	}
+	return best, found
+}
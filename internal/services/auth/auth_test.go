@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"library-music/internal/config"
+	"library-music/internal/domain/models"
+	"library-music/pkg/jwt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fakeSessions map[string]models.Principal
+
+func (f fakeSessions) AuthenticateSession(token string) (models.Principal, error) {
+	principal, ok := f[token]
+	if !ok {
+		return models.Principal{}, errors.New("session not found")
+	}
+	return principal, nil
+}
+
+func TestHighestRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.Claims
+		want   models.Role
+		ok     bool
+	}{
+		{name: "role only", claims: jwt.Claims{Role: "editor"}, want: models.RoleEditor, ok: true},
+		{name: "roles only", claims: jwt.Claims{Roles: []string{"reader", "admin", "editor"}}, want: models.RoleAdmin, ok: true},
+		{name: "role below roles", claims: jwt.Claims{Role: "reader", Roles: []string{"editor"}}, want: models.RoleEditor, ok: true},
+		{name: "role above roles", claims: jwt.Claims{Role: "admin", Roles: []string{"reader"}}, want: models.RoleAdmin, ok: true},
+		{name: "unknown roles skipped", claims: jwt.Claims{Role: "owner", Roles: []string{"superuser", "reader"}}, want: models.RoleReader, ok: true},
+		{name: "no known role", claims: jwt.Claims{Role: "owner", Roles: []string{"Admin"}}},
+		{name: "no role", claims: jwt.Claims{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := highestRole(tt.claims)
+			if ok != tt.ok || (ok && got != tt.want) {
+				t.Errorf("highestRole() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAuthenticatePrecedence(t *testing.T) {
+	sessions := fakeSessions{
+		"session-token": {Subject: "listener", Role: models.RoleReader, Method: models.AuthMethodSession, UserId: 1},
+	}
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("jwt-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.CfgAuth{
+		APIKeys: []config.CfgAPIKey{{Name: "importer", Key: "secret-key", Role: "editor"}},
+		JWT:     config.CfgJWT{HS256SecretFile: secretFile},
+	}
+	token := signHS256([]byte("jwt-secret"), `{"sub":"service","role":"admin","exp":`+strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)+`}`)
+	a, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg, sessions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		bearer  string
+		subject string
+		err     error
+	}{
+		{name: "api key", key: "secret-key", subject: "importer"},
+		{name: "session", bearer: "session-token", subject: "listener"},
+		{name: "jwt", bearer: token, subject: "service"},
+		{name: "api key wins over session", key: "secret-key", bearer: "session-token", subject: "importer"},
+		{name: "api key wins over jwt", key: "secret-key", bearer: token, subject: "importer"},
+		// A wrong key is rejected outright rather than falling back to the
+		// bearer token.
+		{name: "wrong api key with session", key: "wrong", bearer: "session-token", err: ErrInvalidCredentials},
+		{name: "unknown session", bearer: "other-token", err: ErrInvalidCredentials},
+		{name: "wrong api key with jwt", key: "wrong", bearer: token, err: ErrInvalidCredentials},
+		{name: "jwt with bad signature", bearer: signHS256([]byte("other"), `{"sub":"service","role":"admin","exp":4102444800}`), err: ErrInvalidCredentials},
+		{name: "nothing", err: ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := a.Authenticate(tt.key, tt.bearer)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+			}
+			if principal.Subject != tt.subject {
+				t.Errorf("Authenticate() subject = %q, want %q", principal.Subject, tt.subject)
+			}
+		})
+	}
+}
+
+func TestAuthenticateJWTWithoutKeys(t *testing.T) {
+	a, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), config.CfgAuth{}, fakeSessions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := signHS256(nil, `{"sub":"service","role":"admin","exp":4102444800}`)
+	if _, err = a.Authenticate("", token); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Authenticate() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+}
+
+func signHS256(secret []byte, claims string) string {
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewRejectsInvalidKeys(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, key := range []config.CfgAPIKey{
+		{Name: "no role", Key: "k", Role: "owner"},
+		{Name: "empty", Key: "", Role: "reader"},
+	} {
+		if _, err := New(log, config.CfgAuth{APIKeys: []config.CfgAPIKey{key}}, nil); err == nil {
+			t.Errorf("New() accepted api key %q", key.Name)
+		}
+	}
+}
@@ -54,7 +54,6 @@ func (s *ExternalApi) Info(song, group string) (services.SongDetail, error) {
 	}
 
 	defer resp.Body.Close()
-	log.Debug("response", resp.Body)
 
 	err = json.NewDecoder(resp.Body).Decode(&info)
 	if err != nil {
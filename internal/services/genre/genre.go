@@ -0,0 +1,166 @@
+package genre
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/genre"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrGenreNotFound      = errors.New("genre not found")
+	ErrGenreAlreadyExists = errors.New("genre already exists")
+	ErrGenreInUse         = errors.New("genre has subgenres")
+	ErrGenreCycle         = errors.New("genre cannot be nested under itself")
+	ErrMusicNotFound      = errors.New("music not found")
+)
+
+type Genre struct {
+	log  *slog.Logger
+	repo Repo
+}
+
+func New(log *slog.Logger, repo Repo) *Genre {
+	return &Genre{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *Genre) Add(genre services.GenreToAdd) (int, error) {
+	const op = "genre.Add"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("name", genre.Name),
+	)
+
+	log.Info("start adding a genre")
+	id, err := s.repo.Add(models.Genre{
+		Name:     strings.TrimSpace(genre.Name),
+		ParentId: genre.ParentId,
+	})
+	if err != nil {
+		return 0, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully added a genre", slog.String("id", strconv.Itoa(id)))
+	return id, nil
+}
+
+func (s *Genre) Update(genre services.GenreToAdd, id int) error {
+	const op = "genre.Update"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start updating a genre")
+	err := s.repo.Update(models.Genre{
+		Name:     strings.TrimSpace(genre.Name),
+		ParentId: genre.ParentId,
+	}, id)
+	if err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully updated a genre")
+	return nil
+}
+
+func (s *Genre) Delete(id int) error {
+	const op = "genre.Delete"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start deleting a genre")
+	if err := s.repo.Delete(id); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully deleted a genre")
+	return nil
+}
+
+// GetTree returns the genre taxonomy as a forest of top-level genres.
+func (s *Genre) GetTree() ([]services.GenreToGet, error) {
+	const op = "genre.GetTree"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start fetching genres")
+	genres, err := s.repo.GetAll()
+	if err != nil {
+		return nil, s.wrapErr(log, op, err)
+	}
+
+	children := make(map[int][]models.Genre)
+	var roots []models.Genre
+	for _, g := range genres {
+		if g.ParentId == nil {
+			roots = append(roots, g)
+			continue
+		}
+		children[*g.ParentId] = append(children[*g.ParentId], g)
+	}
+
+	var build func(level []models.Genre) []services.GenreToGet
+	build = func(level []models.Genre) []services.GenreToGet {
+		if len(level) == 0 {
+			return nil
+		}
+		res := make([]services.GenreToGet, len(level))
+		for i, g := range level {
+			res[i] = services.GenreToGet{
+				Id:       g.Id,
+				Name:     g.Name,
+				Children: build(children[g.Id]),
+			}
+		}
+		return res
+	}
+
+	log.Info("successfully fetched genres")
+	return build(roots), nil
+}
+
+func (s *Genre) SetMusicGenres(musicId int, genreIds []int) error {
+	const op = "genre.SetMusicGenres"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start setting song genres")
+	if err := s.repo.SetMusicGenres(musicId, genreIds); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully set song genres")
+	return nil
+}
+
+func (s *Genre) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, genrerepo.ErrGenreNotFound):
+		log.Warn("genre not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGenreNotFound)
+	case errors.Is(err, genrerepo.ErrGenreAlreadyExists):
+		log.Warn("genre already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGenreAlreadyExists)
+	case errors.Is(err, genrerepo.ErrGenreInUse):
+		log.Warn("genre has subgenres", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGenreInUse)
+	case errors.Is(err, genrerepo.ErrGenreCycle):
+		log.Warn("genre cycle", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGenreCycle)
+	case errors.Is(err, genrerepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	default:
+		log.Error("genre operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
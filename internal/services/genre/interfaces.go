@@ -0,0 +1,13 @@
+package genre
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	Add(genre models.Genre) (int, error)
+	Update(genre models.Genre, id int) error
+	Delete(id int) error
+	GetAll() ([]models.Genre, error)
+	SetMusicGenres(musicId int, genreIds []int) error
+}
@@ -0,0 +1,127 @@
+package group
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/storage/group"
+	"library-music/pkg/blob"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrAliasNotFound      = errors.New("alias not found")
+	ErrAliasAlreadyExists = errors.New("alias already exists")
+	ErrMergeIntoSelf      = errors.New("group cannot be merged into itself")
+)
+
+type Group struct {
+	log   *slog.Logger
+	repo  Repo
+	store blob.Store
+}
+
+// New creates the group service; images of merged groups are deleted
+// from store.
+func New(log *slog.Logger, repo Repo, store blob.Store) *Group {
+	return &Group{
+		log:   log,
+		repo:  repo,
+		store: store,
+	}
+}
+
+func (s *Group) AddAlias(groupId int, name string) error {
+	const op = "group.AddAlias"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("groupId", strconv.Itoa(groupId)),
+		slog.String("alias", name),
+	)
+
+	log.Info("start adding a group alias")
+	if err := s.repo.AddAlias(groupId, strings.TrimSpace(name)); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully added a group alias")
+	return nil
+}
+
+func (s *Group) RemoveAlias(name string) error {
+	const op = "group.RemoveAlias"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("alias", name),
+	)
+
+	log.Info("start removing a group alias")
+	if err := s.repo.RemoveAlias(name); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully removed a group alias")
+	return nil
+}
+
+func (s *Group) GetAliases(groupId int) ([]string, error) {
+	const op = "group.GetAliases"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("groupId", strconv.Itoa(groupId)),
+	)
+
+	log.Info("start fetching group aliases")
+	aliases, err := s.repo.GetAliases(groupId)
+	if err != nil {
+		return nil, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully fetched group aliases")
+	return aliases, nil
+}
+
+// Merge folds the group fromId into toId, whose aliases then include the
+// name of fromId.
+func (s *Group) Merge(fromId, toId int) error {
+	const op = "group.Merge"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("fromId", strconv.Itoa(fromId)),
+		slog.String("toId", strconv.Itoa(toId)),
+	)
+
+	log.Info("start merging groups")
+	keys, err := s.repo.Merge(fromId, toId)
+	if err != nil {
+		return s.wrapErr(log, op, err)
+	}
+
+	// The group is gone either way, so failures are only logged.
+	for _, key := range keys {
+		if err = s.store.Delete(key); err != nil {
+			log.Warn("failed to delete an image of the merged group", slog.String("err", err.Error()))
+		}
+	}
+	log.Info("successfully merged groups")
+	return nil
+}
+
+func (s *Group) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, grouprepo.ErrGroupNotFound):
+		log.Warn("group not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	case errors.Is(err, grouprepo.ErrAliasNotFound):
+		log.Warn("alias not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrAliasNotFound)
+	case errors.Is(err, grouprepo.ErrAliasAlreadyExists):
+		log.Warn("alias already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrAliasAlreadyExists)
+	case errors.Is(err, grouprepo.ErrMergeIntoSelf):
+		log.Warn("merge into self", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMergeIntoSelf)
+	default:
+		log.Error("group operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
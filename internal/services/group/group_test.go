@@ -0,0 +1,63 @@
+package group
+
+import (
+	"errors"
+	"io"
+	"library-music/internal/storage/group"
+	"library-music/pkg/blob"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// mergeRepo merges groups, leaving the given images unused.
+type mergeRepo struct {
+	Repo
+	keys []string
+	err  error
+}
+
+func (r mergeRepo) Merge(fromId, toId int) ([]string, error) {
+	return r.keys, r.err
+}
+
+func TestMergeDeletesImages(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    mergeRepo
+		deleted bool
+		err     error
+	}{
+		{name: "merged", repo: mergeRepo{keys: []string{"artwork/from"}}, deleted: true},
+		{name: "into itself", repo: mergeRepo{err: grouprepo.ErrMergeIntoSelf}, err: ErrMergeIntoSelf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := blob.NewLocal(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"artwork/from/original.png", "artwork/from/256.jpg", "artwork/to/original.png"} {
+				if err = store.Put(key, strings.NewReader("image")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.repo, store)
+			if err = s.Merge(1, 2); !errors.Is(err, tt.err) {
+				t.Fatalf("Merge() error = %v, want %v", err, tt.err)
+			}
+
+			for _, key := range []string{"artwork/from/original.png", "artwork/from/256.jpg"} {
+				_, _, err = store.Open(key)
+				if deleted := errors.Is(err, blob.ErrNotFound); deleted != tt.deleted {
+					t.Errorf("image of the merged group %s deleted = %v, want %v", key, deleted, tt.deleted)
+				}
+			}
+			if _, _, err = store.Open("artwork/to/original.png"); err != nil {
+				t.Errorf("image of the kept group: Open() error = %v", err)
+			}
+		})
+	}
+}
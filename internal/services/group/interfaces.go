@@ -0,0 +1,8 @@
+package group
+
+type Repo interface {
+	AddAlias(groupId int, name string) error
+	RemoveAlias(name string) error
+	GetAliases(groupId int) ([]string, error)
+	Merge(fromId, toId int) ([]string, error)
+}
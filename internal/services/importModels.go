@@ -0,0 +1,32 @@
+package services
+
+// ImportResult reports what importing a tagged audio file did to a song.
+// Fields the song already had are never overwritten by tags; where they
+// differ, the difference is listed in Conflicts. Audio is missing when the
+// song already had an audio file, which is kept and listed as a conflict.
+type ImportResult struct {
+	MusicId   int           `json:"musicId" example:"1"`
+	Created   bool          `json:"created"`
+	Tags      ImportedTags  `json:"tags"`
+	Applied   []string      `json:"applied,omitempty" example:"text,cover"`
+	Conflicts []TagConflict `json:"conflicts,omitempty"`
+	Audio     *AudioToGet   `json:"audio,omitempty"`
+}
+
+// ImportedTags are the tags read from the file.
+type ImportedTags struct {
+	Title       string `json:"title" example:"Кукушка"`
+	Artist      string `json:"artist" example:"Кино"`
+	Album       string `json:"album,omitempty" example:"Черный альбом"`
+	ReleaseDate string `json:"releaseDate,omitempty" example:"01.01.1990"`
+	TrackNumber int    `json:"trackNumber,omitempty" example:"3"`
+	HasLyrics   bool   `json:"hasLyrics"`
+	HasCover    bool   `json:"hasCover"`
+}
+
+// TagConflict is a field whose tagged value differs from the stored one.
+type TagConflict struct {
+	Field   string `json:"field" example:"releaseDate"`
+	Current string `json:"current" example:"21.06.1990"`
+	Tagged  string `json:"tagged" example:"01.01.1989"`
+}
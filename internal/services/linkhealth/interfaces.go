@@ -0,0 +1,11 @@
+package linkhealth
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	GetLinksToCheck(musicId int) ([]models.MusicLink, error)
+	SetLinkCheck(linkId int, check models.LinkCheck) error
+	GetBrokenLinks(countLinks, page int) ([]models.BrokenLink, error)
+}
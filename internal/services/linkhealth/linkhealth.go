@@ -0,0 +1,254 @@
+package linkhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"library-music/internal/config"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/music"
+	"library-music/pkg/linkcheck"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrLinkNotFound = errors.New("link not found")
+	ErrCheckRunning = errors.New("link check already running")
+)
+
+// LinkHealth checks the links of songs, on demand and every interval in
+// the background, and records the outcome of the last check of each.
+type LinkHealth struct {
+	log      *slog.Logger
+	repo     Repo
+	checker  *linkcheck.Checker
+	interval time.Duration
+	timeout  time.Duration
+	workers  int
+	running  atomic.Bool
+	// jobs is the context given to Run, which stops checks started with
+	// Start as well.
+	mu   sync.Mutex
+	jobs context.Context
+}
+
+// New creates the service sending its requests with client, which tests
+// can point at a local server.
+func New(log *slog.Logger, repo Repo, client *http.Client, cfg config.CfgLinks) *LinkHealth {
+	return &LinkHealth{
+		log:      log,
+		repo:     repo,
+		checker:  linkcheck.New(client, cfg.MaxRedirects),
+		interval: cfg.CheckInterval,
+		timeout:  cfg.CheckTimeout,
+		workers:  max(cfg.CheckWorkers, 1),
+	}
+}
+
+// Run checks every link each interval in the background until ctx is
+// done, which also stops checks started with Start. It does not check
+// periodically when the interval is not positive.
+func (s *LinkHealth) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.jobs = ctx
+	s.mu.Unlock()
+
+	if s.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, _ = s.CheckAll(ctx)
+			}
+		}
+	}()
+}
+
+// Start checks every link in the background, failing when a check of
+// every link is already running. The check stops with the context given
+// to Run.
+func (s *LinkHealth) Start() error {
+	const op = "linkhealth.Start"
+	if !s.running.CompareAndSwap(false, true) {
+		s.log.Warn("link check already running", slog.String("op", op))
+		return fmt.Errorf("%s: %w", op, ErrCheckRunning)
+	}
+
+	s.mu.Lock()
+	ctx := s.jobs
+	s.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	go func() {
+		defer s.running.Store(false)
+		_, _ = s.checkAll(ctx)
+	}()
+	return nil
+}
+
+// CheckAll checks every link, unless a check of every link is already running.
+func (s *LinkHealth) CheckAll(ctx context.Context) (services.LinkCheckSummary, error) {
+	const op = "linkhealth.CheckAll"
+	if !s.running.CompareAndSwap(false, true) {
+		s.log.Warn("link check already running", slog.String("op", op))
+		return services.LinkCheckSummary{}, fmt.Errorf("%s: %w", op, ErrCheckRunning)
+	}
+	defer s.running.Store(false)
+
+	return s.checkAll(ctx)
+}
+
+func (s *LinkHealth) checkAll(ctx context.Context) (services.LinkCheckSummary, error) {
+	const op = "linkhealth.CheckAll"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start checking all links")
+	links, err := s.repo.GetLinksToCheck(0)
+	if err != nil {
+		log.Error("failed to fetch links", slog.String("err", err.Error()))
+		return services.LinkCheckSummary{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	checked, err := s.check(ctx, links)
+	if err != nil {
+		log.Error("failed to check links", slog.String("err", err.Error()))
+		return services.LinkCheckSummary{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	summary := services.LinkCheckSummary{Checked: len(checked)}
+	for _, link := range checked {
+		if link.CheckError != "" || link.StatusCode >= http.StatusBadRequest {
+			summary.Broken++
+		}
+	}
+	log.Info("successfully checked all links",
+		slog.Int("checked", summary.Checked),
+		slog.Int("broken", summary.Broken),
+	)
+	return summary, nil
+}
+
+// CheckMusic checks the links of one song and returns them with the outcome.
+func (s *LinkHealth) CheckMusic(ctx context.Context, musicId int) ([]models.MusicLink, error) {
+	const op = "linkhealth.CheckMusic"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start checking song links")
+	links, err := s.repo.GetLinksToCheck(musicId)
+	if err != nil {
+		log.Error("failed to fetch links", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(links) == 0 {
+		log.Warn("song has no links")
+		return nil, fmt.Errorf("%s: %w", op, ErrLinkNotFound)
+	}
+
+	checked, err := s.check(ctx, links)
+	if err != nil {
+		log.Error("failed to check links", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("successfully checked song links")
+	return checked, nil
+}
+
+func (s *LinkHealth) GetBroken(countLinks, page int) ([]models.BrokenLink, error) {
+	const op = "linkhealth.GetBroken"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start fetching broken links")
+	links, err := s.repo.GetBrokenLinks(countLinks, page)
+	if err != nil {
+		if errors.Is(err, musicrepo.ErrLinkNotFound) {
+			log.Warn("no broken links", slog.String("err", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, ErrLinkNotFound)
+		}
+		log.Error("failed to fetch broken links", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("successfully fetched broken links")
+	return links, nil
+}
+
+// check checks links with the configured number of workers and records
+// the outcomes. Links deleted while being checked are skipped.
+func (s *LinkHealth) check(ctx context.Context, links []models.MusicLink) ([]models.MusicLink, error) {
+	jobs := make(chan int)
+	errs := make(chan error, len(links))
+	var wg sync.WaitGroup
+	for range min(s.workers, len(links)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				links[i].LinkCheck = s.checkOne(ctx, links[i].URL)
+				err := s.repo.SetLinkCheck(links[i].Id, links[i].LinkCheck)
+				if err != nil && !errors.Is(err, musicrepo.ErrLinkNotFound) {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	for i := range links {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (s *LinkHealth) checkOne(ctx context.Context, url string) models.LinkCheck {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	res := s.checker.Check(ctx, url)
+	now := time.Now()
+	check := models.LinkCheck{
+		StatusCode:  res.StatusCode,
+		RedirectURL: res.RedirectURL,
+		CheckedAt:   &now,
+	}
+	if res.Err != nil {
+		check.CheckError = res.Err.Error()
+	}
+	return check
+}
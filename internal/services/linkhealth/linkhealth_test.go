@@ -0,0 +1,201 @@
+package linkhealth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"library-music/internal/config"
+	"library-music/internal/domain/models"
+	"library-music/internal/storage/music"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// linkRepo keeps links in memory, recording the checks set on them.
+type linkRepo struct {
+	mu      sync.Mutex
+	links   []models.MusicLink
+	checks  map[int]models.LinkCheck
+	setErr  error
+	broken  []models.BrokenLink
+	listErr error
+}
+
+func (r *linkRepo) GetLinksToCheck(musicId int) ([]models.MusicLink, error) {
+	var links []models.MusicLink
+	for _, link := range r.links {
+		if musicId == 0 || link.MusicId == musicId {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (r *linkRepo) SetLinkCheck(linkId int, check models.LinkCheck) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.setErr != nil {
+		return r.setErr
+	}
+	if r.checks == nil {
+		r.checks = make(map[int]models.LinkCheck)
+	}
+	r.checks[linkId] = check
+	return nil
+}
+
+func (r *linkRepo) GetBrokenLinks(countLinks, page int) ([]models.BrokenLink, error) {
+	return r.broken, r.listErr
+}
+
+func newService(repo Repo, client *http.Client) *LinkHealth {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return New(log, repo, client, config.CfgLinks{
+		CheckTimeout: time.Second,
+		CheckWorkers: 2,
+		MaxRedirects: 2,
+	})
+}
+
+func TestCheckMusic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	repo := &linkRepo{links: []models.MusicLink{
+		{Id: 1, MusicId: 7, URL: srv.URL + "/ok"},
+		{Id: 2, MusicId: 7, URL: srv.URL + "/moved"},
+		{Id: 3, MusicId: 7, URL: srv.URL + "/gone"},
+		{Id: 4, MusicId: 8, URL: srv.URL + "/ok"},
+	}}
+	s := newService(repo, srv.Client())
+
+	got, err := s.CheckMusic(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("CheckMusic() error = %v", err)
+	}
+
+	want := map[int]models.LinkCheck{
+		1: {StatusCode: http.StatusOK},
+		2: {StatusCode: http.StatusOK, RedirectURL: srv.URL + "/ok"},
+		3: {StatusCode: http.StatusNotFound},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CheckMusic() returned %d links, want %d", len(got), len(want))
+	}
+	for _, link := range got {
+		w := want[link.Id]
+		if link.StatusCode != w.StatusCode || link.RedirectURL != w.RedirectURL || link.CheckError != "" || link.CheckedAt == nil {
+			t.Errorf("link %d checked as %+v, want %+v", link.Id, link.LinkCheck, w)
+		}
+		if stored := repo.checks[link.Id]; stored != link.LinkCheck {
+			t.Errorf("link %d stored as %+v, want %+v", link.Id, stored, link.LinkCheck)
+		}
+	}
+	if _, ok := repo.checks[4]; ok {
+		t.Error("CheckMusic() checked a link of another song")
+	}
+}
+
+func TestCheckMusicErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	links := []models.MusicLink{{Id: 1, MusicId: 7, URL: srv.URL}}
+	failed := errors.New("connection lost")
+
+	tests := []struct {
+		name    string
+		repo    *linkRepo
+		musicId int
+		err     error
+	}{
+		{name: "no links", repo: &linkRepo{links: links}, musicId: 8, err: ErrLinkNotFound},
+		{name: "link deleted while checked", repo: &linkRepo{links: links, setErr: musicrepo.ErrLinkNotFound}, musicId: 7},
+		{name: "storing the check fails", repo: &linkRepo{links: links, setErr: failed}, musicId: 7, err: failed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newService(tt.repo, srv.Client()).CheckMusic(context.Background(), tt.musicId)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("CheckMusic() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestGetBroken(t *testing.T) {
+	broken := []models.BrokenLink{{
+		MusicLink: models.MusicLink{Id: 3, LinkCheck: models.LinkCheck{StatusCode: http.StatusNotFound}},
+		Song:      "song",
+		Group:     "group",
+	}}
+	failed := errors.New("connection lost")
+
+	tests := []struct {
+		name string
+		repo *linkRepo
+		want int
+		err  error
+	}{
+		{name: "found", repo: &linkRepo{broken: broken}, want: 1},
+		{name: "none", repo: &linkRepo{listErr: musicrepo.ErrLinkNotFound}, err: ErrLinkNotFound},
+		{name: "failed", repo: &linkRepo{listErr: failed}, err: failed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newService(tt.repo, http.DefaultClient).GetBroken(10, 1)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("GetBroken() error = %v, want %v", err, tt.err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("GetBroken() returned %d links, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestStartStopsWithRun(t *testing.T) {
+	started := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	repo := &linkRepo{links: []models.MusicLink{{Id: 1, MusicId: 7, URL: srv.URL}}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, srv.Client(), config.CfgLinks{CheckWorkers: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Run(ctx)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	<-started
+	if err := s.Start(); !errors.Is(err, ErrCheckRunning) {
+		t.Errorf("second Start() error = %v, want %v", err, ErrCheckRunning)
+	}
+
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.running.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("check started with Start kept running after the Run context was done")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,11 @@
+package lyrics
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	SetTimings(musicId int, lines []models.TimedLine) error
+	DeleteTimings(musicId int) error
+	GetTimings(musicId int) (models.SyncedLyrics, error)
+}
@@ -0,0 +1,136 @@
+package lyrics
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/services"
+	"library-music/internal/storage/lyrics"
+	"library-music/pkg/lrc"
+	"library-music/pkg/mapper"
+	"log/slog"
+	"strconv"
+)
+
+var (
+	ErrMusicNotFound     = errors.New("music not found")
+	ErrLyricsNotFound    = errors.New("lyrics not found")
+	ErrInvalidLRC        = errors.New("invalid lrc")
+	ErrUnsupportedFormat = errors.New("unsupported format")
+)
+
+// Formats synced lyrics can be exported in as text.
+const (
+	FormatLRC = "lrc"
+	FormatVTT = "vtt"
+)
+
+type Lyrics struct {
+	log    *slog.Logger
+	repo   Repo
+	mapper mapper.LyricsMapper
+}
+
+func New(log *slog.Logger, repo Repo) *Lyrics {
+	return &Lyrics{
+		log:    log,
+		repo:   repo,
+		mapper: mapper.LyricsMapper{},
+	}
+}
+
+// UploadLRC replaces the timings of a song with those of an LRC file,
+// returning the number of timed lines stored.
+func (s *Lyrics) UploadLRC(musicId int, data string) (int, error) {
+	const op = "lyrics.UploadLRC"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	parsed, err := lrc.Parse(data)
+	if err != nil {
+		log.Warn("invalid lrc", slog.String("err", err.Error()))
+		return 0, fmt.Errorf("%s: %w: %w", op, ErrInvalidLRC, err)
+	}
+
+	log.Info("start storing lyric timings")
+	lines := s.mapper.LRCToTimedLines(parsed)
+	if err = s.repo.SetTimings(musicId, lines); err != nil {
+		return 0, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully stored lyric timings", slog.String("lines", strconv.Itoa(len(lines))))
+	return len(lines), nil
+}
+
+func (s *Lyrics) DeleteTimings(musicId int) error {
+	const op = "lyrics.DeleteTimings"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start deleting lyric timings")
+	if err := s.repo.DeleteTimings(musicId); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully deleted lyric timings")
+	return nil
+}
+
+func (s *Lyrics) GetSynced(musicId int) (services.SyncedLyricsToGet, error) {
+	const op = "lyrics.GetSynced"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start fetching lyric timings")
+	synced, err := s.repo.GetTimings(musicId)
+	if err != nil {
+		return services.SyncedLyricsToGet{}, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully fetched lyric timings")
+	return s.mapper.SyncedForGet(synced), nil
+}
+
+// ExportSynced renders the timings of a song as LRC or WebVTT.
+func (s *Lyrics) ExportSynced(musicId int, format string) (string, error) {
+	const op = "lyrics.ExportSynced"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+		slog.String("format", format),
+	)
+
+	if format != FormatLRC && format != FormatVTT {
+		log.Warn("unsupported format")
+		return "", fmt.Errorf("%s: %w", op, ErrUnsupportedFormat)
+	}
+
+	log.Info("start exporting lyric timings")
+	synced, err := s.repo.GetTimings(musicId)
+	if err != nil {
+		return "", s.wrapErr(log, op, err)
+	}
+
+	parsed := s.mapper.SyncedToLRC(synced)
+	log.Info("successfully exported lyric timings")
+	if format == FormatVTT {
+		return parsed.VTT(), nil
+	}
+	return parsed.String(), nil
+}
+
+func (s *Lyrics) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, lyricsrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, lyricsrepo.ErrLyricsNotFound):
+		log.Warn("lyrics not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrLyricsNotFound)
+	default:
+		log.Error("lyrics operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
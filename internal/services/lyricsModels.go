@@ -0,0 +1,85 @@
+package services
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type LyricSectionToGet struct {
+	Type    string   `json:"type" example:"chorus"`
+	Ordinal int      `json:"ordinal" example:"1"`
+	Lines   []string `json:"lines"`
+}
+
+type LyricsToGet struct {
+	MusicId  int                 `json:"musicId"`
+	Sections []LyricSectionToGet `json:"sections"`
+}
+
+type SyncedLyricsToGet struct {
+	MusicId int                `json:"musicId"`
+	Song    string             `json:"song"`
+	Group   string             `json:"group"`
+	Lines   []models.TimedLine `json:"lines"`
+}
+
+type LanguageToSet struct {
+	Lang string `json:"lang" validate:"omitempty,bcp47_language_tag" example:"en"`
+}
+
+type TranslationToSet struct {
+	Lang string `json:"lang" validate:"required,bcp47_language_tag" example:"de-CH"`
+	Text string `json:"text" validate:"required,max=100000"`
+}
+
+// TextPage is a page of lyrics with the zero-based indices of the verses
+// it shows, fully or in part.
+type TextPage struct {
+	Text         string `json:"text"`
+	Page         int    `json:"page"`
+	TotalPages   int    `json:"totalPages"`
+	VerseIndices []int  `json:"verseIndices"`
+}
+
+type VersePairsPage struct {
+	Verses       []VersePair `json:"verses"`
+	Page         int         `json:"page"`
+	TotalPages   int         `json:"totalPages"`
+	VerseIndices []int       `json:"verseIndices"`
+}
+
+// VersePair aligns a verse of the original text with its translation;
+// either side is empty when the texts have a different number of verses.
+type VersePair struct {
+	Original    string `json:"original"`
+	Translation string `json:"translation"`
+}
+
+// LyricMatchToGet locates a matching line for GetTextMusic: VerseIndex is
+// the index reported in verseIndices, Line counts from 1 within the verse
+// and Page is set when the search was given the countVerse of the pages.
+type LyricMatchToGet struct {
+	MusicId    int      `json:"musicId"`
+	Song       string   `json:"song"`
+	Group      string   `json:"group"`
+	VerseIndex int      `json:"verseIndex"`
+	Line       int      `json:"line"`
+	Page       int      `json:"page,omitempty"`
+	Text       string   `json:"text"`
+	Before     []string `json:"before"`
+	After      []string `json:"after"`
+}
+
+// LyricStatsToGet describes the lyrics of a song or of all the songs of a
+// group. Ratios are 0 when there are no words or lines.
+type LyricStatsToGet struct {
+	Songs       int `json:"songs" example:"1"`
+	Words       int `json:"words" example:"214"`
+	UniqueWords int `json:"uniqueWords" example:"87"`
+	// Richness is the share of unique words among all words.
+	Richness float64 `json:"richness" example:"0.41"`
+	// RepetitionRatio is the share of lines repeating an earlier line.
+	RepetitionRatio float64 `json:"repetitionRatio" example:"0.35"`
+	// AverageLineLength is in characters.
+	AverageLineLength float64            `json:"averageLineLength" example:"27.5"`
+	TopWords          []models.WordCount `json:"topWords"`
+}
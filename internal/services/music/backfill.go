@@ -0,0 +1,111 @@
+package music
+
+import (
+	"context"
+	"errors"
+	"library-music/internal/domain/models"
+	"library-music/internal/storage/music"
+	"library-music/pkg/lyrics"
+	"library-music/pkg/lyricstats"
+	"library-music/pkg/songkey"
+	"log/slog"
+	"strconv"
+)
+
+// Backfill fills in what songs stored before it was kept lack, so reads
+// never have to. It runs once at startup, in the background, and stops
+// between steps when ctx is done; a failed step is logged and retried on
+// the next start.
+func (s *Music) Backfill(ctx context.Context) {
+	const op = "music.Backfill"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	steps := []func(log *slog.Logger) error{
+		s.storeMissingSections,
+		s.storeMissingStats,
+		s.storeMissingSongKeys,
+	}
+
+	log.Info("start backfilling songs")
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			log.Warn("backfill stopped")
+			return
+		}
+		if err := step(log); err != nil {
+			log.Error("failed to backfill songs", slog.String("err", err.Error()))
+		}
+	}
+	log.Info("successfully backfilled songs")
+}
+
+// storeMissingSections parses and stores the sections of the songs added
+// before sections were stored, so that search finds them.
+func (s *Music) storeMissingSections(log *slog.Logger) error {
+	pending, err := s.repo.GetUnsectioned()
+	if err != nil {
+		return err
+	}
+
+	missing := make(map[int][]models.LyricSection, len(pending))
+	for _, p := range pending {
+		if sections := s.mapper.SectionsToLyricSections(lyrics.Parse(p.Text)); len(sections) > 0 {
+			missing[p.MusicId] = sections
+		}
+	}
+
+	if len(missing) > 0 {
+		if err = s.repo.SetSections(missing); err != nil {
+			return err
+		}
+		log.Info("stored missing sections", slog.String("songs", strconv.Itoa(len(missing))))
+	}
+	return nil
+}
+
+// storeMissingStats caches the statistics of the songs stored before they
+// were computed on Add and Update.
+func (s *Music) storeMissingStats(log *slog.Logger) error {
+	pending, err := s.repo.GetUnanalyzed()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pending {
+		stats := s.mapper.StatsToLyricStats(lyricstats.Analyze(p.Text, p.Lang))
+		if err = s.repo.SetStats(p.MusicId, stats); err != nil && !errors.Is(err, musicrepo.ErrMusicNotFound) {
+			return err
+		}
+	}
+
+	if len(pending) > 0 {
+		log.Info("computed missing lyrics statistics", slog.String("songs", strconv.Itoa(len(pending))))
+	}
+	return nil
+}
+
+// storeMissingSongKeys stores the comparison keys of the songs added before
+// keys were, so that duplicates are found by them.
+func (s *Music) storeMissingSongKeys(log *slog.Logger) error {
+	titles, err := s.repo.GetSongTitles()
+	if err != nil {
+		return err
+	}
+
+	missing := map[int]string{}
+	for _, t := range titles {
+		if t.SongKey == "" {
+			missing[t.Id] = songkey.Key(t.Song)
+		}
+	}
+
+	if len(missing) > 0 {
+		if err = s.repo.SetSongKeys(missing); err != nil {
+			return err
+		}
+		log.Info("stored missing song keys", slog.String("songs", strconv.Itoa(len(missing))))
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/storage/music"
+	"library-music/pkg/chordpro"
+	"log/slog"
+	"slices"
+	"strconv"
+)
+
+var (
+	ErrChordProNotFound  = errors.New("chordpro not found")
+	ErrInvalidChordPro   = errors.New("invalid chordpro")
+	ErrUnsupportedFormat = errors.New("unsupported format")
+)
+
+// Formats a ChordPro document can be rendered in.
+const (
+	FormatLyrics   = "lyrics"
+	FormatSheet    = "text"
+	FormatHTML     = "html"
+	FormatChordPro = "chordpro"
+)
+
+var formats = []string{FormatLyrics, FormatSheet, FormatHTML, FormatChordPro}
+
+// SetChordPro validates a ChordPro document and stores it; the lyrics it
+// renders to become the text of the song.
+func (s *Music) SetChordPro(id int, document string) error {
+	const op = "music.SetChordPro"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	song, err := chordpro.Parse(document)
+	if err != nil {
+		log.Warn("invalid chordpro", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w: %w", op, ErrInvalidChordPro, err)
+	}
+
+	lang, err := s.manualLang(id)
+	if err != nil {
+		return s.chordProErr(log, op, err)
+	}
+
+	text := models.Music{Text: song.Lyrics()}
+	s.analyze(&text, lang)
+
+	log.Info("start storing chordpro")
+	if err = s.repo.SetChordPro(id, document, text); err != nil {
+		return s.chordProErr(log, op, err)
+	}
+	log.Info("successfully stored chordpro")
+	return nil
+}
+
+func (s *Music) DeleteChordPro(id int) error {
+	const op = "music.DeleteChordPro"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start deleting chordpro")
+	if err := s.repo.DeleteChordPro(id); err != nil {
+		return s.chordProErr(log, op, err)
+	}
+	log.Info("successfully deleted chordpro")
+	return nil
+}
+
+// RenderChordPro renders the document of a song in format, first moved by
+// transpose semitones and then arranged for a capo on the given fret.
+// A negative capo keeps the capo the document was written for.
+func (s *Music) RenderChordPro(id int, format string, transpose, capo int) (string, error) {
+	const op = "music.RenderChordPro"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+		slog.String("format", format),
+	)
+
+	if !slices.Contains(formats, format) {
+		log.Warn("unsupported format")
+		return "", fmt.Errorf("%s: %w", op, ErrUnsupportedFormat)
+	}
+
+	log.Info("start rendering chordpro")
+	document, err := s.repo.GetChordPro(id)
+	if err != nil {
+		return "", s.chordProErr(log, op, err)
+	}
+
+	song, err := chordpro.Parse(document)
+	if err != nil {
+		log.Error("stored chordpro is invalid", slog.String("err", err.Error()))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	song = song.Transpose(transpose)
+	if capo >= 0 {
+		if song, err = song.WithCapo(capo); err != nil {
+			log.Warn("invalid capo", slog.String("err", err.Error()))
+			return "", fmt.Errorf("%s: %w: %w", op, ErrInvalidChordPro, err)
+		}
+	}
+
+	log.Info("successfully rendered chordpro")
+	switch format {
+	case FormatLyrics:
+		return song.Lyrics(), nil
+	case FormatSheet:
+		return song.Sheet(), nil
+	case FormatHTML:
+		return song.HTML(), nil
+	default:
+		return song.String(), nil
+	}
+}
+
+func (s *Music) chordProErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, musicrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, musicrepo.ErrChordProNotFound):
+		log.Warn("chordpro not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrChordProNotFound)
+	default:
+		log.Error("chordpro operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
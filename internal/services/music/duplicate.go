@@ -0,0 +1,140 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/music"
+	"library-music/pkg/songkey"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrDifferentGroups = errors.New("songs are of different groups")
+
+// GetDuplicates returns up to limit pairs of songs of the same group whose
+// normalized titles are at least minScore alike, most alike first.
+func (s *Music) GetDuplicates(minScore float64, limit int) ([]services.DuplicatePair, error) {
+	const op = "music.GetDuplicates"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start looking for duplicate songs")
+	titles, err := s.repo.GetSongTitles()
+	if err != nil {
+		log.Error("failed to fetch song titles", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Keys of songs the backfill has not reached yet are computed, not stored.
+	for i, t := range titles {
+		if t.SongKey == "" {
+			titles[i].SongKey = songkey.Key(t.Song)
+		}
+	}
+
+	pairs := []services.DuplicatePair{}
+	for start := 0; start < len(titles); {
+		end := start
+		for end < len(titles) && titles[end].GroupId == titles[start].GroupId {
+			end++
+		}
+
+		group := titles[start:end]
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				score := songkey.Similarity(group[i].SongKey, group[j].SongKey)
+				if score < minScore {
+					continue
+				}
+				pairs = append(pairs, services.DuplicatePair{
+					Group:  group[i].Group,
+					Score:  score,
+					First:  services.DuplicateSong{Id: group[i].Id, Song: group[i].Song},
+					Second: services.DuplicateSong{Id: group[j].Id, Song: group[j].Song},
+				})
+			}
+		}
+		start = end
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Score > pairs[j].Score
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	log.Info("successfully looked for duplicate songs", slog.String("pairs", strconv.Itoa(len(pairs))))
+	return pairs, nil
+}
+
+// Merge folds a duplicate song into the song kept, taking the listed
+// fields from the duplicate. Playlists, favorites, albums, tags, genres,
+// credits, translations and relations of the duplicate move to the song
+// kept, and the duplicate is deleted.
+func (s *Music) Merge(merge services.MusicToMerge) error {
+	const op = "music.Merge"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("keepId", strconv.Itoa(merge.KeepId)),
+		slog.String("removeId", strconv.Itoa(merge.RemoveId)),
+		slog.String("take", strings.Join(merge.Take, ",")),
+	)
+
+	log.Info("start merging songs")
+	removed, err := s.repo.GetById(merge.RemoveId)
+	if err != nil {
+		return s.mergeErr(log, op, err)
+	}
+
+	var taken models.Music
+	for _, field := range merge.Take {
+		switch field {
+		case "song":
+			taken.Song = removed.Song
+			taken.SongKey = songkey.Key(removed.Song)
+		case "text":
+			taken.Text = removed.Text
+		case "link":
+			taken.Link = removed.Link
+		case "releaseDate":
+			taken.ReleaseDate = removed.ReleaseDate
+		}
+	}
+
+	if taken.Text != "" {
+		lang, err := s.manualLang(merge.KeepId)
+		if err != nil {
+			return s.mergeErr(log, op, err)
+		}
+		s.analyze(&taken, lang)
+	}
+
+	keys, err := s.repo.Merge(merge.KeepId, merge.RemoveId, taken)
+	if err != nil {
+		return s.mergeErr(log, op, err)
+	}
+	s.deleteFiles(log, keys)
+	log.Info("successfully merged songs")
+	return nil
+}
+
+func (s *Music) mergeErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, musicrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, musicrepo.ErrDifferentGroups):
+		log.Warn("songs are of different groups", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrDifferentGroups)
+	case errors.Is(err, musicrepo.ErrMusicAlreadyExists):
+		log.Warn("music already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicAlreadyExists)
+	}
+	log.Error("failed to merge songs", slog.String("err", err.Error()))
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -6,10 +6,33 @@ import (
 
 type Repo interface {
 	Add(music models.Music) (int, error)
-	Delete(musicId int) error
+	Delete(musicId int) ([]string, error)
 	Update(music models.Music, id int) error
 	GetById(musicId int) (models.Music, error)
-	GetAll(params models.Music, countSongs, page int) ([]models.Music, error)
+	GetAll(params models.MusicFilter, countSongs, page int) ([]models.Music, error)
+	GetAllWithFacets(params models.MusicFilter, countSongs, page int) ([]models.Music, models.MusicFacets, error)
 	Get(song, group string) (models.Music, error)
-	GetText(song, group string) (string, error)
+	GetLyrics(song, group string) (models.Lyrics, error)
+	SearchLines(phrase string, context, countMatches, page int) ([]models.LyricMatch, error)
+	GetUnsectioned() ([]models.Lyrics, error)
+	SetSections(sections map[int][]models.LyricSection) error
+	SetChordPro(id int, document string, lyrics models.Music) error
+	DeleteChordPro(id int) error
+	GetChordPro(id int) (string, error)
+	SetLanguage(id int, lang string, stats models.LyricStats) error
+	SetTranslation(musicId int, translation models.Translation) error
+	DeleteTranslation(musicId int, lang string) error
+	GetTranslations(musicId int) ([]models.Translation, error)
+	SetStats(musicId int, stats models.LyricStats) error
+	GetStats(musicId, top int) (models.LyricStats, error)
+	GetGroupStats(group string, top int) (models.LyricStats, error)
+	GetUnanalyzed() ([]models.Lyrics, error)
+	GetSongTitles() ([]models.SongTitle, error)
+	SetSongKeys(keys map[int]string) error
+	Merge(keepId, removeId int, taken models.Music) ([]string, error)
+	AddLink(musicId int, link models.MusicLink) (int, error)
+	DeleteLink(musicId, linkId int) error
+	GetLinks(musicIds []int) (map[int][]models.MusicLink, error)
+	GetArtwork(musicIds []int) (map[int][]models.Artwork, error)
+	GetAudio(musicIds []int) (map[int]models.Audio, error)
 }
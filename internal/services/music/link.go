@@ -0,0 +1,117 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/music"
+	"library-music/pkg/links"
+	"log/slog"
+	"strconv"
+)
+
+var (
+	ErrInvalidLink       = errors.New("invalid link")
+	ErrProviderMismatch  = errors.New("link does not point to the provider")
+	ErrLinkNotFound      = errors.New("link not found")
+	ErrLinkAlreadyExists = errors.New("link already exists")
+)
+
+// AddLink adds a link to a song. Without a provider, the provider is
+// detected from the link.
+func (s *Music) AddLink(id int, link services.LinkToAdd) (int, error) {
+	const op = "music.AddLink"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+		slog.String("url", link.URL),
+	)
+
+	parsed, err := links.Parse(link.URL, link.Provider)
+	if err != nil {
+		log.Warn("invalid link", slog.String("err", err.Error()))
+		if errors.Is(err, links.ErrProviderMismatch) {
+			return 0, fmt.Errorf("%s: %w", op, ErrProviderMismatch)
+		}
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidLink)
+	}
+
+	log.Info("start adding a link")
+	linkId, err := s.repo.AddLink(id, s.mapper.LinkToMusicLink(parsed))
+	if err != nil {
+		return 0, s.linkErr(log, op, err)
+	}
+	log.Info("successfully added a link")
+	return linkId, nil
+}
+
+func (s *Music) DeleteLink(id, linkId int) error {
+	const op = "music.DeleteLink"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+		slog.String("linkId", strconv.Itoa(linkId)),
+	)
+
+	log.Info("start deleting a link")
+	if err := s.repo.DeleteLink(id, linkId); err != nil {
+		return s.linkErr(log, op, err)
+	}
+	log.Info("successfully deleted a link")
+	return nil
+}
+
+func (s *Music) GetLinks(id int) ([]models.MusicLink, error) {
+	const op = "music.GetLinks"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start fetching links")
+	res, err := s.repo.GetLinks([]int{id})
+	if err != nil {
+		return nil, s.linkErr(log, op, err)
+	}
+
+	if len(res[id]) == 0 {
+		log.Warn("song has no links")
+		return nil, fmt.Errorf("%s: %w", op, ErrLinkNotFound)
+	}
+	log.Info("successfully fetched links")
+	return res[id], nil
+}
+
+// linksOf types the main link of a song, kept as other when it is not a
+// link to a song at a known provider.
+func (s *Music) linksOf(link string) []models.MusicLink {
+	if link == "" {
+		return nil
+	}
+
+	parsed, err := links.Parse(link, "")
+	if err != nil {
+		if parsed, err = links.Parse(link, links.Other); err != nil {
+			return nil
+		}
+	}
+	return []models.MusicLink{s.mapper.LinkToMusicLink(parsed)}
+}
+
+func (s *Music) linkErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, musicrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, musicrepo.ErrLinkNotFound):
+		log.Warn("link not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrLinkNotFound)
+	case errors.Is(err, musicrepo.ErrLinkAlreadyExists):
+		log.Warn("link already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrLinkAlreadyExists)
+	default:
+		log.Error("link operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
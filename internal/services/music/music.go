@@ -6,28 +6,45 @@ import (
 	"library-music/internal/domain/models"
 	"library-music/internal/services"
 	"library-music/internal/storage/music"
+	"library-music/pkg/blob"
+	"library-music/pkg/explicit"
+	"library-music/pkg/langdetect"
+	"library-music/pkg/lyrics"
+	"library-music/pkg/lyricstats"
 	"library-music/pkg/mapper"
+	"library-music/pkg/songkey"
 	"log/slog"
 	"strconv"
-	"strings"
 )
 
 type Music struct {
-	log    *slog.Logger
-	repo   Repo
-	mapper mapper.MusicMapper
+	log      *slog.Logger
+	repo     Repo
+	store    blob.Store
+	mapper   mapper.MusicMapper
+	explicit *explicit.Filter
 }
 
 var (
 	ErrMusicNotFound      = errors.New("music not found")
 	ErrMusicAlreadyExists = errors.New("music already exists")
+	ErrAlbumNotFound      = errors.New("album not found")
+	ErrTrackTaken         = errors.New("track number already taken")
+	ErrMissingReleaseDate = errors.New("song and album have no release date")
+	ErrPageOutOfRange     = errors.New("page is out of range")
+	ErrInvalidPagination  = errors.New("invalid pagination")
 )
 
-func New(log *slog.Logger, repo Repo) *Music {
+// New creates the music service; words flags lyrics as explicit and
+// artwork URLs are built under mediaURL. Files of deleted songs are
+// deleted from store.
+func New(log *slog.Logger, repo Repo, store blob.Store, words *explicit.Filter, mediaURL string) *Music {
 	return &Music{
-		log:    log,
-		repo:   repo,
-		mapper: mapper.MusicMapper{},
+		log:      log,
+		repo:     repo,
+		store:    store,
+		mapper:   mapper.MusicMapper{MediaURL: mediaURL},
+		explicit: words,
 	}
 }
 
@@ -46,6 +63,10 @@ func (s *Music) Add(music models.Music) (int, error) {
 		slog.String("ReleaseDate", music.ReleaseDate.String()),
 	)
 
+	s.analyze(&music, "")
+	music.SongKey = songkey.Key(music.Song)
+	music.Links = s.linksOf(music.Link)
+
 	log.Info("start adding song")
 	id, err := s.repo.Add(music)
 	if err != nil {
@@ -53,6 +74,18 @@ func (s *Music) Add(music models.Music) (int, error) {
 			log.Warn("music already exists", slog.String("err", err.Error()))
 			return 0, fmt.Errorf("%s: %w", op, ErrMusicAlreadyExists)
 		}
+		if errors.Is(err, musicrepo.ErrAlbumNotFound) {
+			log.Warn("album not found", slog.String("err", err.Error()))
+			return 0, fmt.Errorf("%s: %w", op, ErrAlbumNotFound)
+		}
+		if errors.Is(err, musicrepo.ErrTrackTaken) {
+			log.Warn("track number already taken", slog.String("err", err.Error()))
+			return 0, fmt.Errorf("%s: %w", op, ErrTrackTaken)
+		}
+		if errors.Is(err, musicrepo.ErrMissingReleaseDate) {
+			log.Warn("no release date", slog.String("err", err.Error()))
+			return 0, fmt.Errorf("%s: %w", op, ErrMissingReleaseDate)
+		}
 		log.Error("failed to add a song", slog.String("err", err.Error()))
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
@@ -76,7 +109,7 @@ func (s *Music) Delete(id int) error {
 		slog.String("id", strconv.FormatInt(int64(id), 10)),
 	)
 	log.Info("start deleting a song")
-	err := s.repo.Delete(id)
+	keys, err := s.repo.Delete(id)
 	if err != nil {
 		if errors.Is(err, musicrepo.ErrMusicNotFound) {
 			log.Warn("music not found", slog.String("err", err.Error()))
@@ -85,6 +118,7 @@ func (s *Music) Delete(id int) error {
 		log.Error("failed to delete a song", slog.String("err", err.Error()))
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	s.deleteFiles(log, keys)
 	log.Info("successfully deleted a song")
 	log.Debug(
 		"delete song",
@@ -116,20 +150,38 @@ func (s *Music) Update(music services.MusicToUpdate, id int) error {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if data.Text != "" {
+		lang, err := s.manualLang(id)
+		if err != nil {
+			if errors.Is(err, musicrepo.ErrMusicNotFound) {
+				log.Warn("music not found", slog.String("err", err.Error()))
+				return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+			}
+			log.Error("failed to fetch a song", slog.String("err", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		s.analyze(&data, lang)
+	}
+
+	if data.Song != "" {
+		data.SongKey = songkey.Key(data.Song)
+	}
+	data.Links = s.linksOf(data.Link)
+
 	log.Info("start updating a song")
 	err = s.repo.Update(data, id)
 	if err != nil {
 		if errors.Is(err, musicrepo.ErrMusicNotFound) {
-			log.Warn("music not found", err.Error())
+			log.Warn("music not found", slog.String("err", err.Error()))
 			return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
 		}
 
 		if errors.Is(err, musicrepo.ErrMusicAlreadyExists) {
-			log.Warn("music already exists", err.Error())
+			log.Warn("music already exists", slog.String("err", err.Error()))
 			return fmt.Errorf("%s: %w", op, ErrMusicAlreadyExists)
 		}
 
-		log.Error("failed to update a song", err.Error())
+		log.Error("failed to update a song", slog.String("err", err.Error()))
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	log.Info("successfully updated a song")
@@ -158,7 +210,7 @@ func (s *Music) GetAll(params services.MusicFilterParams, countSongs, page int)
 	)
 
 	log.Info("start fetching all songs")
-	res, err := s.repo.GetAll(s.mapper.FilterToMusic(params), countSongs, page)
+	res, err := s.repo.GetAll(s.mapper.FilterToMusicFilter(params), countSongs, page)
 	if err != nil {
 		if errors.Is(err, musicrepo.ErrMusicNotFound) {
 			log.Warn("failed to get all songs", slog.String("err", err.Error()))
@@ -168,6 +220,11 @@ func (s *Music) GetAll(params services.MusicFilterParams, countSongs, page int)
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err = s.withDetails(res); err != nil {
+		log.Error("failed to fetch song details", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	arr := make([]services.MusicToGet, len(res))
 	for i, v := range res {
 		arr[i] = s.mapper.MusicForGet(v)
@@ -177,6 +234,36 @@ func (s *Music) GetAll(params services.MusicFilterParams, countSongs, page int)
 	return arr, nil
 }
 
+func (s *Music) GetAllWithFacets(params services.MusicFilterParams, countSongs, page int) ([]services.MusicToGet, models.MusicFacets, error) {
+	const op = "music.GetAllWithFacets"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start fetching songs with facets")
+	res, facets, err := s.repo.GetAllWithFacets(s.mapper.FilterToMusicFilter(params), countSongs, page)
+	if err != nil {
+		if errors.Is(err, musicrepo.ErrMusicNotFound) {
+			log.Warn("failed to get songs with facets", slog.String("err", err.Error()))
+			return nil, models.MusicFacets{}, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		log.Error("failed to fetch songs with facets", slog.String("err", err.Error()))
+		return nil, models.MusicFacets{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.withDetails(res); err != nil {
+		log.Error("failed to fetch song details", slog.String("err", err.Error()))
+		return nil, models.MusicFacets{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	arr := make([]services.MusicToGet, len(res))
+	for i, v := range res {
+		arr[i] = s.mapper.MusicForGet(v)
+	}
+	log.Info("successfully fetched songs with facets")
+	return arr, facets, nil
+}
+
 func (s *Music) Get(song, group string) (services.MusicToGet, error) {
 	const op = "music.Get"
 	log := s.log.With(
@@ -199,6 +286,13 @@ func (s *Music) Get(song, group string) (services.MusicToGet, error) {
 		log.Error("failed to get a song", slog.String("err", err.Error()))
 		return services.MusicToGet{}, fmt.Errorf("%s: %w", op, err)
 	}
+
+	found := []models.Music{music}
+	if err = s.withDetails(found); err != nil {
+		log.Error("failed to fetch song details", slog.String("err", err.Error()))
+		return services.MusicToGet{}, fmt.Errorf("%s: %w", op, err)
+	}
+	music = found[0]
 	log.Info("successfully fetched a song")
 
 	log.Debug(
@@ -213,7 +307,11 @@ func (s *Music) Get(song, group string) (services.MusicToGet, error) {
 	return s.mapper.MusicForGet(music), nil
 }
 
-func (s *Music) GetText(song, group string, countVerse, page int) (string, error) {
+// GetText returns a page of the lyrics of a song, paginated by mode with
+// size verses, lines or characters per page. With lang set, the text
+// comes from the translation closest to it, or from the original text
+// when that is in lang.
+func (s *Music) GetText(song, group, lang, mode string, size, page int) (services.TextPage, error) {
 	const op = "music.GetText"
 	log := s.log.With(
 		slog.String("op", op),
@@ -223,33 +321,203 @@ func (s *Music) GetText(song, group string, countVerse, page int) (string, error
 		"getting song",
 		slog.String("song", song),
 		slog.String("group", group),
-		slog.String("countVerse", strconv.FormatInt(int64(countVerse), 10)),
-		slog.String("page", strconv.FormatInt(int64(countVerse), 10)),
+		slog.String("lang", lang),
+		slog.String("mode", mode),
+		slog.String("size", strconv.Itoa(size)),
+		slog.String("page", strconv.Itoa(page)),
 	)
 
 	log.Info("fetching a song")
-	text, err := s.repo.GetText(song, group)
+	found, err := s.lyrics(log, op, song, group)
 	if err != nil {
-		if errors.Is(err, musicrepo.ErrMusicNotFound) {
-			log.Warn("music not found", slog.String("err", ErrMusicNotFound.Error()))
-			return "", fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		return services.TextPage{}, err
+	}
+
+	verses := s.verses(found.Sections)
+	if lang != "" {
+		if verses, err = s.translatedVerses(log, op, found, lang); err != nil {
+			return services.TextPage{}, err
 		}
+	}
 
-		log.Error("failed to fetch a song", slog.String("err", err.Error()))
-		return "", fmt.Errorf("%s: %w", op, err)
+	pages, err := lyrics.Paginate(verses, mode, size)
+	if err != nil {
+		log.Warn("invalid pagination", slog.String("err", err.Error()))
+		return services.TextPage{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidPagination, err)
 	}
 
-	verses := strings.Split(text, "\n\n")
-	if len(verses)/countVerse < page {
+	if page > len(pages) {
 		log.Warn("page is out of range")
-		return "", fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		return services.TextPage{}, fmt.Errorf("%s: %w", op, ErrPageOutOfRange)
 	}
 	log.Info("successfully fetched a song")
 
-	start := countVerse * (page - 1)
-	end := countVerse*(page-1) + countVerse
-	result := strings.Join(verses[start:end], "\n\n")
+	result := pages[page-1]
+	log.Debug("text", slog.String("text", result.Text))
+	return services.TextPage{
+		Text:         result.Text,
+		Page:         page,
+		TotalPages:   len(pages),
+		VerseIndices: result.Verses,
+	}, nil
+}
 
-	log.Debug("text", result)
-	return result, nil
+// GetStructure returns the lyrics of a song split into typed sections.
+func (s *Music) GetStructure(song, group string) (services.LyricsToGet, error) {
+	const op = "music.GetStructure"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start fetching song structure")
+	found, err := s.lyrics(log, op, song, group)
+	if err != nil {
+		return services.LyricsToGet{}, err
+	}
+	log.Info("successfully fetched song structure")
+	return s.mapper.LyricsForGet(found), nil
+}
+
+// SearchLyrics returns a page of the lines containing phrase with context
+// lines around each; countVerse, when set, is the page size of the
+// GetTextMusic pages to link the matches to.
+func (s *Music) SearchLyrics(phrase string, context, countVerse, countMatches, page int) ([]services.LyricMatchToGet, error) {
+	const op = "music.SearchLyrics"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Debug(
+		"parameters",
+		slog.String("phrase", phrase),
+		slog.String("context", strconv.Itoa(context)),
+		slog.String("countVerse", strconv.Itoa(countVerse)),
+		slog.String("countMatches", strconv.Itoa(countMatches)),
+		slog.String("page", strconv.Itoa(page)),
+	)
+
+	log.Info("start searching lyrics")
+	res, err := s.repo.SearchLines(phrase, context, countMatches, page)
+	if err != nil {
+		if errors.Is(err, musicrepo.ErrMusicNotFound) {
+			log.Warn("no lines found", slog.String("err", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		log.Error("failed to search lyrics", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	arr := make([]services.LyricMatchToGet, len(res))
+	for i, v := range res {
+		arr[i] = s.mapper.LyricMatchForGet(v, countVerse)
+	}
+	log.Info("successfully searched lyrics")
+	log.Debug(fmt.Sprintf("%d lines returned", len(res)))
+	return arr, nil
+}
+
+// lyrics fetches the lyrics of a song, parsing the text when the song
+// predates stored sections.
+func (s *Music) lyrics(log *slog.Logger, op, song, group string) (models.Lyrics, error) {
+	found, err := s.repo.GetLyrics(song, group)
+	if err != nil {
+		if errors.Is(err, musicrepo.ErrMusicNotFound) {
+			log.Warn("music not found", slog.String("err", err.Error()))
+			return models.Lyrics{}, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+
+		log.Error("failed to fetch lyrics", slog.String("err", err.Error()))
+		return models.Lyrics{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(found.Sections) == 0 {
+		found.Sections = s.mapper.SectionsToLyricSections(lyrics.Parse(found.Text))
+	}
+	return found, nil
+}
+
+// withDetails fetches the links, artwork and audio files of songs, one
+// query each.
+func (s *Music) withDetails(musics []models.Music) error {
+	ids := make([]int, len(musics))
+	for i, m := range musics {
+		ids[i] = m.Id
+	}
+
+	links, err := s.repo.GetLinks(ids)
+	if err != nil {
+		return err
+	}
+
+	artwork, err := s.repo.GetArtwork(ids)
+	if err != nil {
+		return err
+	}
+
+	audio, err := s.repo.GetAudio(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range musics {
+		musics[i].Links = links[musics[i].Id]
+		for _, a := range artwork[musics[i].Id] {
+			if a.MusicId != 0 {
+				musics[i].Cover = &a
+			} else {
+				musics[i].GroupImage = &a
+			}
+		}
+		if file, ok := audio[musics[i].Id]; ok {
+			musics[i].Audio = &file
+		}
+	}
+	return nil
+}
+
+// deleteFiles deletes the files of removed songs from the blob store. The
+// songs are gone either way, so failures are only logged.
+func (s *Music) deleteFiles(log *slog.Logger, keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(key); err != nil {
+			log.Warn("failed to delete a file of a removed song", slog.String("err", err.Error()))
+		}
+	}
+}
+
+// analyze derives the sections, statistics, language and explicit flag
+// of a song from its text. The language is detected unless lang, the one
+// an editor set by hand, is given.
+func (s *Music) analyze(music *models.Music, lang string) {
+	music.Sections = s.mapper.SectionsToLyricSections(lyrics.Parse(music.Text))
+	music.Lang = lang
+	if lang == "" {
+		music.Lang = langdetect.Detect(music.Text)
+	}
+	music.Explicit = s.explicit.Match(music.Text)
+	stats := s.mapper.StatsToLyricStats(lyricstats.Analyze(music.Text, music.Lang))
+	music.Stats = &stats
+}
+
+// manualLang returns the language an editor set by hand for the song, or
+// "" when it is left to detection.
+func (s *Music) manualLang(id int) (string, error) {
+	song, err := s.repo.GetById(id)
+	if err != nil {
+		return "", err
+	}
+
+	if !song.LangManual {
+		return "", nil
+	}
+	return song.Lang, nil
+}
+
+// verses renders each section as one verse of GetText pagination.
+func (s *Music) verses(sections []models.LyricSection) []string {
+	verses := make([]string, len(sections))
+	for i, section := range s.mapper.LyricSectionsToSections(sections) {
+		verses[i] = section.String()
+	}
+	return verses
 }
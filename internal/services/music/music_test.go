@@ -0,0 +1,371 @@
+package music
+
+import (
+	"context"
+	"errors"
+	"io"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/music"
+	"library-music/pkg/blob"
+	"library-music/pkg/explicit"
+	"library-music/pkg/lyrics"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lyricsRepo serves the lyrics of a single song; the rest of Repo is left
+// unimplemented.
+type lyricsRepo struct {
+	Repo
+	lyrics models.Lyrics
+}
+
+func (r lyricsRepo) GetLyrics(song, group string) (models.Lyrics, error) {
+	return r.lyrics, nil
+}
+
+func TestGetTextPages(t *testing.T) {
+	repo := lyricsRepo{lyrics: models.Lyrics{
+		Text: "[Verse 1]\none\ntwo\n\n[Chorus]\nla la\n\nthree\nfour\nfive",
+	}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+
+	tests := []struct {
+		name   string
+		mode   string
+		size   int
+		page   int
+		text   string
+		total  int
+		verses []int
+		err    error
+	}{
+		{name: "verses first page", mode: lyrics.ModeVerses, size: 2, page: 1, text: "[Verse 1]\none\ntwo\n\n[Chorus]\nla la", total: 2, verses: []int{0, 1}},
+		{name: "verses partial last page", mode: lyrics.ModeVerses, size: 2, page: 2, text: "three\nfour\nfive", total: 2, verses: []int{2}},
+		{name: "verses past the last page", mode: lyrics.ModeVerses, size: 2, page: 3, err: ErrPageOutOfRange},
+		{name: "lines partial last page", mode: lyrics.ModeLines, size: 3, page: 3, text: "four\nfive", total: 3, verses: []int{2}},
+		{name: "lines past the last page", mode: lyrics.ModeLines, size: 3, page: 4, err: ErrPageOutOfRange},
+		{name: "chars partial last page", mode: lyrics.ModeChars, size: 33, page: 2, text: "three\nfour\nfive", total: 2, verses: []int{2}},
+		{name: "chars past the last page", mode: lyrics.ModeChars, size: 33, page: 9, err: ErrPageOutOfRange},
+		{name: "unknown mode", mode: "words", size: 1, page: 1, err: ErrInvalidPagination},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetText("song", "group", "", tt.mode, tt.size, tt.page)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("GetText() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if got.Text != tt.text || got.Page != tt.page || got.TotalPages != tt.total || !reflect.DeepEqual(got.VerseIndices, tt.verses) {
+				t.Errorf("GetText() = %+v, want text %q, page %d of %d, verses %v", got, tt.text, tt.page, tt.total, tt.verses)
+			}
+		})
+	}
+}
+
+// searchRepo records the sections, statistics and song keys stored.
+type searchRepo struct {
+	Repo
+	unsectioned []models.Lyrics
+	stored      map[int][]models.LyricSection
+	unanalyzed  []models.Lyrics
+	stats       map[int]models.LyricStats
+	titles      []models.SongTitle
+	keys        map[int]string
+}
+
+func (r *searchRepo) GetUnsectioned() ([]models.Lyrics, error) {
+	return r.unsectioned, nil
+}
+
+func (r *searchRepo) SetSections(sections map[int][]models.LyricSection) error {
+	r.stored = sections
+	return nil
+}
+
+func (r *searchRepo) GetUnanalyzed() ([]models.Lyrics, error) {
+	return r.unanalyzed, nil
+}
+
+func (r *searchRepo) SetStats(musicId int, stats models.LyricStats) error {
+	if r.stats == nil {
+		r.stats = make(map[int]models.LyricStats)
+	}
+	r.stats[musicId] = stats
+	return nil
+}
+
+func (r *searchRepo) GetSongTitles() ([]models.SongTitle, error) {
+	return append([]models.SongTitle(nil), r.titles...), nil
+}
+
+func (r *searchRepo) SetSongKeys(keys map[int]string) error {
+	r.keys = keys
+	return nil
+}
+
+func (r *searchRepo) SearchLines(phrase string, context, countMatches, page int) ([]models.LyricMatch, error) {
+	return []models.LyricMatch{{MusicId: 1, Verse: 2, Line: 2, Text: "la la"}}, nil
+}
+
+func TestSearchLyricsOnlyReads(t *testing.T) {
+	repo := &searchRepo{unsectioned: []models.Lyrics{{MusicId: 1, Text: "one"}}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+
+	res, err := s.SearchLyrics("la", 1, 1, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].VerseIndex != 1 || res[0].Page != 2 {
+		t.Errorf("SearchLyrics() = %+v", res)
+	}
+	if repo.stored != nil {
+		t.Errorf("SearchLyrics() stored sections %+v", repo.stored)
+	}
+}
+
+func TestBackfillStoresMissingSections(t *testing.T) {
+	repo := &searchRepo{unsectioned: []models.Lyrics{
+		{MusicId: 1, Text: "[Verse 1]\none\n\n[Chorus]\nla la"},
+		{MusicId: 2, Text: " \n "},
+	}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+	s.Backfill(context.Background())
+
+	if len(repo.stored) != 1 || len(repo.stored[1]) != 2 {
+		t.Fatalf("stored sections = %+v, want two sections of song 1", repo.stored)
+	}
+	if chorus := repo.stored[1][1]; chorus.Label != "[Chorus]" || !reflect.DeepEqual(chorus.Lines, []string{"la la"}) {
+		t.Errorf("stored chorus = %+v", chorus)
+	}
+}
+
+func TestBackfillStoresMissingStats(t *testing.T) {
+	repo := &searchRepo{unanalyzed: []models.Lyrics{
+		{MusicId: 1, Text: "[Chorus]\nthe end\nthe end", Lang: "en"},
+		{MusicId: 2},
+	}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+	s.Backfill(context.Background())
+
+	if len(repo.stats) != 2 {
+		t.Fatalf("stored stats of %d songs, want 2", len(repo.stats))
+	}
+	got := repo.stats[1]
+	if got.Songs != 1 || got.Words != 4 || got.Lines != 2 || got.RepeatedLines != 1 || got.UniqueWords != 2 {
+		t.Errorf("stored stats = %+v", got)
+	}
+}
+
+func TestBackfillStoresMissingSongKeys(t *testing.T) {
+	repo := &searchRepo{titles: []models.SongTitle{
+		{Id: 1, Song: "Café (feat. X)", GroupId: 1},
+		{Id: 2, Song: "Cafe", SongKey: "cafe", GroupId: 1},
+	}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+	s.Backfill(context.Background())
+
+	if want := map[int]string{1: "cafe"}; !reflect.DeepEqual(repo.keys, want) {
+		t.Errorf("stored song keys = %v, want %v", repo.keys, want)
+	}
+}
+
+func TestGetDuplicatesOnlyReads(t *testing.T) {
+	repo := &searchRepo{titles: []models.SongTitle{
+		{Id: 1, Song: "Café (feat. X)", GroupId: 1, Group: "group"},
+		{Id: 2, Song: "Cafe", SongKey: "cafe", GroupId: 1, Group: "group"},
+		{Id: 3, Song: "Cafe", SongKey: "cafe", GroupId: 2, Group: "other"},
+	}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+
+	pairs, err := s.GetDuplicates(0.8, 10)
+	if err != nil {
+		t.Fatalf("GetDuplicates() error = %v", err)
+	}
+	if len(pairs) != 1 || pairs[0].First.Id != 1 || pairs[0].Second.Id != 2 || pairs[0].Score != 1 {
+		t.Errorf("GetDuplicates() = %+v, want songs 1 and 2 alike", pairs)
+	}
+	if repo.keys != nil {
+		t.Errorf("GetDuplicates() stored song keys %v", repo.keys)
+	}
+}
+
+// removeRepo deletes and merges songs, leaving the given files unused.
+type removeRepo struct {
+	Repo
+	keys []string
+	err  error
+}
+
+func (r removeRepo) Delete(musicId int) ([]string, error) {
+	return r.keys, r.err
+}
+
+func (r removeRepo) GetById(musicId int) (models.Music, error) {
+	return models.Music{Id: musicId}, r.err
+}
+
+func (r removeRepo) Merge(keepId, removeId int, taken models.Music) ([]string, error) {
+	return r.keys, r.err
+}
+
+func TestRemovedSongFilesDeleted(t *testing.T) {
+	remove := map[string]func(s *Music) error{
+		"delete": func(s *Music) error { return s.Delete(2) },
+		"merge":  func(s *Music) error { return s.Merge(services.MusicToMerge{KeepId: 1, RemoveId: 2}) },
+	}
+
+	for name, remove := range remove {
+		t.Run(name, func(t *testing.T) {
+			store, err := blob.NewLocal(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"audio/removed.mp3", "artwork/removed/original.png", "artwork/removed/256.jpg", "audio/kept.mp3"} {
+				if err = store.Put(key, strings.NewReader("audio")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			repo := removeRepo{keys: []string{"audio/removed.mp3", "artwork/removed"}}
+			s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, store, nil, "")
+			if err = remove(s); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"audio/removed.mp3", "artwork/removed/original.png", "artwork/removed/256.jpg"} {
+				if _, _, err = store.Open(key); !errors.Is(err, blob.ErrNotFound) {
+					t.Errorf("file of the removed song: Open(%s) error = %v, want %v", key, err, blob.ErrNotFound)
+				}
+			}
+			if _, _, err = store.Open("audio/kept.mp3"); err != nil {
+				t.Errorf("other file: Open() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "song missing", err: musicrepo.ErrMusicNotFound, want: ErrMusicNotFound},
+		{name: "different groups", err: musicrepo.ErrDifferentGroups, want: ErrDifferentGroups},
+		{name: "title taken", err: musicrepo.ErrMusicAlreadyExists, want: ErrMusicAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mergeRepo{err: tt.err}
+			s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+			err := s.Merge(services.MusicToMerge{KeepId: 1, RemoveId: 2, Take: []string{"song"}})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Merge() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// mergeRepo finds every song and fails to merge them with err.
+type mergeRepo struct {
+	Repo
+	err error
+}
+
+func (r mergeRepo) GetById(musicId int) (models.Music, error) {
+	return models.Music{Id: musicId, Song: "song"}, nil
+}
+
+func (r mergeRepo) Merge(keepId, removeId int, taken models.Music) ([]string, error) {
+	return nil, r.err
+}
+
+// langRepo serves one song and records its updates.
+type langRepo struct {
+	Repo
+	song    models.Music
+	updated models.Music
+	lang    string
+	stats   models.LyricStats
+}
+
+func (r *langRepo) GetById(musicId int) (models.Music, error) {
+	return r.song, nil
+}
+
+func (r *langRepo) Update(music models.Music, id int) error {
+	r.updated = music
+	return nil
+}
+
+func (r *langRepo) SetLanguage(id int, lang string, stats models.LyricStats) error {
+	r.lang, r.stats = lang, stats
+	return nil
+}
+
+// stopwords lists the words of stats counted as stopwords.
+func stopwords(stats *models.LyricStats) []string {
+	words := []string{}
+	for _, c := range stats.Counts {
+		if c.Stopword {
+			words = append(words, c.Word)
+		}
+	}
+	return words
+}
+
+const englishText = "I have been waiting for you all night long, and the morning will not come"
+
+func TestUpdateKeepsLanguageSetByHand(t *testing.T) {
+	tests := []struct {
+		name      string
+		song      models.Music
+		lang      string
+		stopwords bool
+	}{
+		{name: "detected", song: models.Music{Id: 1, Lang: "de"}, lang: "en", stopwords: true},
+		{name: "set by hand", song: models.Music{Id: 1, Lang: "de", LangManual: true}, lang: "de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &langRepo{song: tt.song}
+			s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, explicit.New(nil), "")
+			if err := s.Update(services.MusicToUpdate{Text: englishText}, 1); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			if repo.updated.Lang != tt.lang {
+				t.Errorf("updated language = %q, want %q", repo.updated.Lang, tt.lang)
+			}
+			// English stopwords count only when the text is taken as English.
+			if got := len(stopwords(repo.updated.Stats)) > 0; got != tt.stopwords {
+				t.Errorf("updated stopwords = %v, want some: %v", stopwords(repo.updated.Stats), tt.stopwords)
+			}
+		})
+	}
+}
+
+func TestSetLanguageRecomputesStats(t *testing.T) {
+	repo := &langRepo{song: models.Music{Id: 1, Text: "die Welt und the world"}}
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, nil, nil, "")
+
+	for lang, want := range map[string][]string{"de": {"die", "und"}, "en-GB": {"the"}, "": {}} {
+		if err := s.SetLanguage(1, lang); err != nil {
+			t.Fatalf("SetLanguage(%q) error = %v", lang, err)
+		}
+		if repo.lang != lang {
+			t.Errorf("SetLanguage(%q) stored language %q", lang, repo.lang)
+		}
+		if got := stopwords(&repo.stats); !reflect.DeepEqual(got, want) {
+			t.Errorf("SetLanguage(%q) stored stopwords %v, want %v", lang, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/services"
+	"library-music/internal/storage/music"
+	"log/slog"
+	"strconv"
+)
+
+var ErrGroupNotFound = errors.New("group not found")
+
+// GetStats returns the statistics of the lyrics of a song with its top
+// most frequent words that are not stopwords.
+func (s *Music) GetStats(id, top int) (services.LyricStatsToGet, error) {
+	const op = "music.GetStats"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start fetching lyrics statistics")
+
+	stats, err := s.repo.GetStats(id, top)
+	if err != nil {
+		if errors.Is(err, musicrepo.ErrStatsNotFound) {
+			log.Warn("music not found", slog.String("err", err.Error()))
+			return services.LyricStatsToGet{}, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		log.Error("failed to fetch lyrics statistics", slog.String("err", err.Error()))
+		return services.LyricStatsToGet{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("successfully fetched lyrics statistics")
+	return s.mapper.StatsForGet(stats), nil
+}
+
+// GetGroupStats returns the statistics of the lyrics of all the songs of
+// a group taken together.
+func (s *Music) GetGroupStats(group string, top int) (services.LyricStatsToGet, error) {
+	const op = "music.GetGroupStats"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("group", group),
+	)
+
+	log.Info("start fetching group lyrics statistics")
+
+	stats, err := s.repo.GetGroupStats(group, top)
+	if err != nil {
+		if errors.Is(err, musicrepo.ErrGroupNotFound) {
+			log.Warn("group not found", slog.String("err", err.Error()))
+			return services.LyricStatsToGet{}, fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+		log.Error("failed to fetch group lyrics statistics", slog.String("err", err.Error()))
+		return services.LyricStatsToGet{}, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("successfully fetched group lyrics statistics")
+	return s.mapper.StatsForGet(stats), nil
+}
@@ -0,0 +1,234 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/text/language"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/music"
+	"library-music/pkg/lyrics"
+	"library-music/pkg/lyricstats"
+	"log/slog"
+	"strconv"
+)
+
+var (
+	ErrInvalidLanguage     = errors.New("invalid language tag")
+	ErrTranslationNotFound = errors.New("translation not found")
+)
+
+// SetLanguage tags the original text of a song by hand, so that editing
+// the text keeps the tag, and recomputes the statistics, whose stopwords
+// depend on it. An empty lang clears the tag.
+func (s *Music) SetLanguage(id int, lang string) error {
+	const op = "music.SetLanguage"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+		slog.String("lang", lang),
+	)
+
+	if lang != "" {
+		tag, err := canonicalLang(lang)
+		if err != nil {
+			log.Warn("invalid language", slog.String("err", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		lang = tag
+	}
+
+	log.Info("start setting song language")
+	song, err := s.repo.GetById(id)
+	if err != nil {
+		return s.translationErr(log, op, err)
+	}
+
+	stats := s.mapper.StatsToLyricStats(lyricstats.Analyze(song.Text, lang))
+	if err = s.repo.SetLanguage(id, lang, stats); err != nil {
+		return s.translationErr(log, op, err)
+	}
+	log.Info("successfully set song language")
+	return nil
+}
+
+func (s *Music) SetTranslation(id int, translation services.TranslationToSet) error {
+	const op = "music.SetTranslation"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+		slog.String("lang", translation.Lang),
+	)
+
+	lang, err := canonicalLang(translation.Lang)
+	if err != nil {
+		log.Warn("invalid language", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("start setting a translation")
+	err = s.repo.SetTranslation(id, models.Translation{Lang: lang, Text: translation.Text})
+	if err != nil {
+		return s.translationErr(log, op, err)
+	}
+	log.Info("successfully set a translation")
+	return nil
+}
+
+func (s *Music) DeleteTranslation(id int, lang string) error {
+	const op = "music.DeleteTranslation"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+		slog.String("lang", lang),
+	)
+
+	tag, err := canonicalLang(lang)
+	if err != nil {
+		log.Warn("invalid language", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("start deleting a translation")
+	if err = s.repo.DeleteTranslation(id, tag); err != nil {
+		return s.translationErr(log, op, err)
+	}
+	log.Info("successfully deleted a translation")
+	return nil
+}
+
+func (s *Music) GetTranslations(id int) ([]models.Translation, error) {
+	const op = "music.GetTranslations"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start fetching translations")
+	translations, err := s.repo.GetTranslations(id)
+	if err != nil {
+		return nil, s.translationErr(log, op, err)
+	}
+
+	if len(translations) == 0 {
+		log.Warn("song has no translations")
+		return nil, fmt.Errorf("%s: %w", op, ErrTranslationNotFound)
+	}
+	log.Info("successfully fetched translations")
+	return translations, nil
+}
+
+// GetTextSideBySide returns a page of countVerse verses of a song, each
+// paired with the verse at the same position in the translation closest
+// to lang.
+func (s *Music) GetTextSideBySide(song, group, lang string, countVerse, page int) (services.VersePairsPage, error) {
+	const op = "music.GetTextSideBySide"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("lang", lang),
+	)
+
+	log.Info("fetching a song")
+	found, err := s.lyrics(log, op, song, group)
+	if err != nil {
+		return services.VersePairsPage{}, err
+	}
+
+	original := s.verses(found.Sections)
+	translated, err := s.translatedVerses(log, op, found, lang)
+	if err != nil {
+		return services.VersePairsPage{}, err
+	}
+
+	total := max(len(original), len(translated))
+	totalPages := (total + countVerse - 1) / countVerse
+	if page > totalPages {
+		log.Warn("page is out of range")
+		return services.VersePairsPage{}, fmt.Errorf("%s: %w", op, ErrPageOutOfRange)
+	}
+
+	res := services.VersePairsPage{
+		Page:       page,
+		TotalPages: totalPages,
+	}
+	for i := (page - 1) * countVerse; i < min(page*countVerse, total); i++ {
+		var pair services.VersePair
+		if i < len(original) {
+			pair.Original = original[i]
+		}
+		if i < len(translated) {
+			pair.Translation = translated[i]
+		}
+		res.Verses = append(res.Verses, pair)
+		res.VerseIndices = append(res.VerseIndices, i)
+	}
+	log.Info("successfully fetched a song")
+	return res, nil
+}
+
+// translatedVerses picks the text closest to lang among the original and
+// the translations of a song, and splits it into verses.
+func (s *Music) translatedVerses(log *slog.Logger, op string, found models.Lyrics, lang string) ([]string, error) {
+	want, err := language.Parse(lang)
+	if err != nil {
+		log.Warn("invalid language", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidLanguage)
+	}
+
+	translations, err := s.repo.GetTranslations(found.MusicId)
+	if err != nil {
+		return nil, s.translationErr(log, op, err)
+	}
+
+	// The original comes first so it wins over a translation in the same language.
+	var texts []string
+	var tags []language.Tag
+	if found.Lang != "" {
+		if tag, err := language.Parse(found.Lang); err == nil {
+			tags = append(tags, tag)
+			texts = append(texts, "")
+		}
+	}
+	for _, t := range translations {
+		if tag, err := language.Parse(t.Lang); err == nil {
+			tags = append(tags, tag)
+			texts = append(texts, t.Text)
+		}
+	}
+
+	if len(tags) > 0 {
+		_, i, confidence := language.NewMatcher(tags).Match(want)
+		if confidence >= language.High {
+			if found.Lang != "" && i == 0 {
+				return s.verses(found.Sections), nil
+			}
+			return s.verses(s.mapper.SectionsToLyricSections(lyrics.Parse(texts[i]))), nil
+		}
+	}
+
+	log.Warn("no text in the requested language")
+	return nil, fmt.Errorf("%s: %w", op, ErrTranslationNotFound)
+}
+
+func (s *Music) translationErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, musicrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, musicrepo.ErrTranslationNotFound):
+		log.Warn("translation not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrTranslationNotFound)
+	default:
+		log.Error("translation operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// canonicalLang validates a BCP 47 tag and returns its canonical form.
+func canonicalLang(lang string) (string, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "", ErrInvalidLanguage
+	}
+	return tag.String(), nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type PersonToAdd struct {
+	Name string `json:"name" validate:"required,max=200" example:"Freddie Mercury"`
+}
+
+type CreditToAdd struct {
+	PersonId int    `json:"personId" validate:"required,min=1" example:"1"`
+	Role     string `json:"role" validate:"required,oneof=composer lyricist producer performer" example:"composer"`
+}
+
+type MembershipToAdd struct {
+	PersonId int    `json:"personId" validate:"required,min=1" example:"1"`
+	From     string `json:"from,omitempty" validate:"omitempty,datetime=02.01.2006" example:"DD.MM.YYYY"`
+	To       string `json:"to,omitempty" validate:"omitempty,datetime=02.01.2006" example:"DD.MM.YYYY"`
+}
+
+type MembershipToGet struct {
+	Id       int    `json:"id"`
+	GroupId  int    `json:"groupId"`
+	Group    string `json:"group"`
+	PersonId int    `json:"personId"`
+	Person   string `json:"person"`
+	From     string `json:"from,omitempty" example:"DD.MM.YYYY"`
+	To       string `json:"to,omitempty" example:"DD.MM.YYYY"`
+}
+
+type PersonToGet struct {
+	Id          int               `json:"id"`
+	Name        string            `json:"name"`
+	Credits     []models.Credit   `json:"credits"`
+	Memberships []MembershipToGet `json:"memberships"`
+}
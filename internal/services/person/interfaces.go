@@ -0,0 +1,17 @@
+package person
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	Add(name string) (int, error)
+	Delete(id int) error
+	Get(id int) (models.Person, error)
+	AddCredit(musicId, personId int, role string) error
+	RemoveCredit(musicId, personId int, role string) error
+	GetCredits(musicId, personId int) ([]models.Credit, error)
+	AddMember(membership models.Membership) (int, error)
+	RemoveMember(id int) error
+	GetMembers(groupId, personId, year int) ([]models.Membership, error)
+}
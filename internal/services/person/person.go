@@ -0,0 +1,240 @@
+package person
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/person"
+	"library-music/pkg/mapper"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrPersonNotFound        = errors.New("person not found")
+	ErrPersonAlreadyExists   = errors.New("person already exists")
+	ErrMusicNotFound         = errors.New("music not found")
+	ErrGroupNotFound         = errors.New("group not found")
+	ErrCreditNotFound        = errors.New("credit not found")
+	ErrCreditAlreadyExists   = errors.New("credit already exists")
+	ErrMembershipNotFound    = errors.New("membership not found")
+	ErrInvalidMembershipDate = errors.New("membership ends before it starts")
+)
+
+type Person struct {
+	log    *slog.Logger
+	repo   Repo
+	mapper mapper.PersonMapper
+}
+
+func New(log *slog.Logger, repo Repo) *Person {
+	return &Person{
+		log:    log,
+		repo:   repo,
+		mapper: mapper.PersonMapper{},
+	}
+}
+
+func (s *Person) Add(person services.PersonToAdd) (int, error) {
+	const op = "person.Add"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("name", person.Name),
+	)
+
+	log.Info("start adding a person")
+	id, err := s.repo.Add(strings.TrimSpace(person.Name))
+	if err != nil {
+		return 0, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully added a person", slog.String("id", strconv.Itoa(id)))
+	return id, nil
+}
+
+func (s *Person) Delete(id int) error {
+	const op = "person.Delete"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start deleting a person")
+	if err := s.repo.Delete(id); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully deleted a person")
+	return nil
+}
+
+// Get returns a person with their credits and group memberships.
+func (s *Person) Get(id int) (services.PersonToGet, error) {
+	const op = "person.Get"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start fetching a person")
+	person, err := s.repo.Get(id)
+	if err != nil {
+		return services.PersonToGet{}, s.wrapErr(log, op, err)
+	}
+
+	credits, err := s.repo.GetCredits(0, id)
+	if err != nil {
+		return services.PersonToGet{}, s.wrapErr(log, op, err)
+	}
+
+	memberships, err := s.repo.GetMembers(0, id, 0)
+	if err != nil {
+		return services.PersonToGet{}, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully fetched a person")
+	return services.PersonToGet{
+		Id:          person.Id,
+		Name:        person.Name,
+		Credits:     credits,
+		Memberships: s.mapper.MembershipsForGet(memberships),
+	}, nil
+}
+
+func (s *Person) AddCredit(musicId int, credit services.CreditToAdd) error {
+	const op = "person.AddCredit"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+		slog.String("personId", strconv.Itoa(credit.PersonId)),
+		slog.String("role", credit.Role),
+	)
+
+	log.Info("start crediting a person")
+	if err := s.repo.AddCredit(musicId, credit.PersonId, credit.Role); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully credited a person")
+	return nil
+}
+
+func (s *Person) RemoveCredit(musicId, personId int, role string) error {
+	const op = "person.RemoveCredit"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+		slog.String("personId", strconv.Itoa(personId)),
+		slog.String("role", role),
+	)
+
+	log.Info("start removing a credit")
+	if err := s.repo.RemoveCredit(musicId, personId, role); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully removed a credit")
+	return nil
+}
+
+func (s *Person) GetCredits(musicId int) ([]models.Credit, error) {
+	const op = "person.GetCredits"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start fetching song credits")
+	credits, err := s.repo.GetCredits(musicId, 0)
+	if err != nil {
+		return nil, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully fetched song credits")
+	return credits, nil
+}
+
+func (s *Person) AddMember(groupId int, membership services.MembershipToAdd) (int, error) {
+	const op = "person.AddMember"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("groupId", strconv.Itoa(groupId)),
+		slog.String("personId", strconv.Itoa(membership.PersonId)),
+	)
+
+	data, err := s.mapper.AddToMembership(groupId, membership)
+	if err != nil {
+		log.Warn("invalid membership dates", slog.String("err", err.Error()))
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidMembershipDate)
+	}
+
+	log.Info("start adding a group member")
+	id, err := s.repo.AddMember(data)
+	if err != nil {
+		return 0, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully added a group member", slog.String("id", strconv.Itoa(id)))
+	return id, nil
+}
+
+func (s *Person) RemoveMember(id int) error {
+	const op = "person.RemoveMember"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start removing a group member")
+	if err := s.repo.RemoveMember(id); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully removed a group member")
+	return nil
+}
+
+// GetMembers returns the members of a group, only those who were in it at
+// some point of year when year is not 0.
+func (s *Person) GetMembers(groupId, year int) ([]services.MembershipToGet, error) {
+	const op = "person.GetMembers"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("groupId", strconv.Itoa(groupId)),
+		slog.String("year", strconv.Itoa(year)),
+	)
+
+	log.Info("start fetching group members")
+	members, err := s.repo.GetMembers(groupId, 0, year)
+	if err != nil {
+		return nil, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully fetched group members")
+	return s.mapper.MembershipsForGet(members), nil
+}
+
+func (s *Person) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, personrepo.ErrPersonNotFound):
+		log.Warn("person not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+	case errors.Is(err, personrepo.ErrPersonAlreadyExists):
+		log.Warn("person already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrPersonAlreadyExists)
+	case errors.Is(err, personrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, personrepo.ErrGroupNotFound):
+		log.Warn("group not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	case errors.Is(err, personrepo.ErrCreditNotFound):
+		log.Warn("credit not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrCreditNotFound)
+	case errors.Is(err, personrepo.ErrCreditAlreadyExists):
+		log.Warn("credit already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrCreditAlreadyExists)
+	case errors.Is(err, personrepo.ErrMembershipNotFound):
+		log.Warn("membership not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMembershipNotFound)
+	case errors.Is(err, personrepo.ErrInvalidMembershipDate):
+		log.Warn("invalid membership dates", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrInvalidMembershipDate)
+	default:
+		log.Error("person operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
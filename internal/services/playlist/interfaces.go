@@ -0,0 +1,20 @@
+package playlist
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	Create(userId int, name string) (int, error)
+	GetOwner(id int) (int, error)
+	Get(id int) (models.Playlist, error)
+	GetByToken(token string) (models.Playlist, error)
+	GetAllByUser(userId int) ([]models.Playlist, error)
+	Rename(id int, name string) error
+	SetToken(id int, token *string) error
+	Delete(id int) error
+	AddTrack(id, musicId, position int) (int, error)
+	RemoveTrack(id, position int) error
+	MoveTrack(id, from, to int) error
+	Duplicate(id, userId int, name string) (int, error)
+}
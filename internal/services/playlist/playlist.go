@@ -0,0 +1,266 @@
+package playlist
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/playlist"
+	"library-music/pkg/mapper"
+	"log/slog"
+	"strconv"
+)
+
+var (
+	ErrPlaylistNotFound = errors.New("playlist not found")
+	ErrMusicNotFound    = errors.New("music not found")
+	ErrInvalidPosition  = errors.New("invalid position")
+)
+
+const tokenBytes = 18
+
+type Playlist struct {
+	log    *slog.Logger
+	repo   Repo
+	mapper mapper.PlaylistMapper
+}
+
+func New(log *slog.Logger, repo Repo) *Playlist {
+	return &Playlist{
+		log:    log,
+		repo:   repo,
+		mapper: mapper.PlaylistMapper{},
+	}
+}
+
+func (s *Playlist) Create(owner models.Principal, name string) (int, error) {
+	const op = "playlist.Create"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userId", strconv.Itoa(owner.UserId)),
+	)
+
+	log.Info("start creating a playlist")
+	id, err := s.repo.Create(owner.UserId, name)
+	if err != nil {
+		log.Error("failed to create a playlist", slog.String("err", err.Error()))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("successfully created a playlist", slog.String("id", strconv.Itoa(id)))
+	return id, nil
+}
+
+func (s *Playlist) Get(principal models.Principal, id int) (services.PlaylistToGet, error) {
+	const op = "playlist.Get"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start fetching a playlist")
+	res, err := s.repo.Get(id)
+	if err != nil {
+		return services.PlaylistToGet{}, s.wrapErr(log, op, err)
+	}
+
+	if !canManage(principal, res.UserId) {
+		log.Warn("playlist belongs to another user")
+		return services.PlaylistToGet{}, fmt.Errorf("%s: %w", op, ErrPlaylistNotFound)
+	}
+
+	log.Info("successfully fetched a playlist")
+	return s.mapper.PlaylistForGet(res, true), nil
+}
+
+func (s *Playlist) GetAll(owner models.Principal) ([]services.PlaylistToGet, error) {
+	const op = "playlist.GetAll"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userId", strconv.Itoa(owner.UserId)),
+	)
+
+	log.Info("start fetching playlists")
+	res, err := s.repo.GetAllByUser(owner.UserId)
+	if err != nil {
+		return nil, s.wrapErr(log, op, err)
+	}
+
+	arr := make([]services.PlaylistToGet, len(res))
+	for i, v := range res {
+		arr[i] = s.mapper.PlaylistForGet(v, true)
+	}
+	log.Info("successfully fetched playlists")
+	return arr, nil
+}
+
+// GetShared returns a playlist by its public token, without disclosing the token itself.
+func (s *Playlist) GetShared(token string) (services.PlaylistToGet, error) {
+	const op = "playlist.GetShared"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start fetching a shared playlist")
+	res, err := s.repo.GetByToken(token)
+	if err != nil {
+		return services.PlaylistToGet{}, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully fetched a shared playlist")
+	return s.mapper.PlaylistForGet(res, false), nil
+}
+
+func (s *Playlist) Rename(principal models.Principal, id int, name string) error {
+	const op = "playlist.Rename"
+	return s.modify(op, principal, id, func() error {
+		return s.repo.Rename(id, name)
+	})
+}
+
+func (s *Playlist) Delete(principal models.Principal, id int) error {
+	const op = "playlist.Delete"
+	return s.modify(op, principal, id, func() error {
+		return s.repo.Delete(id)
+	})
+}
+
+func (s *Playlist) AddTrack(principal models.Principal, id int, track services.PlaylistTrackToAdd) (int, error) {
+	const op = "playlist.AddTrack"
+	var position int
+	err := s.modify(op, principal, id, func() error {
+		var err error
+		position, err = s.repo.AddTrack(id, track.MusicId, track.Position)
+		return err
+	})
+	return position, err
+}
+
+func (s *Playlist) RemoveTrack(principal models.Principal, id, position int) error {
+	const op = "playlist.RemoveTrack"
+	return s.modify(op, principal, id, func() error {
+		return s.repo.RemoveTrack(id, position)
+	})
+}
+
+func (s *Playlist) MoveTrack(principal models.Principal, id int, move services.PlaylistTrackToMove) error {
+	const op = "playlist.MoveTrack"
+	return s.modify(op, principal, id, func() error {
+		return s.repo.MoveTrack(id, move.From, move.To)
+	})
+}
+
+// Duplicate copies a playlist the principal can manage into a new playlist of
+// their own. An empty name derives one from the original.
+func (s *Playlist) Duplicate(principal models.Principal, id int, name string) (int, error) {
+	const op = "playlist.Duplicate"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+	)
+
+	log.Info("start duplicating a playlist")
+	original, err := s.repo.Get(id)
+	if err != nil {
+		return 0, s.wrapErr(log, op, err)
+	}
+
+	if !canManage(principal, original.UserId) {
+		log.Warn("playlist belongs to another user")
+		return 0, fmt.Errorf("%s: %w", op, ErrPlaylistNotFound)
+	}
+
+	if name == "" {
+		name = original.Name + " (copy)"
+	}
+
+	newId, err := s.repo.Duplicate(id, principal.UserId, name)
+	if err != nil {
+		return 0, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully duplicated a playlist", slog.String("newId", strconv.Itoa(newId)))
+	return newId, nil
+}
+
+// Share issues a new public token; any previously shared link stops working.
+func (s *Playlist) Share(principal models.Principal, id int) (string, error) {
+	const op = "playlist.Share"
+	token, err := newToken()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.modify(op, principal, id, func() error {
+		return s.repo.SetToken(id, &token)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *Playlist) Unshare(principal models.Principal, id int) error {
+	const op = "playlist.Unshare"
+	return s.modify(op, principal, id, func() error {
+		return s.repo.SetToken(id, nil)
+	})
+}
+
+// modify runs apply after checking that the principal may change the playlist.
+func (s *Playlist) modify(op string, principal models.Principal, id int, apply func() error) error {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("id", strconv.Itoa(id)),
+		slog.String("principal", principal.Subject),
+	)
+
+	owner, err := s.repo.GetOwner(id)
+	if err != nil {
+		return s.wrapErr(log, op, err)
+	}
+
+	if !canManage(principal, owner) {
+		log.Warn("playlist belongs to another user")
+		return fmt.Errorf("%s: %w", op, ErrPlaylistNotFound)
+	}
+
+	log.Info("start changing a playlist")
+	if err = apply(); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully changed a playlist")
+	return nil
+}
+
+func (s *Playlist) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, playlistrepo.ErrPlaylistNotFound):
+		log.Warn("playlist not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrPlaylistNotFound)
+	case errors.Is(err, playlistrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, playlistrepo.ErrInvalidPosition):
+		log.Warn("invalid position", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrInvalidPosition)
+	default:
+		log.Error("playlist operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// canManage allows owners to manage their playlists and admins to manage any.
+func canManage(principal models.Principal, ownerId int) bool {
+	if principal.Role.Allows(models.RoleAdmin) {
+		return true
+	}
+	return principal.UserId != 0 && principal.UserId == ownerId
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
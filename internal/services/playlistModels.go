@@ -0,0 +1,38 @@
+package services
+
+import "time"
+
+type PlaylistToCreate struct {
+	Name string `json:"name" validate:"required,max=200"`
+}
+
+type PlaylistTrackToAdd struct {
+	MusicId  int `json:"musicId" validate:"required,min=1"`
+	Position int `json:"position,omitempty" validate:"omitempty,min=1" example:"1"`
+}
+
+type PlaylistTrackToMove struct {
+	From int `json:"from" validate:"required,min=1"`
+	To   int `json:"to" validate:"required,min=1"`
+}
+
+type PlaylistToGet struct {
+	Id          int                  `json:"id"`
+	Name        string               `json:"name"`
+	Shared      bool                 `json:"shared"`
+	PublicToken string               `json:"publicToken,omitempty"`
+	TrackCount  int                  `json:"trackCount"`
+	CreatedAt   time.Time            `json:"createdAt"`
+	UpdatedAt   time.Time            `json:"updatedAt"`
+	Tracks      []PlaylistTrackToGet `json:"tracks,omitempty"`
+}
+
+// PlaylistTrackToGet is a playlist entry; Available is false when the song
+// has been deleted from the library since it was added.
+type PlaylistTrackToGet struct {
+	Position  int    `json:"position"`
+	MusicId   *int   `json:"musicId"`
+	Song      string `json:"song"`
+	Group     string `json:"group"`
+	Available bool   `json:"available"`
+}
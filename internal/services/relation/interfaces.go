@@ -0,0 +1,11 @@
+package relation
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	Add(relation models.Relation) error
+	Delete(relation models.Relation) error
+	GetGraph(musicId, depth int) (models.RelationGraph, error)
+}
@@ -0,0 +1,114 @@
+package relation
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/relation"
+	"log/slog"
+	"strconv"
+)
+
+var (
+	ErrMusicNotFound         = errors.New("music not found")
+	ErrRelationNotFound      = errors.New("relation not found")
+	ErrRelationAlreadyExists = errors.New("relation already exists")
+	ErrSelfRelation          = errors.New("song cannot be related to itself")
+)
+
+type Relation struct {
+	log  *slog.Logger
+	repo Repo
+}
+
+func New(log *slog.Logger, repo Repo) *Relation {
+	return &Relation{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// Link records that the song musicId relates to another one, e.g. is a
+// cover of it.
+func (s *Relation) Link(musicId int, relation services.RelationToAdd) error {
+	const op = "relation.Link"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+		slog.String("relatedId", strconv.Itoa(relation.RelatedId)),
+		slog.String("type", relation.Type),
+	)
+
+	log.Info("start linking songs")
+	err := s.repo.Add(models.Relation{
+		MusicId:   musicId,
+		RelatedId: relation.RelatedId,
+		Type:      relation.Type,
+	})
+	if err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully linked songs")
+	return nil
+}
+
+func (s *Relation) Unlink(relation models.Relation) error {
+	const op = "relation.Unlink"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(relation.MusicId)),
+		slog.String("relatedId", strconv.Itoa(relation.RelatedId)),
+		slog.String("type", relation.Type),
+	)
+
+	log.Info("start unlinking songs")
+	if err := s.repo.Delete(relation); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully unlinked songs")
+	return nil
+}
+
+// GetGraph returns the songs related to musicId directly or through up to
+// depth relations, whichever way the relations point.
+func (s *Relation) GetGraph(musicId, depth int) (models.RelationGraph, error) {
+	const op = "relation.GetGraph"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("musicId", strconv.Itoa(musicId)),
+		slog.String("depth", strconv.Itoa(depth)),
+	)
+
+	log.Info("start fetching relation graph")
+	graph, err := s.repo.GetGraph(musicId, depth)
+	if err != nil {
+		return models.RelationGraph{}, s.wrapErr(log, op, err)
+	}
+	log.Info(
+		"successfully fetched relation graph",
+		slog.String("nodes", strconv.Itoa(len(graph.Nodes))),
+		slog.String("edges", strconv.Itoa(len(graph.Edges))),
+	)
+	return graph, nil
+}
+
+func (s *Relation) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, relationrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, relationrepo.ErrRelationNotFound):
+		log.Warn("relation not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrRelationNotFound)
+	case errors.Is(err, relationrepo.ErrRelationAlreadyExists):
+		log.Warn("relation already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrRelationAlreadyExists)
+	case errors.Is(err, relationrepo.ErrSelfRelation):
+		log.Warn("self relation", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrSelfRelation)
+	default:
+		log.Error("relation operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
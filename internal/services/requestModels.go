@@ -3,6 +3,7 @@ package services
 import (
 	"library-music/internal/domain/models"
 	"reflect"
+	"time"
 )
 
 type SongDetail struct {
@@ -11,9 +12,14 @@ type SongDetail struct {
 	Link        string `json:"link"`
 }
 
+// MusicToAdd optionally places the new song on an album, in which case the
+// album's release date is used when the song details carry none.
 type MusicToAdd struct {
-	Song  string `json:"song"`
-	Group string `json:"group"`
+	Song        string `json:"song"`
+	Group       string `json:"group"`
+	AlbumId     int    `json:"albumId,omitempty" validate:"min=0"`
+	DiscNumber  int    `json:"discNumber,omitempty" validate:"min=0"`
+	TrackNumber int    `json:"trackNumber,omitempty" validate:"required_with=AlbumId,min=0"`
 }
 
 type MusicToUpdate struct {
@@ -47,19 +53,73 @@ func (m *MusicToPartialUpdate) ParsePartial() MusicToUpdate {
 }
 
 type MusicToGet struct {
-	Id          int          `json:"id"`
-	Song        string       `json:"song"`
-	Group       models.Group `json:"group"`
-	Link        string       `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
-	ReleaseDate string       `json:"releaseDate" example:"16.07.2006"`
+	Id          int                `json:"id"`
+	Song        string             `json:"song"`
+	Group       models.Group       `json:"group"`
+	Link        string             `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	ReleaseDate string             `json:"releaseDate" example:"16.07.2006"`
+	Lang        string             `json:"lang,omitempty" example:"en"`
+	Explicit    bool               `json:"explicit"`
+	Links       []models.MusicLink `json:"links,omitempty"`
+	Cover       *ImageToGet        `json:"cover,omitempty"`
+	GroupImage  *ImageToGet        `json:"groupImage,omitempty"`
+	Audio       *AudioToGet        `json:"audio,omitempty"`
+}
+
+// AudioToGet links to the stream of the audio file of a song.
+type AudioToGet struct {
+	URL         string `json:"url" example:"/api/audio/stream?id=1"`
+	ContentType string `json:"contentType" example:"audio/mpeg"`
+	Size        int64  `json:"size" example:"5242880"`
+}
+
+// ImageToGet links to an image and its thumbnails, keyed by the size of
+// the square they fit in.
+type ImageToGet struct {
+	URL        string            `json:"url" example:"/api/public/media/artwork/5f1c0e/original.png"`
+	Width      int               `json:"width" example:"1200"`
+	Height     int               `json:"height" example:"1200"`
+	Thumbnails map[string]string `json:"thumbnails"`
 }
 
 type MusicFilterParams struct {
-	Song        string `json:"song,omitempty" validate:"omitempty"`
-	Group       string `json:"group,omitempty" validate:"omitempty"`
-	Text        string `json:"text,omitempty" validate:"omitempty"`
-	Link        string `json:"link,omitempty" validate:"omitempty,url" example:"https://example.com"`
-	ReleaseDate string `json:"releaseDate" validate:"omitempty,datetime=02.01.2006" example:"DD.MM.YYYY"`
+	Song        string   `json:"song,omitempty" validate:"omitempty"`
+	Group       string   `json:"group,omitempty" validate:"omitempty"`
+	Text        string   `json:"text,omitempty" validate:"omitempty"`
+	Link        string   `json:"link,omitempty" validate:"omitempty,url" example:"https://example.com"`
+	ReleaseDate string   `json:"releaseDate" validate:"omitempty,datetime=02.01.2006" example:"DD.MM.YYYY"`
+	AlbumId     int      `json:"albumId,omitempty" validate:"omitempty,min=1"`
+	GenreId     int      `json:"genreId,omitempty" validate:"omitempty,min=1"`
+	Tags        []string `json:"tags,omitempty" validate:"omitempty,dive,required,max=50"`
+	Lang        string   `json:"lang,omitempty" validate:"omitempty,bcp47_language_tag"`
+	Explicit    *bool    `json:"explicit,omitempty"`
+	PersonId    int      `json:"personId,omitempty" validate:"omitempty,min=1"`
+	Role        string   `json:"role,omitempty" validate:"omitempty,oneof=composer lyricist producer performer"`
+	MemberId    int      `json:"memberId,omitempty" validate:"omitempty,min=1"`
+	MemberYear  int      `json:"memberYear,omitempty" validate:"omitempty,min=1,max=9999,excluded_without=MemberId"`
+	Provider    string   `json:"provider,omitempty" validate:"omitempty,oneof=youtube spotify apple_music bandcamp lyrics other"`
+}
+
+// LinkToAdd is a link to a song; Provider is detected from URL when empty.
+type LinkToAdd struct {
+	Provider string `json:"provider,omitempty" validate:"omitempty,oneof=youtube spotify apple_music bandcamp lyrics other" example:"youtube"`
+	URL      string `json:"url" validate:"required,url,max=2000" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+}
+
+// LinkCheckSummary counts the links checked and those found broken.
+type LinkCheckSummary struct {
+	Checked int `json:"checked"`
+	Broken  int `json:"broken"`
+}
+
+type UserCredentials struct {
+	Login    string `json:"login" validate:"required,min=3,max=64,printascii,excludes= "`
+	Password string `json:"password" validate:"required,min=8,maxbytes=72" maxLength:"72"`
+}
+
+type Session struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 //func NewMusicFilterParams(song, group, text, link string, releaseDate time.Time) MusicFilterParams {
@@ -71,3 +131,25 @@ type MusicFilterParams struct {
 //		ReleaseDate: releaseDate,
 //	}
 //}
+
+// DuplicatePair is two songs of a group whose titles are probably the same
+// title, scored from 0 to 1.
+type DuplicatePair struct {
+	Group  string        `json:"group"`
+	Score  float64       `json:"score" example:"0.92"`
+	First  DuplicateSong `json:"first"`
+	Second DuplicateSong `json:"second"`
+}
+
+type DuplicateSong struct {
+	Id   int    `json:"id"`
+	Song string `json:"song"`
+}
+
+// MusicToMerge names the song to keep, the duplicate to fold into it and
+// the fields to take from the duplicate instead of the kept song.
+type MusicToMerge struct {
+	KeepId   int      `json:"keepId" validate:"required,min=1" example:"1"`
+	RemoveId int      `json:"removeId" validate:"required,min=1,nefield=KeepId" example:"2"`
+	Take     []string `json:"take,omitempty" validate:"omitempty,dive,oneof=song text link releaseDate" example:"text,link"`
+}
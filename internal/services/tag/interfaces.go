@@ -0,0 +1,13 @@
+package tag
+
+import (
+	"library-music/internal/domain/models"
+)
+
+type Repo interface {
+	AddToMusic(musicId int, names []string) error
+	AddToGroup(groupId int, names []string) error
+	RemoveFromMusic(musicId int, name string) error
+	RemoveFromGroup(groupId int, name string) error
+	GetCounts(limit int) ([]models.TagCount, error)
+}
@@ -0,0 +1,124 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+	"library-music/internal/storage/tag"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrTagNotFound   = errors.New("tag not found")
+	ErrMusicNotFound = errors.New("music not found")
+	ErrGroupNotFound = errors.New("group not found")
+)
+
+type Tag struct {
+	log  *slog.Logger
+	repo Repo
+}
+
+func New(log *slog.Logger, repo Repo) *Tag {
+	return &Tag{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *Tag) AddToMusic(musicId int, names []string) error {
+	const op = "tag.AddToMusic"
+	return s.change(op, musicId, func() error {
+		return s.repo.AddToMusic(musicId, NormalizeAll(names))
+	})
+}
+
+func (s *Tag) AddToGroup(groupId int, names []string) error {
+	const op = "tag.AddToGroup"
+	return s.change(op, groupId, func() error {
+		return s.repo.AddToGroup(groupId, NormalizeAll(names))
+	})
+}
+
+func (s *Tag) RemoveFromMusic(musicId int, name string) error {
+	const op = "tag.RemoveFromMusic"
+	return s.change(op, musicId, func() error {
+		return s.repo.RemoveFromMusic(musicId, Normalize(name))
+	})
+}
+
+func (s *Tag) RemoveFromGroup(groupId int, name string) error {
+	const op = "tag.RemoveFromGroup"
+	return s.change(op, groupId, func() error {
+		return s.repo.RemoveFromGroup(groupId, Normalize(name))
+	})
+}
+
+func (s *Tag) GetCounts(limit int) ([]models.TagCount, error) {
+	const op = "tag.GetCounts"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("start counting tags")
+	counts, err := s.repo.GetCounts(limit)
+	if err != nil {
+		return nil, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully counted tags")
+	return counts, nil
+}
+
+func (s *Tag) change(op string, ownerId int, apply func() error) error {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ownerId", strconv.Itoa(ownerId)),
+	)
+
+	log.Info("start changing tags")
+	if err := apply(); err != nil {
+		return s.wrapErr(log, op, err)
+	}
+	log.Info("successfully changed tags")
+	return nil
+}
+
+func (s *Tag) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, tagrepo.ErrTagNotFound):
+		log.Warn("tag not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrTagNotFound)
+	case errors.Is(err, tagrepo.ErrMusicNotFound):
+		log.Warn("music not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	case errors.Is(err, tagrepo.ErrGroupNotFound):
+		log.Warn("group not found", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+	default:
+		log.Error("tag operation failed", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// Normalize folds a free-form tag to its stored form: trimmed,
+// lower-cased and with inner whitespace collapsed to single spaces.
+func Normalize(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// NormalizeAll normalizes names, dropping empty and repeated tags.
+func NormalizeAll(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	res := make([]string, 0, len(names))
+	for _, n := range names {
+		n = Normalize(n)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		res = append(res, n)
+	}
+	return res
+}
@@ -0,0 +1,35 @@
+package tagimport
+
+import (
+	"io"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+)
+
+type MusicRepo interface {
+	Get(song, group string) (models.Music, error)
+	GetArtwork(musicIds []int) (map[int][]models.Artwork, error)
+	GetAudio(musicIds []int) (map[int]models.Audio, error)
+}
+
+type AlbumRepo interface {
+	FindByTitle(title, group string) (models.Album, error)
+	GetById(id int) (models.Album, error)
+	AddTrack(track models.AlbumTrack) error
+}
+
+// Songs adds and updates songs, so imported lyrics are analyzed as
+// lyrics added by hand are, and deletes them with their files.
+type Songs interface {
+	Add(music models.Music) (int, error)
+	Update(music services.MusicToUpdate, id int) error
+	Delete(id int) error
+}
+
+type Artwork interface {
+	SetForMusic(musicId int, data []byte) (services.ImageToGet, error)
+}
+
+type Audio interface {
+	Upload(musicId int, r io.Reader) (services.AudioToGet, error)
+}
@@ -0,0 +1,392 @@
+package tagimport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/services/audio"
+	"library-music/internal/services/music"
+	"library-music/internal/storage/album"
+	"library-music/internal/storage/music"
+	"library-music/pkg/audiotag"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoTags             = errors.New("file has no readable tags")
+	ErrMissingTags        = errors.New("tags lack fields needed to add the song")
+	ErrMusicAlreadyExists = errors.New("music already exists")
+	ErrUnsupportedFormat  = errors.New("unsupported audio format")
+)
+
+const dateLayout = "02.01.2006"
+
+type TagImport struct {
+	log     *slog.Logger
+	musics  MusicRepo
+	albums  AlbumRepo
+	songs   Songs
+	artwork Artwork
+	audio   Audio
+}
+
+func New(log *slog.Logger, musics MusicRepo, albums AlbumRepo, songs Songs, artwork Artwork, audio Audio) *TagImport {
+	return &TagImport{
+		log:     log,
+		musics:  musics,
+		albums:  albums,
+		songs:   songs,
+		artwork: artwork,
+		audio:   audio,
+	}
+}
+
+// Import reads the tags of an audio file and stores the file as the audio
+// of the song they name. The song, and its group, are created when they
+// do not exist; otherwise only fields the song lacks are filled in, and
+// tags differing from stored fields are reported as conflicts. The audio
+// of an existing song is not replaced either: the file is then reported
+// as a conflict and not stored. A song created for the file is deleted
+// again when the file cannot be stored, so that a retry creates it anew.
+func (s *TagImport) Import(r io.ReadSeeker) (services.ImportResult, error) {
+	const op = "tagimport.Import"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	tags, err := audiotag.Read(r)
+	if err != nil {
+		log.Warn("failed to read tags", slog.String("err", err.Error()))
+		return services.ImportResult{}, fmt.Errorf("%s: %w", op, ErrNoTags)
+	}
+	if tags.Title == "" || tags.Artist == "" {
+		log.Warn("tags lack a title or an artist")
+		return services.ImportResult{}, fmt.Errorf("%s: %w", op, ErrMissingTags)
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		log.Error("failed to rewind the file", slog.String("err", err.Error()))
+		return services.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(
+		slog.String("song", tags.Title),
+		slog.String("group", tags.Artist),
+	)
+	res := services.ImportResult{Tags: tagsForGet(tags)}
+
+	album, err := s.findAlbum(tags)
+	if err != nil {
+		log.Error("failed to find the album", slog.String("err", err.Error()))
+		return services.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("start importing a song")
+	existing, err := s.musics.Get(tags.Title, tags.Artist)
+	switch {
+	case errors.Is(err, musicrepo.ErrMusicNotFound):
+		err = s.create(&res, tags, album)
+	case err == nil:
+		err = s.merge(&res, existing, tags, album)
+	}
+	if err != nil {
+		return services.ImportResult{}, s.wrapErr(log, op, err)
+	}
+
+	if err = s.upload(&res, r); err != nil {
+		if res.Created {
+			return s.rollback(log, res), s.wrapErr(log, op, err)
+		}
+		return services.ImportResult{}, s.wrapErr(log, op, err)
+	}
+	log.Info("successfully imported a song",
+		slog.String("id", strconv.Itoa(res.MusicId)),
+		slog.Bool("created", res.Created),
+		slog.Int("conflicts", len(res.Conflicts)),
+	)
+	return res, nil
+}
+
+// create adds the song the tags describe. A song needs a release date,
+// taken from the album when the tags have none.
+func (s *TagImport) create(res *services.ImportResult, tags audiotag.Tags, album *models.Album) error {
+	song := models.Music{
+		Song:        tags.Title,
+		Group:       models.Group{Name: tags.Artist},
+		Text:        tags.Lyrics,
+		ReleaseDate: tags.Date,
+	}
+
+	if album != nil && tags.TrackNumber > 0 {
+		if taken, current := trackTaken(album, tags.TrackNumber, 0); taken {
+			res.Conflicts = append(res.Conflicts, services.TagConflict{
+				Field:   "trackNumber",
+				Current: current,
+				Tagged:  tags.Title,
+			})
+		} else {
+			song.Album = &models.AlbumTrack{AlbumId: album.Id, DiscNumber: 1, TrackNumber: tags.TrackNumber}
+		}
+	}
+	if song.ReleaseDate.IsZero() && (song.Album == nil || album.ReleaseDate == nil) {
+		return ErrMissingTags
+	}
+
+	id, err := s.songs.Add(song)
+	if err != nil {
+		return err
+	}
+	res.MusicId = id
+	res.Created = true
+	res.Applied = append(res.Applied, "song", "group")
+	if !tags.Date.IsZero() {
+		res.Applied = append(res.Applied, "releaseDate")
+	}
+	if song.Text != "" {
+		res.Applied = append(res.Applied, "text")
+	}
+	if song.Album != nil {
+		res.Applied = append(res.Applied, "album")
+	}
+
+	if tags.Picture != nil {
+		s.setCover(res, tags.Picture)
+	}
+	return nil
+}
+
+// merge fills in the fields an existing song lacks and reports those
+// differing from the tags.
+func (s *TagImport) merge(res *services.ImportResult, existing models.Music, tags audiotag.Tags, album *models.Album) error {
+	res.MusicId = existing.Id
+	conflict := func(field, current, tagged string) {
+		res.Conflicts = append(res.Conflicts, services.TagConflict{Field: field, Current: current, Tagged: tagged})
+	}
+
+	// The song is found across spellings, which the tags may not share.
+	if existing.Song != tags.Title {
+		conflict("song", existing.Song, tags.Title)
+	}
+	if existing.Group.Name != tags.Artist {
+		conflict("group", existing.Group.Name, tags.Artist)
+	}
+
+	if !tags.Date.IsZero() && !sameDate(existing, tags) {
+		tagged := tags.Date.Format(dateLayout)
+		if tags.DateIsYear {
+			tagged = strconv.Itoa(tags.Date.Year())
+		}
+		conflict("releaseDate", existing.ReleaseDate.Format(dateLayout), tagged)
+	}
+
+	if tags.Lyrics != "" {
+		switch {
+		case strings.TrimSpace(existing.Text) == "":
+			if err := s.songs.Update(services.MusicToUpdate{Text: tags.Lyrics}, existing.Id); err != nil {
+				return err
+			}
+			res.Applied = append(res.Applied, "text")
+		case normalizeText(existing.Text) != normalizeText(tags.Lyrics):
+			conflict("text", existing.Text, tags.Lyrics)
+		}
+	}
+
+	if album != nil && tags.TrackNumber > 0 {
+		if err := s.placeOnAlbum(res, existing, album, tags.TrackNumber); err != nil {
+			return err
+		}
+	}
+
+	if tags.Picture != nil {
+		artwork, err := s.musics.GetArtwork([]int{existing.Id})
+		if err != nil {
+			return err
+		}
+		hasCover := false
+		for _, a := range artwork[existing.Id] {
+			hasCover = hasCover || a.MusicId == existing.Id
+		}
+		if hasCover {
+			conflict("cover", "uploaded cover", "embedded "+tags.Picture.MIMEType+" picture")
+		} else {
+			s.setCover(res, tags.Picture)
+		}
+	}
+	return nil
+}
+
+// upload stores the file as the audio of the song, unless the song
+// already has audio.
+func (s *TagImport) upload(res *services.ImportResult, r io.Reader) error {
+	if !res.Created {
+		stored, err := s.musics.GetAudio([]int{res.MusicId})
+		if err != nil {
+			return err
+		}
+		if file, ok := stored[res.MusicId]; ok {
+			res.Conflicts = append(res.Conflicts, services.TagConflict{
+				Field:   "audio",
+				Current: "stored " + file.ContentType + " file",
+				Tagged:  "uploaded file",
+			})
+			return nil
+		}
+	}
+
+	uploaded, err := s.audio.Upload(res.MusicId, r)
+	if err != nil {
+		return err
+	}
+	res.Audio = &uploaded
+	res.Applied = append(res.Applied, "audio")
+	return nil
+}
+
+// rollback deletes the song created for a file that could not be stored,
+// with its cover. A song that cannot be deleted is returned, so that the
+// caller knows it was left behind.
+func (s *TagImport) rollback(log *slog.Logger, res services.ImportResult) services.ImportResult {
+	if err := s.songs.Delete(res.MusicId); err != nil {
+		log.Error("failed to delete the song created for the file",
+			slog.String("id", strconv.Itoa(res.MusicId)),
+			slog.String("err", err.Error()),
+		)
+		return services.ImportResult{MusicId: res.MusicId, Created: true, Tags: res.Tags}
+	}
+	return services.ImportResult{}
+}
+
+// placeOnAlbum adds an existing song to the tagged album, unless it is
+// already there or the track number is taken.
+func (s *TagImport) placeOnAlbum(res *services.ImportResult, existing models.Music, album *models.Album, trackNumber int) error {
+	for _, track := range album.Tracks {
+		if track.MusicId != existing.Id {
+			continue
+		}
+		if track.TrackNumber != trackNumber {
+			res.Conflicts = append(res.Conflicts, services.TagConflict{
+				Field:   "trackNumber",
+				Current: strconv.Itoa(track.TrackNumber),
+				Tagged:  strconv.Itoa(trackNumber),
+			})
+		}
+		return nil
+	}
+
+	if taken, current := trackTaken(album, trackNumber, existing.Id); taken {
+		res.Conflicts = append(res.Conflicts, services.TagConflict{
+			Field:   "trackNumber",
+			Current: current,
+			Tagged:  existing.Song,
+		})
+		return nil
+	}
+
+	err := s.albums.AddTrack(models.AlbumTrack{
+		AlbumId:     album.Id,
+		MusicId:     existing.Id,
+		DiscNumber:  1,
+		TrackNumber: trackNumber,
+	})
+	if err != nil {
+		return err
+	}
+	res.Applied = append(res.Applied, "album")
+	return nil
+}
+
+// setCover makes the embedded picture the cover of the song. A picture
+// that cannot be used is left out rather than failing the import.
+func (s *TagImport) setCover(res *services.ImportResult, picture *audiotag.Picture) {
+	if _, err := s.artwork.SetForMusic(res.MusicId, picture.Data); err != nil {
+		s.log.Warn("failed to set the embedded cover",
+			slog.String("id", strconv.Itoa(res.MusicId)),
+			slog.String("err", err.Error()),
+		)
+		return
+	}
+	res.Applied = append(res.Applied, "cover")
+}
+
+// findAlbum returns the tagged album with its tracks, or nil when the
+// tags name no album or one not in the library.
+func (s *TagImport) findAlbum(tags audiotag.Tags) (*models.Album, error) {
+	if tags.Album == "" {
+		return nil, nil
+	}
+
+	found, err := s.albums.FindByTitle(tags.Album, tags.Artist)
+	if err != nil {
+		if errors.Is(err, albumrepo.ErrAlbumNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	album, err := s.albums.GetById(found.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+func (s *TagImport) wrapErr(log *slog.Logger, op string, err error) error {
+	switch {
+	case errors.Is(err, ErrMissingTags):
+		log.Warn("tags lack a release date", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMissingTags)
+	case errors.Is(err, music.ErrMusicAlreadyExists):
+		log.Warn("music already exists", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrMusicAlreadyExists)
+	case errors.Is(err, audio.ErrUnsupportedFormat):
+		log.Warn("unsupported audio format", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, ErrUnsupportedFormat)
+	default:
+		log.Error("failed to import a song", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// trackTaken reports whether another song than musicId holds the track
+// number on the first disc of the album, and which song it is.
+func trackTaken(album *models.Album, trackNumber, musicId int) (bool, string) {
+	for _, track := range album.Tracks {
+		if track.DiscNumber == 1 && track.TrackNumber == trackNumber && track.MusicId != musicId {
+			return true, track.Song
+		}
+	}
+	return false, ""
+}
+
+// sameDate compares the tagged date to the stored one, by year alone
+// when the tags only have a year.
+func sameDate(existing models.Music, tags audiotag.Tags) bool {
+	if tags.DateIsYear {
+		return existing.ReleaseDate.Year() == tags.Date.Year()
+	}
+	return existing.ReleaseDate.Format(dateLayout) == tags.Date.Format(dateLayout)
+}
+
+// normalizeText ignores line endings and surrounding blank space, which
+// differ between tag editors.
+func normalizeText(text string) string {
+	return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+}
+
+func tagsForGet(tags audiotag.Tags) services.ImportedTags {
+	res := services.ImportedTags{
+		Title:       tags.Title,
+		Artist:      tags.Artist,
+		Album:       tags.Album,
+		TrackNumber: tags.TrackNumber,
+		HasLyrics:   tags.Lyrics != "",
+		HasCover:    tags.Picture != nil,
+	}
+	if !tags.Date.IsZero() {
+		res.ReleaseDate = tags.Date.Format(dateLayout)
+	}
+	return res
+}
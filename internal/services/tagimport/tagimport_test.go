@@ -0,0 +1,212 @@
+package tagimport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/music"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// songRepo holds at most one song and its audio file.
+type songRepo struct {
+	MusicRepo
+	song  *models.Music
+	audio map[int]models.Audio
+}
+
+func (r songRepo) Get(song, group string) (models.Music, error) {
+	if r.song == nil {
+		return models.Music{}, musicrepo.ErrMusicNotFound
+	}
+	return *r.song, nil
+}
+
+func (r songRepo) GetAudio(musicIds []int) (map[int]models.Audio, error) {
+	return r.audio, nil
+}
+
+// songs adds every song as song 9, recording the songs added and deleted.
+type songs struct {
+	Songs
+	added     int
+	deleted   []int
+	deleteErr error
+}
+
+func (s *songs) Add(music models.Music) (int, error) {
+	s.added++
+	return 9, nil
+}
+
+func (s *songs) Delete(id int) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+// uploads records the songs audio is uploaded for, failing with err.
+type uploads struct {
+	Audio
+	ids []int
+	err error
+}
+
+func (u *uploads) Upload(musicId int, r io.Reader) (services.AudioToGet, error) {
+	u.ids = append(u.ids, musicId)
+	if u.err != nil {
+		return services.AudioToGet{}, u.err
+	}
+	return services.AudioToGet{ContentType: "audio/mpeg"}, nil
+}
+
+// taggedFile is an MP3 with an ID3v2.3 tag naming the song, its group
+// and its release year.
+func taggedFile() *bytes.Reader {
+	return bytes.NewReader(taggedBytes())
+}
+
+func taggedBytes() []byte {
+	frame := func(id, text string) []byte {
+		data := binary.BigEndian.AppendUint32([]byte(id), uint32(len(text)+1))
+		data = append(data, 0, 0, 0)
+		return append(data, text...)
+	}
+	body := bytes.Join([][]byte{frame("TIT2", "Kukushka"), frame("TPE1", "Kino"), frame("TYER", "1990")}, nil)
+	tag := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, byte(len(body) >> 7), byte(len(body) & 0x7F)}
+	return bytes.Join([][]byte{tag, body, {0xFF, 0xFB, 0x90, 0x00}}, nil)
+}
+
+func TestImportAudio(t *testing.T) {
+	existing := &models.Music{
+		Id:          4,
+		Song:        "Kukushka",
+		Group:       models.Group{Name: "Kino"},
+		Text:        "text",
+		ReleaseDate: time.Date(1990, time.June, 21, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		repo      songRepo
+		uploaded  []int
+		applied   []string
+		conflicts []services.TagConflict
+	}{
+		{
+			name:     "new song",
+			repo:     songRepo{},
+			uploaded: []int{9},
+			applied:  []string{"song", "group", "releaseDate", "audio"},
+		},
+		{
+			name:     "song without audio",
+			repo:     songRepo{song: existing},
+			uploaded: []int{4},
+			applied:  []string{"audio"},
+		},
+		{
+			name: "song with audio",
+			repo: songRepo{song: existing, audio: map[int]models.Audio{
+				4: {MusicId: 4, Key: "audio/stored.flac", ContentType: "audio/flac"},
+			}},
+			conflicts: []services.TagConflict{{Field: "audio", Current: "stored audio/flac file", Tagged: "uploaded file"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audio := &uploads{}
+			s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.repo, nil, &songs{}, nil, audio)
+
+			got, err := s.Import(taggedFile())
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if !reflect.DeepEqual(audio.ids, tt.uploaded) {
+				t.Errorf("uploaded audio for %v, want %v", audio.ids, tt.uploaded)
+			}
+			if (got.Audio != nil) != (tt.uploaded != nil) {
+				t.Errorf("Import() audio = %v, want it only when uploaded", got.Audio)
+			}
+			if !reflect.DeepEqual(got.Applied, tt.applied) {
+				t.Errorf("Import() applied %v, want %v", got.Applied, tt.applied)
+			}
+			if !reflect.DeepEqual(got.Conflicts, tt.conflicts) {
+				t.Errorf("Import() conflicts = %+v, want %+v", got.Conflicts, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestImportFailures(t *testing.T) {
+	failed := errors.New("disk full")
+
+	tests := []struct {
+		name    string
+		file    []byte
+		songs   *songs
+		repo    songRepo
+		upload  error
+		err     error
+		added   int
+		deleted []int
+		kept    int
+	}{
+		{
+			name:    "upload of a new song fails",
+			file:    taggedBytes(),
+			songs:   &songs{},
+			upload:  failed,
+			err:     failed,
+			added:   1,
+			deleted: []int{9},
+		},
+		{
+			name:   "new song cannot be deleted",
+			file:   taggedBytes(),
+			songs:  &songs{deleteErr: failed},
+			upload: failed,
+			err:    failed,
+			added:  1,
+			kept:   9,
+		},
+		{
+			name:   "upload of an existing song fails",
+			file:   taggedBytes(),
+			songs:  &songs{},
+			repo:   songRepo{song: &models.Music{Id: 4, Song: "Kukushka", Group: models.Group{Name: "Kino"}}},
+			upload: failed,
+			err:    failed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audio := &uploads{err: tt.upload}
+			s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.repo, nil, tt.songs, nil, audio)
+
+			got, err := s.Import(bytes.NewReader(tt.file))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Import() error = %v, want %v", err, tt.err)
+			}
+			if tt.songs.added != tt.added {
+				t.Errorf("added %d songs, want %d", tt.songs.added, tt.added)
+			}
+			if !reflect.DeepEqual(tt.songs.deleted, tt.deleted) {
+				t.Errorf("deleted songs %v, want %v", tt.songs.deleted, tt.deleted)
+			}
+			if got.MusicId != tt.kept {
+				t.Errorf("Import() song id = %d, want %d", got.MusicId, tt.kept)
+			}
+		})
+	}
+}
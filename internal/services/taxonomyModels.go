@@ -0,0 +1,35 @@
+package services
+
+type GenreToAdd struct {
+	Name     string `json:"name" validate:"required,max=100" example:"Punk"`
+	ParentId *int   `json:"parentId,omitempty" validate:"omitempty,min=1" example:"1"`
+}
+
+// GenreToGet is a node of the genre tree.
+type GenreToGet struct {
+	Id       int          `json:"id"`
+	Name     string       `json:"name"`
+	Children []GenreToGet `json:"children,omitempty"`
+}
+
+type MusicGenresToSet struct {
+	GenreIds []int `json:"genreIds" validate:"dive,min=1"`
+}
+
+type TagsToAdd struct {
+	Tags []string `json:"tags" validate:"required,min=1,dive,required,max=50" example:"live,90s"`
+}
+
+type RelationToAdd struct {
+	RelatedId int    `json:"relatedId" validate:"required,min=1" example:"2"`
+	Type      string `json:"type" validate:"required,oneof=cover_of remix_of live_version_of samples translation_of" example:"cover_of"`
+}
+
+type AliasToAdd struct {
+	Name string `json:"name" validate:"required,max=200" example:"ACDC"`
+}
+
+type GroupsToMerge struct {
+	FromId int `json:"fromId" validate:"required,min=1" example:"2"`
+	ToId   int `json:"toId" validate:"required,min=1,nefield=FromId" example:"1"`
+}
@@ -0,0 +1,20 @@
+package user
+
+import (
+	"library-music/internal/domain/models"
+	"time"
+)
+
+type Repo interface {
+	Create(user models.User) (int, error)
+	GetByLogin(login string) (models.User, error)
+	CreateSession(userId int, tokenHash []byte, expiresAt time.Time) error
+	GetSessionUser(tokenHash []byte) (models.User, error)
+	DeleteSession(tokenHash []byte) error
+	AddFavorite(userId, musicId int) error
+	RemoveFavorite(userId, musicId int) error
+	GetFavorites(userId, countSongs, page int) ([]models.Music, error)
+	AddToLibrary(userId, musicId int) error
+	RemoveFromLibrary(userId, musicId int) error
+	GetLibrary(userId, countSongs, page int) ([]models.Music, error)
+}
@@ -0,0 +1,240 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/user"
+	"library-music/pkg/mapper"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrMusicNotFound      = errors.New("music not found")
+)
+
+const tokenBytes = 32
+
+type User struct {
+	log        *slog.Logger
+	repo       Repo
+	mapper     mapper.MusicMapper
+	sessionTTL time.Duration
+}
+
+func New(log *slog.Logger, repo Repo, sessionTTL time.Duration) *User {
+	return &User{
+		log:        log,
+		repo:       repo,
+		mapper:     mapper.MusicMapper{},
+		sessionTTL: sessionTTL,
+	}
+}
+
+func (s *User) Register(credentials services.UserCredentials) (int, error) {
+	const op = "user.Register"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("login", credentials.Login),
+	)
+
+	log.Info("start registering a user")
+	hash, err := bcrypt.GenerateFromPassword([]byte(credentials.Password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to hash password", slog.String("err", err.Error()))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.Create(models.User{
+		Login:        credentials.Login,
+		PasswordHash: string(hash),
+		Role:         models.RoleReader,
+	})
+	if err != nil {
+		if errors.Is(err, userrepo.ErrUserAlreadyExists) {
+			log.Warn("user already exists", slog.String("err", err.Error()))
+			return 0, fmt.Errorf("%s: %w", op, ErrUserAlreadyExists)
+		}
+		log.Error("failed to register a user", slog.String("err", err.Error()))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("successfully registered a user")
+	return id, nil
+}
+
+func (s *User) Login(credentials services.UserCredentials) (services.Session, error) {
+	const op = "user.Login"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("login", credentials.Login),
+	)
+
+	log.Info("start logging in")
+	user, err := s.repo.GetByLogin(credentials.Login)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			log.Warn("unknown login")
+			return services.Session{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+		log.Error("failed to get a user", slog.String("err", err.Error()))
+		return services.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password))
+	if err != nil {
+		log.Warn("wrong password")
+		return services.Session{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		log.Error("failed to generate a token", slog.String("err", err.Error()))
+		return services.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	expiresAt := time.Now().Add(s.sessionTTL)
+	if err = s.repo.CreateSession(user.Id, hashToken(token), expiresAt); err != nil {
+		log.Error("failed to create a session", slog.String("err", err.Error()))
+		return services.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("successfully logged in")
+	return services.Session{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *User) Logout(token string) error {
+	const op = "user.Logout"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	if err := s.repo.DeleteSession(hashToken(token)); err != nil {
+		log.Error("failed to delete a session", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("successfully logged out")
+	return nil
+}
+
+// AuthenticateSession resolves an opaque session token into the principal of its user.
+func (s *User) AuthenticateSession(token string) (models.Principal, error) {
+	const op = "user.AuthenticateSession"
+
+	user, err := s.repo.GetSessionUser(hashToken(token))
+	if err != nil {
+		if errors.Is(err, userrepo.ErrSessionNotFound) {
+			return models.Principal{}, fmt.Errorf("%s: %w", op, ErrSessionNotFound)
+		}
+		return models.Principal{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.Principal{
+		Subject: user.Login,
+		Role:    user.Role,
+		Method:  models.AuthMethodSession,
+		UserId:  user.Id,
+	}, nil
+}
+
+func (s *User) AddFavorite(userId, musicId int) error {
+	const op = "user.AddFavorite"
+	return s.change(op, s.repo.AddFavorite, userId, musicId)
+}
+
+func (s *User) RemoveFavorite(userId, musicId int) error {
+	const op = "user.RemoveFavorite"
+	return s.change(op, s.repo.RemoveFavorite, userId, musicId)
+}
+
+func (s *User) GetFavorites(userId, countSongs, page int) ([]services.MusicToGet, error) {
+	const op = "user.GetFavorites"
+	return s.list(op, s.repo.GetFavorites, userId, countSongs, page)
+}
+
+func (s *User) AddToLibrary(userId, musicId int) error {
+	const op = "user.AddToLibrary"
+	return s.change(op, s.repo.AddToLibrary, userId, musicId)
+}
+
+func (s *User) RemoveFromLibrary(userId, musicId int) error {
+	const op = "user.RemoveFromLibrary"
+	return s.change(op, s.repo.RemoveFromLibrary, userId, musicId)
+}
+
+func (s *User) GetLibrary(userId, countSongs, page int) ([]services.MusicToGet, error) {
+	const op = "user.GetLibrary"
+	return s.list(op, s.repo.GetLibrary, userId, countSongs, page)
+}
+
+func (s *User) change(op string, apply func(userId, musicId int) error, userId, musicId int) error {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userId", strconv.Itoa(userId)),
+		slog.String("musicId", strconv.Itoa(musicId)),
+	)
+
+	log.Info("start changing a user collection")
+	if err := apply(userId, musicId); err != nil {
+		if errors.Is(err, userrepo.ErrMusicNotFound) {
+			log.Warn("music not found", slog.String("err", err.Error()))
+			return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		log.Error("failed to change a user collection", slog.String("err", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("successfully changed a user collection")
+	return nil
+}
+
+func (s *User) list(op string, fetch func(userId, countSongs, page int) ([]models.Music, error), userId, countSongs, page int) ([]services.MusicToGet, error) {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userId", strconv.Itoa(userId)),
+	)
+
+	log.Info("start fetching a user collection")
+	res, err := fetch(userId, countSongs, page)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrMusicNotFound) {
+			log.Warn("user collection is empty", slog.String("err", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		log.Error("failed to fetch a user collection", slog.String("err", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	arr := make([]services.MusicToGet, len(res))
+	for i, v := range res {
+		arr[i] = s.mapper.MusicForGet(v)
+	}
+	log.Info("successfully fetched a user collection")
+	return arr, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken keeps raw session tokens out of the database.
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
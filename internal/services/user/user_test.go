@@ -0,0 +1,185 @@
+package user
+
+import (
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/internal/storage/user"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// userRepo keeps users and sessions in memory, sessions by token hash.
+type userRepo struct {
+	Repo
+	users    []models.User
+	sessions map[string]int
+	expiries map[string]time.Time
+}
+
+func (r *userRepo) Create(user models.User) (int, error) {
+	for _, u := range r.users {
+		if u.Login == user.Login {
+			return 0, userrepo.ErrUserAlreadyExists
+		}
+	}
+	user.Id = len(r.users) + 1
+	r.users = append(r.users, user)
+	return user.Id, nil
+}
+
+func (r *userRepo) GetByLogin(login string) (models.User, error) {
+	for _, u := range r.users {
+		if u.Login == login {
+			return u, nil
+		}
+	}
+	return models.User{}, userrepo.ErrUserNotFound
+}
+
+func (r *userRepo) CreateSession(userId int, tokenHash []byte, expiresAt time.Time) error {
+	if r.sessions == nil {
+		r.sessions, r.expiries = map[string]int{}, map[string]time.Time{}
+	}
+	r.sessions[string(tokenHash)] = userId
+	r.expiries[string(tokenHash)] = expiresAt
+	return nil
+}
+
+func (r *userRepo) GetSessionUser(tokenHash []byte) (models.User, error) {
+	id, ok := r.sessions[string(tokenHash)]
+	if !ok {
+		return models.User{}, userrepo.ErrSessionNotFound
+	}
+	return r.users[id-1], nil
+}
+
+func (r *userRepo) DeleteSession(tokenHash []byte) error {
+	delete(r.sessions, string(tokenHash))
+	return nil
+}
+
+func newService(repo Repo) *User {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, time.Hour)
+}
+
+func TestRegister(t *testing.T) {
+	repo := &userRepo{}
+	s := newService(repo)
+	credentials := services.UserCredentials{Login: "reader", Password: "correct horse"}
+
+	id, err := s.Register(credentials)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if id != 1 || len(repo.users) != 1 {
+		t.Fatalf("Register() = %d with %d users stored, want 1 with 1", id, len(repo.users))
+	}
+
+	stored := repo.users[0]
+	if stored.Role != models.RoleReader {
+		t.Errorf("registered role = %q, want %q", stored.Role, models.RoleReader)
+	}
+	if stored.PasswordHash == credentials.Password {
+		t.Error("password stored as given")
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(stored.PasswordHash), []byte(credentials.Password)); err != nil {
+		t.Errorf("stored hash does not match the password: %v", err)
+	}
+
+	if _, err = s.Register(credentials); !errors.Is(err, ErrUserAlreadyExists) {
+		t.Errorf("second Register() error = %v, want %v", err, ErrUserAlreadyExists)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	repo := &userRepo{}
+	s := newService(repo)
+	if _, err := s.Register(services.UserCredentials{Login: "reader", Password: "correct horse"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		credentials services.UserCredentials
+		err         error
+	}{
+		{name: "valid", credentials: services.UserCredentials{Login: "reader", Password: "correct horse"}},
+		{name: "wrong password", credentials: services.UserCredentials{Login: "reader", Password: "battery staple"}, err: ErrInvalidCredentials},
+		{name: "unknown login", credentials: services.UserCredentials{Login: "writer", Password: "correct horse"}, err: ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(repo.sessions)
+			start := time.Now()
+			session, err := s.Login(tt.credentials)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Login() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				if len(repo.sessions) != before {
+					t.Error("failed Login() created a session")
+				}
+				return
+			}
+
+			if len(session.Token) < tokenBytes {
+				t.Errorf("token %q is too short", session.Token)
+			}
+			if _, ok := repo.sessions[session.Token]; ok {
+				t.Error("session stored by the raw token")
+			}
+			expiresAt, ok := repo.expiries[string(hashToken(session.Token))]
+			if !ok {
+				t.Fatal("session not stored by the token hash")
+			}
+			if !expiresAt.Equal(session.ExpiresAt) || expiresAt.Before(start.Add(time.Hour)) {
+				t.Errorf("session expires at %v, returned %v, want an hour from now", expiresAt, session.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	repo := &userRepo{}
+	s := newService(repo)
+	credentials := services.UserCredentials{Login: "reader", Password: "correct horse"}
+	if _, err := s.Register(credentials); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := s.Login(credentials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Login(credentials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Token == second.Token {
+		t.Fatal("Login() issued the same token twice")
+	}
+
+	principal, err := s.AuthenticateSession(first.Token)
+	if err != nil {
+		t.Fatalf("AuthenticateSession() error = %v", err)
+	}
+	want := models.Principal{Subject: "reader", Role: models.RoleReader, Method: models.AuthMethodSession, UserId: 1}
+	if principal != want {
+		t.Errorf("AuthenticateSession() = %+v, want %+v", principal, want)
+	}
+
+	if err = s.Logout(first.Token); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err = s.AuthenticateSession(first.Token); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("AuthenticateSession() of a revoked token error = %v, want %v", err, ErrSessionNotFound)
+	}
+	if _, err = s.AuthenticateSession(second.Token); err != nil {
+		t.Errorf("Logout() revoked another session: %v", err)
+	}
+}
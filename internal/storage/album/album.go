@@ -0,0 +1,253 @@
+package albumrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrAlbumNotFound = errors.New("album not found")
+	ErrMusicNotFound = errors.New("music not found")
+	ErrTrackTaken    = errors.New("track already on album")
+)
+
+type Album struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Album {
+	return &Album{
+		db: db,
+	}
+}
+
+const selectAlbum = `SELECT a.id, a.title, a.release_date, a.cover_link,
+       COALESCE(g.id, 0) AS "group.id",
+       COALESCE(g.name, '') AS "group.name"
+       FROM albums a
+       LEFT JOIN groups g ON g.id = a.group_id`
+
+func (r *Album) Add(album models.Album) (int, error) {
+	const op = "storage.album.Add"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	groupId, err := ensureGroup(tx, album.Group.Name)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int
+	query := `INSERT INTO albums (title, release_date, cover_link, group_id) VALUES ($1, $2, $3, $4) RETURNING id;`
+	err = tx.QueryRow(query, album.Title, album.ReleaseDate, album.CoverLink, groupId).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+func (r *Album) Update(album models.Album, id int) error {
+	const op = "storage.album.Update"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	groupId, err := ensureGroup(tx, album.Group.Name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `UPDATE albums SET title = $1, release_date = $2, cover_link = $3, group_id = $4 WHERE id = $5`
+	res, err := tx.Exec(query, album.Title, album.ReleaseDate, album.CoverLink, groupId, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		err = ErrAlbumNotFound
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ensureGroup returns the id of the named group, or of the group the name
+// is an alias of, creating the group when needed. An empty name leaves the
+// album without a group.
+func ensureGroup(tx *sqlx.Tx, name string) (*int, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	var existing *int
+	if err := tx.Get(&existing, `SELECT group_id_by_name($1)`, name); err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return existing, nil
+	}
+
+	query := `INSERT INTO groups (name) VALUES ($1) ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name RETURNING id;`
+	var id int
+	if err := tx.QueryRow(query, name).Scan(&id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *Album) Delete(id int) error {
+	const op = "storage.album.Delete"
+	res, err := r.db.Exec(`DELETE FROM albums WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrAlbumNotFound)
+	}
+	return nil
+}
+
+func (r *Album) GetById(id int) (models.Album, error) {
+	const op = "storage.album.GetById"
+
+	var album models.Album
+	err := r.db.Get(&album, selectAlbum+` WHERE a.id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Album{}, fmt.Errorf("%s: %w", op, ErrAlbumNotFound)
+		}
+		return models.Album{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT t.album_id, t.music_id, m.song, t.disc_number, t.track_number
+	FROM album_tracks t
+	JOIN music m ON m.id = t.music_id
+	WHERE t.album_id = $1
+	ORDER BY t.disc_number, t.track_number`
+
+	if err = r.db.Select(&album.Tracks, query, id); err != nil {
+		return models.Album{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return album, nil
+}
+
+// FindByTitle finds an album of a group by its title, ignoring case and
+// matching the group in either Cyrillic or Latin spelling.
+func (r *Album) FindByTitle(title, group string) (models.Album, error) {
+	const op = "storage.album.FindByTitle"
+
+	var album models.Album
+	query := selectAlbum + ` WHERE lower(a.title) = lower($1)
+		AND (g.id = group_id_by_name($2) OR g.name_search = search_key($2))
+		ORDER BY g.id IS DISTINCT FROM group_id_by_name($2), a.id
+		LIMIT 1`
+
+	err := r.db.Get(&album, query, title, group)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Album{}, fmt.Errorf("%s: %w", op, ErrAlbumNotFound)
+		}
+		return models.Album{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return album, nil
+}
+
+func (r *Album) GetAll(group string, countAlbums, page int) ([]models.Album, error) {
+	const op = "storage.album.GetAll"
+
+	query := selectAlbum
+	var args []interface{}
+	if group != "" {
+		args = append(args, group)
+		query += ` WHERE g.id = group_id_by_name($1)`
+	}
+	query += fmt.Sprintf(" ORDER BY a.release_date NULLS LAST, a.id LIMIT %d OFFSET %d", countAlbums, (page-1)*countAlbums)
+
+	var albums []models.Album
+	if err := r.db.Select(&albums, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(albums) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrAlbumNotFound)
+	}
+	return albums, nil
+}
+
+func (r *Album) AddTrack(track models.AlbumTrack) error {
+	const op = "storage.album.AddTrack"
+	query := `INSERT INTO album_tracks (album_id, music_id, disc_number, track_number) VALUES ($1, $2, $3, $4);`
+
+	_, err := r.db.Exec(query, track.AlbumId, track.MusicId, track.DiscNumber, track.TrackNumber)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch {
+			case pqErr.Code == "23505":
+				return fmt.Errorf("%s: %w", op, ErrTrackTaken)
+			case pqErr.Code == "23503" && pqErr.Constraint == "album_tracks_music_id_fkey":
+				return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+			case pqErr.Code == "23503":
+				return fmt.Errorf("%s: %w", op, ErrAlbumNotFound)
+			}
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Album) RemoveTrack(albumId, musicId int) error {
+	const op = "storage.album.RemoveTrack"
+	res, err := r.db.Exec(`DELETE FROM album_tracks WHERE album_id = $1 AND music_id = $2`, albumId, musicId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package artworkrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrMusicNotFound   = errors.New("music not found")
+	ErrGroupNotFound   = errors.New("group not found")
+	ErrArtworkNotFound = errors.New("artwork not found")
+)
+
+type Artwork struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Artwork {
+	return &Artwork{
+		db: db,
+	}
+}
+
+// Set records the artwork of a song or group, replacing the one it had,
+// and returns the key of the replaced artwork, empty when there was none.
+func (r *Artwork) Set(artwork models.Artwork) (string, error) {
+	const op = "storage.artwork.Set"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var old string
+	err = tx.Get(&old, `DELETE FROM artwork WHERE music_id = NULLIF($1, 0) OR group_id = NULLIF($2, 0) RETURNING key`,
+		artwork.MusicId, artwork.GroupId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	sizes := make(pq.Int64Array, len(artwork.Sizes))
+	for i, size := range artwork.Sizes {
+		sizes[i] = int64(size)
+	}
+
+	query := `INSERT INTO artwork (music_id, group_id, key, ext, width, height, sizes)
+	VALUES (NULLIF($1, 0), NULLIF($2, 0), $3, $4, $5, $6, $7)`
+	_, err = tx.Exec(query, artwork.MusicId, artwork.GroupId, artwork.Key, artwork.Ext, artwork.Width, artwork.Height, sizes)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			if artwork.MusicId != 0 {
+				return "", fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+			}
+			return "", fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return old, nil
+}
+
+// Delete removes the artwork of a song or group and returns its key.
+func (r *Artwork) Delete(musicId, groupId int) (string, error) {
+	const op = "storage.artwork.Delete"
+
+	var key string
+	err := r.db.Get(&key, `DELETE FROM artwork WHERE music_id = NULLIF($1, 0) OR group_id = NULLIF($2, 0) RETURNING key`,
+		musicId, groupId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, ErrArtworkNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return key, nil
+}
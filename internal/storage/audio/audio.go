@@ -0,0 +1,91 @@
+package audiorepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrMusicNotFound = errors.New("music not found")
+	ErrAudioNotFound = errors.New("audio not found")
+)
+
+type Audio struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Audio {
+	return &Audio{
+		db: db,
+	}
+}
+
+// Set records the audio file of a song, replacing the one it had, and
+// returns the key of the replaced file, empty when there was none.
+func (r *Audio) Set(audio models.Audio) (string, error) {
+	const op = "storage.audio.Set"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var old string
+	err = tx.Get(&old, `DELETE FROM audio_files WHERE music_id = $1 RETURNING key`, audio.MusicId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO audio_files (music_id, key, content_type, size) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(query, audio.MusicId, audio.Key, audio.ContentType, audio.Size)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return "", fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return old, nil
+}
+
+// Delete removes the audio file of a song and returns its key.
+func (r *Audio) Delete(musicId int) (string, error) {
+	const op = "storage.audio.Delete"
+
+	var key string
+	err := r.db.Get(&key, `DELETE FROM audio_files WHERE music_id = $1 RETURNING key`, musicId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, ErrAudioNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return key, nil
+}
+
+func (r *Audio) Get(musicId int) (models.Audio, error) {
+	const op = "storage.audio.Get"
+
+	var audio models.Audio
+	query := `SELECT music_id, key, content_type, size FROM audio_files WHERE music_id = $1`
+	if err := r.db.Get(&audio, query, musicId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Audio{}, fmt.Errorf("%s: %w", op, ErrAudioNotFound)
+		}
+		return models.Audio{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return audio, nil
+}
@@ -0,0 +1,180 @@
+package genrerepo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrGenreNotFound      = errors.New("genre not found")
+	ErrGenreAlreadyExists = errors.New("genre already exists")
+	ErrGenreInUse         = errors.New("genre has subgenres")
+	ErrGenreCycle         = errors.New("genre cannot be nested under itself")
+	ErrMusicNotFound      = errors.New("music not found")
+)
+
+type Genre struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Genre {
+	return &Genre{
+		db: db,
+	}
+}
+
+func (r *Genre) Add(genre models.Genre) (int, error) {
+	const op = "storage.genre.Add"
+	query := `INSERT INTO genres (name, parent_id) VALUES ($1, $2) RETURNING id;`
+
+	var id int
+	if err := r.db.QueryRow(query, genre.Name, genre.ParentId).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, translateErr(err))
+	}
+	return id, nil
+}
+
+func (r *Genre) Update(genre models.Genre, id int) error {
+	const op = "storage.genre.Update"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if genre.ParentId != nil {
+		var cycle bool
+		cycleQuery := `WITH RECURSIVE subtree AS (
+			SELECT id FROM genres WHERE id = $1
+			UNION
+			SELECT g.id FROM genres g JOIN subtree s ON g.parent_id = s.id
+		)
+		SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+
+		if err = tx.Get(&cycle, cycleQuery, id, *genre.ParentId); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if cycle {
+			err = ErrGenreCycle
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	query := `UPDATE genres SET name = $1, parent_id = $2 WHERE id = $3`
+	res, err := tx.Exec(query, genre.Name, genre.ParentId, id)
+	if err != nil {
+		err = translateErr(err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		err = ErrGenreNotFound
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Genre) Delete(id int) error {
+	const op = "storage.genre.Delete"
+	res, err := r.db.Exec(`DELETE FROM genres WHERE id = $1`, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("%s: %w", op, ErrGenreInUse)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrGenreNotFound)
+	}
+	return nil
+}
+
+func (r *Genre) GetAll() ([]models.Genre, error) {
+	const op = "storage.genre.GetAll"
+
+	var genres []models.Genre
+	if err := r.db.Select(&genres, `SELECT id, name, parent_id FROM genres ORDER BY name, id`); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return genres, nil
+}
+
+// SetMusicGenres replaces the genres of a song.
+func (r *Genre) SetMusicGenres(musicId int, genreIds []int) error {
+	const op = "storage.genre.SetMusicGenres"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var exists bool
+	if err = tx.Get(&exists, `SELECT EXISTS (SELECT 1 FROM music WHERE id = $1)`, musicId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		err = ErrMusicNotFound
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = tx.Exec(`DELETE FROM music_genres WHERE music_id = $1`, musicId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO music_genres (music_id, genre_id)
+	SELECT $1, unnest($2::int[])
+	ON CONFLICT DO NOTHING;`
+	if _, err = tx.Exec(query, musicId, pq.Array(genreIds)); err != nil {
+		err = translateErr(err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func translateErr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return ErrGenreAlreadyExists
+		case "23503":
+			return ErrGenreNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+package grouprepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrAliasNotFound      = errors.New("alias not found")
+	ErrAliasAlreadyExists = errors.New("alias already exists")
+	ErrMergeIntoSelf      = errors.New("group cannot be merged into itself")
+)
+
+type Group struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Group {
+	return &Group{
+		db: db,
+	}
+}
+
+// AddAlias makes name resolve to the group. A name can be the alias of a
+// single group and cannot be the name of a group, both ignoring case.
+func (r *Group) AddAlias(groupId int, name string) error {
+	const op = "storage.group.AddAlias"
+	query := `INSERT INTO group_aliases (name, group_id)
+	SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM groups WHERE lower(name) = lower($1))`
+
+	res, err := r.db.Exec(query, name, groupId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, translateErr(err))
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrAliasAlreadyExists)
+	}
+	return nil
+}
+
+func (r *Group) RemoveAlias(name string) error {
+	const op = "storage.group.RemoveAlias"
+	res, err := r.db.Exec(`DELETE FROM group_aliases WHERE lower(name) = lower($1)`, name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrAliasNotFound)
+	}
+	return nil
+}
+
+func (r *Group) GetAliases(groupId int) ([]string, error) {
+	const op = "storage.group.GetAliases"
+	if err := r.checkGroup(r.db, groupId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	aliases := []string{}
+	if err := r.db.Select(&aliases, `SELECT name FROM group_aliases WHERE group_id = $1 ORDER BY name`, groupId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return aliases, nil
+}
+
+// Merge moves the songs, albums, tags, members and aliases of the group
+// fromId to the group toId, deletes fromId and keeps its name as an alias
+// of toId. Songs of both groups with the same title are kept as they are;
+// they are reported as duplicates rather than merged here. The blob keys
+// of files left to fromId are returned for the caller to delete.
+func (r *Group) Merge(fromId, toId int) ([]string, error) {
+	const op = "storage.group.Merge"
+	if fromId == toId {
+		return nil, fmt.Errorf("%s: %w", op, ErrMergeIntoSelf)
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var name string
+	err = tx.Get(&name, `SELECT name FROM groups WHERE id = $1 FOR UPDATE`, fromId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = r.checkGroup(tx, toId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	queries := []string{
+		`DELETE FROM music_groups WHERE group_id = $1
+		AND music_id IN (SELECT music_id FROM music_groups WHERE group_id = $2)`,
+		`UPDATE music_groups SET group_id = $2 WHERE group_id = $1`,
+		`UPDATE albums SET group_id = $2 WHERE group_id = $1`,
+		`INSERT INTO group_tags (group_id, tag_id)
+		SELECT $2, tag_id FROM group_tags WHERE group_id = $1
+		ON CONFLICT DO NOTHING`,
+		`UPDATE group_members SET group_id = $2 WHERE group_id = $1`,
+		`UPDATE group_aliases SET group_id = $2 WHERE group_id = $1`,
+		`UPDATE artwork SET group_id = $2 WHERE group_id = $1
+		AND NOT EXISTS (SELECT 1 FROM artwork WHERE group_id = $2)`,
+	}
+	for _, query := range queries {
+		if _, err = tx.Exec(query, fromId, toId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	var keys []string
+	if err = tx.Select(&keys, `SELECT key FROM artwork WHERE group_id = $1`, fromId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = tx.Exec(`DELETE FROM groups WHERE id = $1`, fromId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO group_aliases (name, group_id) VALUES ($1, $2)
+	ON CONFLICT ((lower(name))) DO UPDATE SET group_id = EXCLUDED.group_id`
+	if _, err = tx.Exec(query, name, toId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return keys, nil
+}
+
+func (r *Group) checkGroup(q sqlx.Queryer, id int) error {
+	var exists bool
+	if err := sqlx.Get(q, &exists, `SELECT EXISTS (SELECT 1 FROM groups WHERE id = $1)`, id); err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func translateErr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return ErrAliasAlreadyExists
+		case "23503":
+			return ErrGroupNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package lyricsrepo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrMusicNotFound  = errors.New("music not found")
+	ErrLyricsNotFound = errors.New("lyrics not found")
+)
+
+type Lyrics struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Lyrics {
+	return &Lyrics{
+		db: db,
+	}
+}
+
+// SetTimings replaces the timed lines of a song.
+func (r *Lyrics) SetTimings(musicId int, lines []models.TimedLine) error {
+	const op = "storage.lyrics.SetTimings"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec(`DELETE FROM lyric_timings WHERE music_id = $1`, musicId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO lyric_timings (music_id, position, start_ms, text, words) VALUES ($1, $2, $3, $4, $5);`
+	for i, line := range lines {
+		var words []byte
+		if len(line.Words) > 0 {
+			if words, err = json.Marshal(line.Words); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		if _, err = tx.Exec(query, musicId, i+1, line.StartMs, line.Text, words); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				err = ErrMusicNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Lyrics) DeleteTimings(musicId int) error {
+	const op = "storage.lyrics.DeleteTimings"
+	res, err := r.db.Exec(`DELETE FROM lyric_timings WHERE music_id = $1`, musicId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrLyricsNotFound)
+	}
+	return nil
+}
+
+func (r *Lyrics) GetTimings(musicId int) (models.SyncedLyrics, error) {
+	const op = "storage.lyrics.GetTimings"
+
+	synced := models.SyncedLyrics{MusicId: musicId}
+	query := `SELECT m.song, COALESCE(g.name, '')
+	FROM music m
+	LEFT JOIN music_groups mg ON mg.music_id = m.id
+	LEFT JOIN groups g ON g.id = mg.group_id
+	WHERE m.id = $1
+	LIMIT 1`
+
+	err := r.db.QueryRow(query, musicId).Scan(&synced.Song, &synced.Group)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.SyncedLyrics{}, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		return models.SyncedLyrics{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var rows []struct {
+		models.TimedLine
+		Words []byte `db:"words"`
+	}
+	query = `SELECT start_ms, text, words FROM lyric_timings WHERE music_id = $1 ORDER BY position`
+	if err = r.db.Select(&rows, query, musicId); err != nil {
+		return models.SyncedLyrics{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(rows) == 0 {
+		return models.SyncedLyrics{}, fmt.Errorf("%s: %w", op, ErrLyricsNotFound)
+	}
+
+	synced.Lines = make([]models.TimedLine, len(rows))
+	for i, row := range rows {
+		synced.Lines[i] = row.TimedLine
+		if row.Words != nil {
+			if err = json.Unmarshal(row.Words, &synced.Lines[i].Words); err != nil {
+				return models.SyncedLyrics{}, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+	return synced, nil
+}
@@ -0,0 +1,52 @@
+package musicrepo
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrAlbumNotFound      = errors.New("album not found")
+	ErrTrackTaken         = errors.New("track number already taken")
+	ErrMissingReleaseDate = errors.New("song and album have no release date")
+)
+
+// checkAlbum makes sure the album exists and, for a song with no release
+// date of its own, has a release date for the song to take.
+func (r *Music) checkAlbum(tx *sqlx.Tx, albumId int, needsDate bool) error {
+	var hasDate bool
+	err := tx.Get(&hasDate, `SELECT release_date IS NOT NULL FROM albums WHERE id = $1`, albumId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAlbumNotFound
+		}
+		return err
+	}
+
+	if needsDate && !hasDate {
+		return ErrMissingReleaseDate
+	}
+	return nil
+}
+
+func (r *Music) insertAlbumTrack(tx *sqlx.Tx, musicId int, track models.AlbumTrack) error {
+	query := `INSERT INTO album_tracks (album_id, music_id, disc_number, track_number) VALUES ($1, $2, $3, $4);`
+
+	_, err := tx.Exec(query, track.AlbumId, musicId, track.DiscNumber, track.TrackNumber)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23503":
+				return ErrAlbumNotFound
+			case "23505":
+				return ErrTrackTaken
+			}
+		}
+		return err
+	}
+	return nil
+}
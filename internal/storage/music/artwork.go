@@ -0,0 +1,41 @@
+package musicrepo
+
+import (
+	"fmt"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+// GetArtwork returns the covers of the songs and the images of their
+// groups, keyed by song id.
+func (r *Music) GetArtwork(musicIds []int) (map[int][]models.Artwork, error) {
+	const op = "storage.music.GetArtwork"
+
+	var rows []struct {
+		models.Artwork
+		ForId int           `db:"for_id"`
+		Sizes pq.Int64Array `db:"sizes"`
+	}
+	query := `SELECT a.music_id AS for_id, a.id, COALESCE(a.music_id, 0) AS music_id, COALESCE(a.group_id, 0) AS group_id,
+	a.key, a.ext, a.width, a.height, a.sizes
+	FROM artwork a
+	WHERE a.music_id = ANY($1)
+	UNION ALL
+	SELECT mg.music_id, a.id, 0, a.group_id, a.key, a.ext, a.width, a.height, a.sizes
+	FROM music_groups mg
+	JOIN artwork a ON a.group_id = mg.group_id
+	WHERE mg.music_id = ANY($1)`
+	if err := r.db.Select(&rows, query, pq.Array(musicIds)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := make(map[int][]models.Artwork, len(musicIds))
+	for _, row := range rows {
+		artwork := row.Artwork
+		for _, size := range row.Sizes {
+			artwork.Sizes = append(artwork.Sizes, int(size))
+		}
+		res[row.ForId] = append(res[row.ForId], artwork)
+	}
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package musicrepo
+
+import (
+	"fmt"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+// GetAudio returns the audio files of the songs that have one, keyed by
+// song id.
+func (r *Music) GetAudio(musicIds []int) (map[int]models.Audio, error) {
+	const op = "storage.music.GetAudio"
+
+	var files []models.Audio
+	query := `SELECT music_id, key, content_type, size FROM audio_files WHERE music_id = ANY($1)`
+	if err := r.db.Select(&files, query, pq.Array(musicIds)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := make(map[int]models.Audio, len(files))
+	for _, file := range files {
+		res[file.MusicId] = file
+	}
+	return res, nil
+}
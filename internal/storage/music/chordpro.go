@@ -0,0 +1,88 @@
+package musicrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"library-music/internal/domain/models"
+)
+
+var ErrChordProNotFound = errors.New("chordpro not found")
+
+// SetChordPro stores a ChordPro document together with the plain lyrics
+// rendered from it, which replace the text of the song and what was
+// derived from it.
+func (r *Music) SetChordPro(id int, document string, lyrics models.Music) error {
+	const op = "storage.music.SetChordPro"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.Exec(`UPDATE music SET chordpro = $1, text_song = $2 WHERE id = $3`, document, lyrics.Text, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		err = ErrMusicNotFound
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = r.replaceAnalysis(tx, id, lyrics); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteChordPro drops the document of a song; its lyrics are kept.
+func (r *Music) DeleteChordPro(id int) error {
+	const op = "storage.music.DeleteChordPro"
+	res, err := r.db.Exec(`UPDATE music SET chordpro = NULL WHERE id = $1 AND chordpro IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrChordProNotFound)
+	}
+	return nil
+}
+
+func (r *Music) GetChordPro(id int) (string, error) {
+	const op = "storage.music.GetChordPro"
+
+	var document sql.NullString
+	err := r.db.Get(&document, `SELECT chordpro FROM music WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !document.Valid {
+		return "", fmt.Errorf("%s: %w", op, ErrChordProNotFound)
+	}
+	return document.String, nil
+}
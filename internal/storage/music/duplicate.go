@@ -0,0 +1,198 @@
+package musicrepo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var ErrDifferentGroups = errors.New("songs are of different groups")
+
+// GetSongTitles returns the titles of all songs by group, with an empty
+// key for songs stored before keys were.
+func (r *Music) GetSongTitles() ([]models.SongTitle, error) {
+	const op = "storage.music.GetSongTitles"
+	query := `SELECT m.id, m.song, COALESCE(m.song_key, '') AS song_key, g.id AS group_id, g.name AS "group"
+	FROM music m
+	JOIN music_groups mg ON mg.music_id = m.id
+	JOIN groups g ON g.id = mg.group_id
+	ORDER BY g.id, m.id`
+
+	var titles []models.SongTitle
+	if err := r.db.Select(&titles, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return titles, nil
+}
+
+// SetSongKeys stores the keys of songs by id.
+func (r *Music) SetSongKeys(keys map[int]string) error {
+	const op = "storage.music.SetSongKeys"
+	ids := make([]int64, 0, len(keys))
+	values := make([]string, 0, len(keys))
+	for id, key := range keys {
+		ids = append(ids, int64(id))
+		values = append(values, key)
+	}
+
+	query := `UPDATE music m SET song_key = NULLIF(k.key, '')
+	FROM unnest($1::int[], $2::text[]) AS k(id, key)
+	WHERE m.id = k.id`
+	if _, err := r.db.Exec(query, pq.Array(ids), pq.Array(values)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Merge folds the song removeId into keepId: keepId takes the non-zero
+// fields of taken, and everything referring to removeId is moved to keepId
+// unless keepId already has it. Both songs must be of the same group, and
+// a title taken from removeId must not clash with a third song of it. The timings and ChordPro document follow
+// the text, so they are moved only when taken has a text. The blob keys
+// of files left to removeId are returned for the caller to delete.
+func (r *Music) Merge(keepId, removeId int, taken models.Music) ([]string, error) {
+	const op = "storage.music.Merge"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var locked []int
+	err = tx.Select(&locked, `SELECT id FROM music WHERE id IN ($1, $2) FOR UPDATE`, keepId, removeId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(locked) != 2 {
+		err = ErrMusicNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var groups int
+	err = tx.Get(&groups, `SELECT COUNT(DISTINCT group_id) FROM music_groups WHERE music_id IN ($1, $2)`, keepId, removeId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if groups != 1 {
+		err = ErrDifferentGroups
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if taken.Song != "" {
+		var exists bool
+		if exists, err = r.checkMergeOnDuplicate(tx, taken.Song, taken.SongKey, keepId, removeId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if exists {
+			err = ErrMusicAlreadyExists
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if query, args := generateUpdateQuery(taken, keepId); args != nil {
+		if _, err = tx.Exec(query, args...); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	queries := []string{
+		`INSERT INTO user_favorites (user_id, music_id, added_at)
+		SELECT user_id, $1, added_at FROM user_favorites WHERE music_id = $2
+		ON CONFLICT DO NOTHING`,
+		`INSERT INTO user_library (user_id, music_id, added_at)
+		SELECT user_id, $1, added_at FROM user_library WHERE music_id = $2
+		ON CONFLICT DO NOTHING`,
+		`UPDATE playlist_tracks t SET music_id = $1, song = m.song, group_name = g.name
+		FROM music m
+		JOIN music_groups mg ON mg.music_id = m.id
+		JOIN groups g ON g.id = mg.group_id
+		WHERE m.id = $1 AND t.music_id = $2`,
+		`UPDATE album_tracks t SET music_id = $1 WHERE music_id = $2
+		AND NOT EXISTS (SELECT 1 FROM album_tracks a WHERE a.album_id = t.album_id AND a.music_id = $1)`,
+		`INSERT INTO music_genres (music_id, genre_id)
+		SELECT $1, genre_id FROM music_genres WHERE music_id = $2
+		ON CONFLICT DO NOTHING`,
+		`INSERT INTO music_tags (music_id, tag_id)
+		SELECT $1, tag_id FROM music_tags WHERE music_id = $2
+		ON CONFLICT DO NOTHING`,
+		`INSERT INTO music_credits (music_id, person_id, role)
+		SELECT $1, person_id, role FROM music_credits WHERE music_id = $2
+		ON CONFLICT DO NOTHING`,
+		`INSERT INTO lyric_translations (music_id, lang, text)
+		SELECT $1, lang, text FROM lyric_translations WHERE music_id = $2
+		ON CONFLICT DO NOTHING`,
+		`UPDATE music_links l SET music_id = $1 WHERE music_id = $2
+		AND NOT EXISTS (SELECT 1 FROM music_links x WHERE x.music_id = $1 AND x.url = l.url)`,
+		`UPDATE artwork SET music_id = $1 WHERE music_id = $2
+		AND NOT EXISTS (SELECT 1 FROM artwork WHERE music_id = $1)`,
+		`UPDATE audio_files SET music_id = $1 WHERE music_id = $2
+		AND NOT EXISTS (SELECT 1 FROM audio_files WHERE music_id = $1)`,
+		`UPDATE music_relations r SET music_id = $1 WHERE music_id = $2 AND related_id <> $1
+		AND NOT EXISTS (SELECT 1 FROM music_relations x WHERE x.music_id = $1 AND x.related_id = r.related_id AND x.type = r.type)`,
+		`UPDATE music_relations r SET related_id = $1 WHERE related_id = $2 AND music_id <> $1
+		AND NOT EXISTS (SELECT 1 FROM music_relations x WHERE x.related_id = $1 AND x.music_id = r.music_id AND x.type = r.type)`,
+	}
+	if taken.Text != "" {
+		queries = append(queries,
+			`DELETE FROM lyric_timings WHERE music_id = $1`,
+			`UPDATE lyric_timings SET music_id = $1 WHERE music_id = $2`,
+			`UPDATE music SET chordpro = (SELECT chordpro FROM music WHERE id = $2) WHERE id = $1`,
+		)
+	}
+
+	for _, query := range queries {
+		if _, err = tx.Exec(query, keepId, removeId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	var keys []string
+	query := `SELECT key FROM audio_files WHERE music_id = $1
+	UNION ALL SELECT key FROM artwork WHERE music_id = $1`
+	if err = tx.Select(&keys, query, removeId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = tx.Exec(`DELETE FROM music WHERE id = $1`, removeId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if taken.Text != "" {
+		if err = r.replaceAnalysis(tx, keepId, taken); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return keys, nil
+}
+
+// checkMergeOnDuplicate reports whether a song of the group other than the
+// two merged already has the title the kept song takes.
+func (r *Music) checkMergeOnDuplicate(tx *sqlx.Tx, song, key string, keepId, removeId int) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1
+		FROM music m
+		JOIN music_groups mg ON m.id = mg.music_id
+		JOIN music_groups mg2 ON mg.group_id = mg2.group_id
+		WHERE (m.song = $1 OR m.song_key = NULLIF($2, '')) AND mg2.music_id = $3 AND m.id NOT IN ($3, $4)
+	)`
+
+	var exists bool
+	if err := tx.Get(&exists, query, song, key, keepId, removeId); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -26,8 +26,9 @@ func (r *Music) updateWithGroup(tx *sqlx.Tx, musicId int, group string) error {
 	return nil
 }
 
+// getGroupIDByName resolves aliases to their group.
 func (r *Music) getGroupIDByName(tx *sqlx.Tx, groupName string) (int, error) {
-	query := `SELECT id FROM groups WHERE name = $1`
+	query := `SELECT id FROM groups WHERE id = group_id_by_name($1)`
 	var groupID int
 	err := tx.Get(&groupID, query, groupName)
 	if err != nil {
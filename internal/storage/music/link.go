@@ -0,0 +1,90 @@
+package musicrepo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrLinkNotFound      = errors.New("link not found")
+	ErrLinkAlreadyExists = errors.New("link already exists")
+)
+
+func (r *Music) AddLink(musicId int, link models.MusicLink) (int, error) {
+	const op = "storage.music.AddLink"
+	query := `INSERT INTO music_links (music_id, provider, url, provider_id)
+	VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING id;`
+
+	var id int
+	err := r.db.QueryRow(query, musicId, link.Provider, link.URL, link.ProviderId).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23503":
+				return -1, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+			case "23505":
+				return -1, fmt.Errorf("%s: %w", op, ErrLinkAlreadyExists)
+			}
+		}
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+// addLinks keeps the links of a song as it is added or updated, skipping
+// the ones it already has.
+func (r *Music) addLinks(tx *sqlx.Tx, musicId int, links []models.MusicLink) error {
+	query := `INSERT INTO music_links (music_id, provider, url, provider_id)
+	VALUES ($1, $2, $3, NULLIF($4, '')) ON CONFLICT (music_id, url) DO NOTHING;`
+
+	for _, link := range links {
+		if _, err := tx.Exec(query, musicId, link.Provider, link.URL, link.ProviderId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Music) DeleteLink(musicId, linkId int) error {
+	const op = "storage.music.DeleteLink"
+	res, err := r.db.Exec(`DELETE FROM music_links WHERE id = $1 AND music_id = $2`, linkId, musicId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrLinkNotFound)
+	}
+	return nil
+}
+
+// GetLinks returns the links of the songs, keyed by song id.
+func (r *Music) GetLinks(musicIds []int) (map[int][]models.MusicLink, error) {
+	const op = "storage.music.GetLinks"
+
+	var links []models.MusicLink
+	query := `SELECT id, music_id, provider, url, COALESCE(provider_id, '') AS provider_id,
+	COALESCE(status_code, 0) AS status_code, COALESCE(redirect_url, '') AS redirect_url,
+	COALESCE(check_error, '') AS check_error, checked_at
+	FROM music_links
+	WHERE music_id = ANY($1)
+	ORDER BY music_id, id`
+	if err := r.db.Select(&links, query, pq.Array(musicIds)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := make(map[int][]models.MusicLink, len(musicIds))
+	for _, link := range links {
+		res[link.MusicId] = append(res[link.MusicId], link)
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package musicrepo
+
+import (
+	"fmt"
+	"library-music/internal/domain/models"
+)
+
+// GetLinksToCheck returns the links of a song, or of every song when
+// musicId is 0, least recently checked first.
+func (r *Music) GetLinksToCheck(musicId int) ([]models.MusicLink, error) {
+	const op = "storage.music.GetLinksToCheck"
+
+	var links []models.MusicLink
+	query := `SELECT id, music_id, provider, url, COALESCE(provider_id, '') AS provider_id
+	FROM music_links
+	WHERE $1 = 0 OR music_id = $1
+	ORDER BY checked_at NULLS FIRST, id`
+	if err := r.db.Select(&links, query, musicId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return links, nil
+}
+
+func (r *Music) SetLinkCheck(linkId int, check models.LinkCheck) error {
+	const op = "storage.music.SetLinkCheck"
+	query := `UPDATE music_links
+	SET status_code = NULLIF($1, 0), redirect_url = NULLIF($2, ''), check_error = NULLIF($3, ''), checked_at = $4
+	WHERE id = $5`
+
+	res, err := r.db.Exec(query, check.StatusCode, check.RedirectURL, check.CheckError, check.CheckedAt, linkId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrLinkNotFound)
+	}
+	return nil
+}
+
+// GetBrokenLinks returns a page of the links whose last check failed or
+// answered with an error status, most recently checked first.
+func (r *Music) GetBrokenLinks(countLinks, page int) ([]models.BrokenLink, error) {
+	const op = "storage.music.GetBrokenLinks"
+
+	var links []models.BrokenLink
+	query := `SELECT l.id, l.music_id, l.provider, l.url, COALESCE(l.provider_id, '') AS provider_id,
+	COALESCE(l.status_code, 0) AS status_code, COALESCE(l.redirect_url, '') AS redirect_url,
+	COALESCE(l.check_error, '') AS check_error, l.checked_at,
+	m.song, COALESCE(g.name, '') AS "group"
+	FROM music_links l
+	JOIN music m ON m.id = l.music_id
+	LEFT JOIN music_groups mg ON mg.music_id = m.id
+	LEFT JOIN groups g ON g.id = mg.group_id
+	WHERE l.check_error IS NOT NULL OR l.status_code >= 400
+	ORDER BY l.checked_at DESC, l.id
+	LIMIT $1 OFFSET $2`
+
+	if err := r.db.Select(&links, query, countLinks, (page-1)*countLinks); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(links) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrLinkNotFound)
+	}
+	return links, nil
+}
@@ -0,0 +1,175 @@
+package musicrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+// replaceSections stores the sections of a song in place of the previous ones.
+func (r *Music) replaceSections(tx *sqlx.Tx, musicId int, sections []models.LyricSection) error {
+	if _, err := tx.Exec(`DELETE FROM lyric_sections WHERE music_id = $1`, musicId); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO lyric_sections (music_id, position, type, ordinal, label, lines) VALUES ($1, $2, $3, $4, $5, $6);`
+	for i, s := range sections {
+		if _, err := tx.Exec(query, musicId, i+1, s.Type, s.Ordinal, s.Label, pq.Array(s.Lines)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceAnalysis stores what was derived from a new text of the song:
+// its sections, statistics, language and explicit flag.
+func (r *Music) replaceAnalysis(tx *sqlx.Tx, id int, music models.Music) error {
+	query := `UPDATE music SET lang = CASE WHEN lang_manual THEN lang ELSE NULLIF($1, '') END, explicit = $2 WHERE id = $3`
+	if _, err := tx.Exec(query, music.Lang, music.Explicit, id); err != nil {
+		return err
+	}
+	if err := r.replaceStats(tx, id, music.Stats); err != nil {
+		return err
+	}
+	return r.replaceSections(tx, id, music.Sections)
+}
+
+func (r *Music) GetLyrics(song, group string) (models.Lyrics, error) {
+	const op = "storage.music.GetLyrics"
+
+	var lyrics models.Lyrics
+	query := `SELECT m.id, m.text_song, COALESCE(m.lang, '')
+	FROM music m
+	JOIN music_groups mg ON m.id = mg.music_id
+	JOIN groups g ON mg.group_id = g.id
+	WHERE (m.song = $1 OR m.song_search = search_key($1))
+		AND (g.id = group_id_by_name($2) OR g.name_search = search_key($2))
+	ORDER BY m.song <> $1, g.id IS DISTINCT FROM group_id_by_name($2), m.id
+	LIMIT 1`
+
+	err := r.db.QueryRow(query, song, group).Scan(&lyrics.MusicId, &lyrics.Text, &lyrics.Lang)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Lyrics{}, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		return models.Lyrics{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var rows []struct {
+		models.LyricSection
+		Lines pq.StringArray `db:"lines"`
+	}
+	query = `SELECT position, type, ordinal, label, lines FROM lyric_sections WHERE music_id = $1 ORDER BY position`
+	if err = r.db.Select(&rows, query, lyrics.MusicId); err != nil {
+		return models.Lyrics{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	lyrics.Sections = make([]models.LyricSection, len(rows))
+	for i, row := range rows {
+		lyrics.Sections[i] = row.LyricSection
+		lyrics.Sections[i].Lines = row.Lines
+	}
+	return lyrics, nil
+}
+
+// SearchLines returns a page of the lines containing phrase, ignoring
+// case, in the order of the songs and their lines. Verse is the position
+// of the section and Line the position of the line in the verse as
+// GetTextMusic shows it, label included, both from 1. Songs without
+// stored sections are not searched.
+func (r *Music) SearchLines(phrase string, context, countMatches, page int) ([]models.LyricMatch, error) {
+	const op = "storage.music.SearchLines"
+	query := `WITH lines AS (
+		SELECT s.music_id, s.position, l.line_no + (s.label <> '')::int AS line_no, l.text,
+		       row_number() OVER (PARTITION BY s.music_id ORDER BY s.position, l.line_no) AS n
+		FROM lyric_sections s, unnest(s.lines) WITH ORDINALITY AS l(text, line_no)
+	), hits AS (
+		SELECT * FROM lines
+		WHERE strpos(lower(text), lower($1)) > 0
+		ORDER BY music_id, n
+		LIMIT $3 OFFSET $4
+	)
+	SELECT h.music_id, m.song, COALESCE(g.name, '') AS "group", h.position AS verse, h.line_no AS line, h.text,
+	       ARRAY(SELECT c.text FROM lines c WHERE c.music_id = h.music_id AND c.n BETWEEN h.n - $2 AND h.n - 1 ORDER BY c.n) AS before,
+	       ARRAY(SELECT c.text FROM lines c WHERE c.music_id = h.music_id AND c.n BETWEEN h.n + 1 AND h.n + $2 ORDER BY c.n) AS after
+	FROM hits h
+	JOIN music m ON m.id = h.music_id
+	LEFT JOIN music_groups mg ON mg.music_id = m.id
+	LEFT JOIN groups g ON g.id = mg.group_id
+	ORDER BY h.music_id, h.n`
+
+	var rows []struct {
+		models.LyricMatch
+		Before pq.StringArray `db:"before"`
+		After  pq.StringArray `db:"after"`
+	}
+	if err := r.db.Select(&rows, query, phrase, context, countMatches, (page-1)*countMatches); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	}
+
+	matches := make([]models.LyricMatch, len(rows))
+	for i, row := range rows {
+		matches[i] = row.LyricMatch
+		matches[i].Before = row.Before
+		matches[i].After = row.After
+	}
+	return matches, nil
+}
+
+// GetUnsectioned returns the lyrics of the songs with a text but no stored
+// sections, which were added before sections were stored.
+func (r *Music) GetUnsectioned() ([]models.Lyrics, error) {
+	const op = "storage.music.GetUnsectioned"
+	query := `SELECT m.id, m.text_song
+	FROM music m
+	WHERE btrim(m.text_song) <> ''
+	  AND NOT EXISTS (SELECT 1 FROM lyric_sections s WHERE s.music_id = m.id)`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []models.Lyrics
+	for rows.Next() {
+		var lyrics models.Lyrics
+		if err = rows.Scan(&lyrics.MusicId, &lyrics.Text); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		res = append(res, lyrics)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+// SetSections stores the sections of songs by id.
+func (r *Music) SetSections(sections map[int][]models.LyricSection) error {
+	const op = "storage.music.SetSections"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for musicId, s := range sections {
+		if err = r.replaceSections(tx, musicId, s); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -2,12 +2,14 @@ package musicrepo
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	"library-music/internal/domain/models"
 	"reflect"
 	"strings"
+	"time"
 )
 
 var (
@@ -39,7 +41,7 @@ func (r *Music) Add(music models.Music) (int, error) {
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
-	exists, err := r.checkSongInGroup(tx, music.Song, music.Group.Name)
+	exists, err := r.checkSongInGroup(tx, music.Song, music.SongKey, music.Group.Name)
 	if err != nil {
 		_ = tx.Rollback()
 		return -1, fmt.Errorf("%s: %w", op, err)
@@ -50,13 +52,27 @@ func (r *Music) Add(music models.Music) (int, error) {
 		return -1, fmt.Errorf("%s: %w", op, ErrMusicAlreadyExists)
 	}
 
+	if music.Album != nil {
+		err = r.checkAlbum(tx, music.Album.AlbumId, music.ReleaseDate.IsZero())
+		if err != nil {
+			_ = tx.Rollback()
+			return -1, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	musicId, err := r.insertMusic(tx, music)
 	if err != nil {
 		_ = tx.Rollback()
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
-	query := `WITH group_cte AS (SELECT id FROM groups WHERE name = $1)
+	err = r.addLinks(tx, musicId, music.Links)
+	if err != nil {
+		_ = tx.Rollback()
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `WITH group_cte AS (SELECT group_id_by_name($1) AS id)
 		INSERT INTO music_groups (music_id, group_id) 
 		SELECT $2, g.id FROM group_cte g
 		ON CONFLICT DO NOTHING;`
@@ -67,6 +83,26 @@ func (r *Music) Add(music models.Music) (int, error) {
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if music.Album != nil {
+		err = r.insertAlbumTrack(tx, musicId, *music.Album)
+		if err != nil {
+			_ = tx.Rollback()
+			return -1, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = r.replaceSections(tx, musicId, music.Sections)
+	if err != nil {
+		_ = tx.Rollback()
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = r.replaceStats(tx, musicId, music.Stats)
+	if err != nil {
+		_ = tx.Rollback()
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err = tx.Commit(); err != nil {
 		_ = tx.Rollback()
 		return -1, fmt.Errorf("%s: %w", op, err)
@@ -74,57 +110,97 @@ func (r *Music) Add(music models.Music) (int, error) {
 	return musicId, nil
 }
 
+// insertMusic falls back to the release date of the album
+// the song is added to when the song has none of its own.
 func (r *Music) insertMusic(tx *sqlx.Tx, music models.Music) (int, error) {
-	query := `INSERT INTO music (song, text_song, release_date, link) VALUES ($1, $2, $3, $4) RETURNING id;`
+	query := `INSERT INTO music (song, text_song, release_date, link, lang, explicit, song_key)
+	VALUES ($1, $2, COALESCE($3, (SELECT release_date FROM albums WHERE id = $5)), $4, NULLIF($6, ''), $7, NULLIF($8, '')) RETURNING id;`
+
+	var releaseDate *time.Time
+	if !music.ReleaseDate.IsZero() {
+		releaseDate = &music.ReleaseDate
+	}
+
+	var albumId *int
+	if music.Album != nil {
+		albumId = &music.Album.AlbumId
+	}
 
 	var musicId int
-	row := tx.QueryRow(query, music.Song, music.Text, music.ReleaseDate, music.Link)
+	row := tx.QueryRow(query, music.Song, music.Text, releaseDate, music.Link, albumId, music.Lang, music.Explicit, music.SongKey)
 	if err := row.Scan(&musicId); err != nil {
 		return -1, err
 	}
 	return musicId, nil
 }
 
+// insertGroup creates the group unless the name is already taken by a
+// group or one of its aliases.
 func (r *Music) insertGroup(tx *sqlx.Tx, groupName string) error {
-	query := `INSERT INTO groups (name) VALUES ($1) ON CONFLICT DO NOTHING;`
+	query := `INSERT INTO groups (name) SELECT $1 WHERE group_id_by_name($1) IS NULL ON CONFLICT DO NOTHING;`
 	_, err := tx.Exec(query, groupName)
 	return err
 }
 
-func (r *Music) checkSongInGroup(tx *sqlx.Tx, song, groupName string) (bool, error) {
+// checkSongInGroup matches songs by their key as well, so that titles
+// differing in case, punctuation or remaster notes are duplicates too.
+func (r *Music) checkSongInGroup(tx *sqlx.Tx, song, key, groupName string) (bool, error) {
 	query := `SELECT EXISTS (
 		SELECT 1
 		FROM music m 
 		JOIN music_groups mg ON m.id = mg.music_id
-		JOIN groups g ON mg.group_id = g.id
-		WHERE m.song = $1 AND g.name = $2
+		WHERE (m.song = $1 OR m.song_key = NULLIF($3, '')) AND mg.group_id = group_id_by_name($2)
 	)`
 
 	var exists bool
-	err := tx.Get(&exists, query, song, groupName)
+	err := tx.Get(&exists, query, song, groupName, key)
 	if err != nil {
 		return false, err
 	}
 	return exists, nil
 }
 
-func (r *Music) Delete(id int) error {
+// Delete removes a song and returns the blob keys of the files only it
+// used, which the caller deletes from the blob store.
+func (r *Music) Delete(id int) ([]string, error) {
 	const op = "storage.music.Delete"
-	query := "DELETE FROM music WHERE id=$1"
-	res, err := r.db.Exec(query, id)
+	tx, err := r.db.Beginx()
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var keys []string
+	query := `SELECT key FROM audio_files WHERE music_id = $1
+	UNION ALL SELECT key FROM artwork WHERE music_id = $1`
+	if err = tx.Select(&keys, query, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.Exec("DELETE FROM music WHERE id=$1", id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		err = ErrMusicNotFound
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	return nil
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return keys, nil
 }
 
 func (r *Music) Update(music models.Music, id int) error {
@@ -160,7 +236,7 @@ func (r *Music) Update(music models.Music, id int) error {
 	}
 
 	if music.Song != "" {
-		res, err := r.checkUpdateOnDuplicate(tx, music.Song, id)
+		res, err := r.checkUpdateOnDuplicate(tx, music.Song, music.SongKey, id)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
@@ -184,6 +260,18 @@ func (r *Music) Update(music models.Music, id int) error {
 		return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
 	}
 
+	err = r.addLinks(tx, id, music.Links)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if music.Text != "" {
+		err = r.replaceAnalysis(tx, id, music)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -191,6 +279,10 @@ func (r *Music) Update(music models.Music, id int) error {
 	return nil
 }
 
+// derivedFields are not updated from their own values: the group has its
+// own table and the analysis fields follow the text.
+var derivedFields = map[string]bool{"Group": true, "Lang": true, "LangManual": true, "Explicit": true}
+
 func generateUpdateQuery(music models.Music, id int) (string, []interface{}) {
 	query := "UPDATE music SET "
 	var updates []string
@@ -199,8 +291,9 @@ func generateUpdateQuery(music models.Music, id int) (string, []interface{}) {
 	t := reflect.TypeOf(music)
 
 	for i := 0; i < v.NumField(); i++ {
-		if !v.Field(i).IsZero() && t.Field(i).Name != "Group" {
-			updates = append(updates, fmt.Sprintf("%s = $%d", t.Field(i).Tag.Get("db"), len(args)+1))
+		column := t.Field(i).Tag.Get("db")
+		if !v.Field(i).IsZero() && !derivedFields[t.Field(i).Name] && column != "-" {
+			updates = append(updates, fmt.Sprintf("%s = $%d", column, len(args)+1))
 			args = append(args, v.Field(i).Interface())
 		}
 	}
@@ -214,17 +307,17 @@ func generateUpdateQuery(music models.Music, id int) (string, []interface{}) {
 	return query, args
 }
 
-func (r *Music) checkUpdateOnDuplicate(tx *sqlx.Tx, song string, id int) (bool, error) {
+func (r *Music) checkUpdateOnDuplicate(tx *sqlx.Tx, song, key string, id int) (bool, error) {
 	query := `SELECT EXISTS (
     	SELECT 1
 		FROM music m
 		JOIN music_groups mg ON m.id = mg.music_id
 		JOIN music_groups mg2 ON mg.group_id = mg2.group_id
-		WHERE m.song = $1 AND mg2.music_id = $2 AND m.id <> $2
+		WHERE (m.song = $1 OR m.song_key = NULLIF($3, '')) AND mg2.music_id = $2 AND m.id <> $2
 	)`
 
 	var exists bool
-	err := tx.Get(&exists, query, song, id)
+	err := tx.Get(&exists, query, song, id, key)
 	if err != nil {
 		return false, err
 	}
@@ -234,7 +327,9 @@ func (r *Music) checkUpdateOnDuplicate(tx *sqlx.Tx, song string, id int) (bool,
 func (r *Music) GetById(id int) (models.Music, error) {
 	const op = "storage.music.GetById"
 	var music models.Music
-	query := `SELECT m.id, m.song, m.text_song, m.link, m.release_date, g.name
+	query := `SELECT m.id, m.song, m.text_song, m.link, m.release_date, COALESCE(m.lang, '') AS lang, m.lang_manual, m.explicit,
+	g.id AS "group.id",
+	g.name AS "group.name"
 	FROM music m
 	JOIN music_groups mg ON mg.music_id = m.id
 	JOIN groups g ON g.id = mg.group_id
@@ -250,7 +345,7 @@ func (r *Music) GetById(id int) (models.Music, error) {
 	return music, nil
 }
 
-func (r *Music) GetAll(params models.Music, countSongs, page int) ([]models.Music, error) {
+func (r *Music) GetAll(params models.MusicFilter, countSongs, page int) ([]models.Music, error) {
 	const op = "storage.music.GetAll"
 	var musics []models.Music
 	query, args := generateQuery(params, countSongs, page)
@@ -265,70 +360,218 @@ func (r *Music) GetAll(params models.Music, countSongs, page int) ([]models.Musi
 	return musics, nil
 }
 
-func generateQuery(params models.Music, countSongs, page int) (string, []interface{}) {
-	query := `SELECT m.id, m.song, m.text_song, m.link, m.release_date, 
+func generateQuery(params models.MusicFilter, countSongs, page int) (string, []interface{}) {
+	query := `SELECT m.id, m.song, m.text_song, m.link, m.release_date,
+       COALESCE(m.lang, '') AS lang, m.explicit,
        g.id AS "group.id",
        g.name AS "group.name"
        FROM music m
        LEFT JOIN music_groups mg ON mg.music_id = m.id
        LEFT JOIN groups g ON g.id = mg.group_id`
 
+	where, args := generateConditions(params)
+	query += where
+
+	offset := (page - 1) * countSongs
+	query += fmt.Sprintf(" ORDER BY m.id LIMIT %d OFFSET %d", countSongs, offset)
+	return query, args
+}
+
+// GetAllWithFacets returns a page of songs together with facet counts over
+// every song matching params. Both come from a single query: the facets are
+// computed once over the filtered set and joined to each row of the page.
+func (r *Music) GetAllWithFacets(params models.MusicFilter, countSongs, page int) ([]models.Music, models.MusicFacets, error) {
+	const op = "storage.music.GetAllWithFacets"
+	var rows []struct {
+		models.Music
+		Facets []byte `db:"facets"`
+	}
+
+	query, args := generateFacetQuery(params, countSongs, page)
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, models.MusicFacets{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, models.MusicFacets{}, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	}
+
+	var facets models.MusicFacets
+	if err := json.Unmarshal(rows[0].Facets, &facets); err != nil {
+		return nil, models.MusicFacets{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	musics := make([]models.Music, len(rows))
+	for i, row := range rows {
+		musics[i] = row.Music
+	}
+	return musics, facets, nil
+}
+
+// generateFacetQuery counts groups, release years and decades, genres and
+// tags over the filtered set. Genres are counted as assigned, without
+// rolling subgenres up; tags count both song and group tags.
+func generateFacetQuery(params models.MusicFilter, countSongs, page int) (string, []interface{}) {
+	where, args := generateConditions(params)
+	query := `WITH filtered AS (
+		SELECT m.id, m.song, m.text_song, m.link, m.release_date,
+		COALESCE(m.lang, '') AS lang, m.explicit,
+		g.id AS group_id, g.name AS group_name
+		FROM music m
+		LEFT JOIN music_groups mg ON mg.music_id = m.id
+		LEFT JOIN groups g ON g.id = mg.group_id` + where + `
+	),
+	facets AS (
+		SELECT json_build_object(
+			'total', (SELECT COUNT(DISTINCT id) FROM filtered),
+			'groups', COALESCE((
+				SELECT json_agg(json_build_object('id', group_id, 'value', group_name, 'count', n) ORDER BY n DESC, group_name)
+				FROM (SELECT group_id, group_name, COUNT(DISTINCT id) AS n FROM filtered
+					WHERE group_id IS NOT NULL GROUP BY group_id, group_name) s
+			), '[]'::json),
+			'years', COALESCE((
+				SELECT json_agg(json_build_object('value', y::text, 'count', n) ORDER BY y DESC)
+				FROM (SELECT EXTRACT(YEAR FROM release_date)::int AS y, COUNT(DISTINCT id) AS n FROM filtered GROUP BY 1) s
+			), '[]'::json),
+			'decades', COALESCE((
+				SELECT json_agg(json_build_object('value', d::text || 's', 'count', n) ORDER BY d DESC)
+				FROM (SELECT EXTRACT(YEAR FROM release_date)::int / 10 * 10 AS d, COUNT(DISTINCT id) AS n FROM filtered GROUP BY 1) s
+			), '[]'::json),
+			'genres', COALESCE((
+				SELECT json_agg(json_build_object('id', id, 'value', name, 'count', n) ORDER BY n DESC, name)
+				FROM (SELECT ge.id, ge.name, COUNT(DISTINCT f.id) AS n FROM filtered f
+					JOIN music_genres mgn ON mgn.music_id = f.id
+					JOIN genres ge ON ge.id = mgn.genre_id
+					GROUP BY ge.id, ge.name) s
+			), '[]'::json),
+			'tags', COALESCE((
+				SELECT json_agg(json_build_object('value', name, 'count', n) ORDER BY n DESC, name)
+				FROM (SELECT t.name, COUNT(DISTINCT f.id) AS n FROM filtered f
+					JOIN tags t ON t.id IN (
+						SELECT tag_id FROM music_tags WHERE music_id = f.id
+						UNION
+						SELECT tag_id FROM group_tags WHERE group_id = f.group_id
+					)
+					GROUP BY t.id, t.name) s
+			), '[]'::json)
+		) AS data
+	)
+	SELECT f.id, f.song, f.text_song, f.link, f.release_date, f.lang, f.explicit,
+	f.group_id AS "group.id",
+	f.group_name AS "group.name",
+	fc.data AS facets
+	FROM filtered f
+	CROSS JOIN facets fc`
+
+	offset := (page - 1) * countSongs
+	query += fmt.Sprintf(" ORDER BY f.id LIMIT %d OFFSET %d", countSongs, offset)
+	return query, args
+}
+
+// generateConditions builds the WHERE clause shared by song listings.
+// It expects music aliased as m and groups as g.
+func generateConditions(params models.MusicFilter) (string, []interface{}) {
+	var conditions []string
 	var args []interface{}
-	isWhere := false
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
 
 	if params.Song != "" {
-		args = append(args, params.Song)
-		query += addCondition("m.song", len(args), false)
-		isWhere = true
+		add("(m.song = $%[1]d OR m.song_search = search_key($%[1]d))", params.Song)
 	}
 
 	if params.Text != "" {
-		args = append(args, params.Text)
-		query += addCondition("m.text_song", len(args), isWhere)
-		isWhere = true
+		add("m.text_song = $%d", params.Text)
 	}
 
 	if params.Link != "" {
-		args = append(args, params.Link)
-		query += addCondition("m.link", len(args), isWhere)
-		isWhere = true
+		add("m.link = $%d", params.Link)
 	}
 
-	if params.Group.Name != "" {
-		args = append(args, params.Group.Name)
-		query += addCondition("g.name", len(args), isWhere)
-		isWhere = true
+	if params.Group != "" {
+		add("(g.id = group_id_by_name($%[1]d) OR g.name_search = search_key($%[1]d))", params.Group)
 	}
 
 	if !params.ReleaseDate.IsZero() {
-		args = append(args, params.ReleaseDate)
-		query += addCondition("m.release_date", len(args), isWhere)
-		isWhere = true
+		add("m.release_date = $%d", params.ReleaseDate)
 	}
 
-	offset := (page - 1) * countSongs
-	query += fmt.Sprintf(" LIMIT %d OFFSET %d", countSongs, offset)
-	return query, args
-}
+	if params.AlbumId != 0 {
+		add("m.id IN (SELECT music_id FROM album_tracks WHERE album_id = $%d)", params.AlbumId)
+	}
+
+	if params.GenreId != 0 {
+		add(`m.id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM genres WHERE id = $%d
+				UNION
+				SELECT ge.id FROM genres ge JOIN subtree s ON ge.parent_id = s.id
+			)
+			SELECT mgn.music_id FROM music_genres mgn JOIN subtree s ON s.id = mgn.genre_id
+		)`, params.GenreId)
+	}
+
+	for _, tag := range params.Tags {
+		add(`(m.id IN (SELECT mt.music_id FROM music_tags mt JOIN tags t ON t.id = mt.tag_id WHERE t.name = $%[1]d)
+			OR mg.group_id IN (SELECT gt.group_id FROM group_tags gt JOIN tags t ON t.id = gt.tag_id WHERE t.name = $%[1]d))`, tag)
+	}
+
+	if params.Lang != "" {
+		add("(m.lang = $%[1]d OR m.lang LIKE $%[1]d || '-%%')", params.Lang)
+	}
+
+	if params.Explicit != nil {
+		add("m.explicit = $%d", *params.Explicit)
+	}
+
+	switch {
+	case params.PersonId != 0 && params.Role != "":
+		args = append(args, params.PersonId, params.Role)
+		conditions = append(conditions, fmt.Sprintf(
+			"m.id IN (SELECT music_id FROM music_credits WHERE person_id = $%d AND role = $%d)", len(args)-1, len(args)))
+	case params.PersonId != 0:
+		add("m.id IN (SELECT music_id FROM music_credits WHERE person_id = $%d)", params.PersonId)
+	case params.Role != "":
+		add("m.id IN (SELECT music_id FROM music_credits WHERE role = $%d)", params.Role)
+	}
 
-func addCondition(field string, paramIndex int, isWhere bool) string {
-	condition := fmt.Sprintf("%s = $%d", field, paramIndex)
-	if isWhere {
-		return " AND " + condition
+	switch {
+	case params.MemberId != 0 && params.MemberYear != 0:
+		args = append(args, params.MemberId, params.MemberYear)
+		conditions = append(conditions, fmt.Sprintf(`mg.group_id IN (SELECT group_id FROM group_members WHERE person_id = $%[1]d
+			AND (joined_on IS NULL OR joined_on <= make_date($%[2]d, 12, 31))
+			AND (left_on IS NULL OR left_on >= make_date($%[2]d, 1, 1)))`, len(args)-1, len(args)))
+	case params.MemberId != 0:
+		add("mg.group_id IN (SELECT group_id FROM group_members WHERE person_id = $%d)", params.MemberId)
 	}
-	return " WHERE " + condition
+
+	if params.Provider != "" {
+		add("m.id IN (SELECT music_id FROM music_links WHERE provider = $%d)", params.Provider)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
 }
 
 func (r *Music) Get(song, group string) (models.Music, error) {
 	const op = "storage.music.Get"
 
 	var foundMusic models.Music
-	query := `SELECT m.id, m.song, m.text_song, m.link, m.release_date, g.id AS "group.id",
+	query := `SELECT m.id, m.song, m.text_song, m.link, m.release_date,
+        COALESCE(m.lang, '') AS lang, m.explicit,
+        g.id AS "group.id",
         g.name AS "group.name"
     FROM music m 
     JOIN music_groups mg ON m.id = mg.music_id 
     JOIN groups g ON mg.group_id = g.id 
-    WHERE m.song = $1 AND g.name = $2`
+    WHERE (m.song = $1 OR m.song_search = search_key($1))
+        AND (g.id = group_id_by_name($2) OR g.name_search = search_key($2))
+    ORDER BY m.song <> $1, g.id IS DISTINCT FROM group_id_by_name($2), m.id
+    LIMIT 1`
 
 	err := r.db.Get(&foundMusic, query, song, group)
 	if err != nil {
@@ -339,23 +582,3 @@ func (r *Music) Get(song, group string) (models.Music, error) {
 	}
 	return foundMusic, nil
 }
-
-func (r *Music) GetText(song, group string) (string, error) {
-	const op = "storage.music.GetText"
-
-	var text string
-	query := `SELECT m.text_song
-	FROM music m
-	JOIN music_groups mg on m.id = mg.music_id
-	JOIN groups g ON mg.group_id = g.id
-	WHERE m.song = $1 AND g.name =$2`
-	err := r.db.Get(&text, query, song, group)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return "", fmt.Errorf("%s: %w", op, ErrMusicNotFound)
-		}
-		return "", fmt.Errorf("%s: %w", op, err)
-	}
-	return text, err
-}
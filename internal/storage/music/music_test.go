@@ -0,0 +1,48 @@
+package musicrepo
+
+import (
+	"library-music/internal/domain/models"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGenerateConditionsMember(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     models.MusicFilter
+		args       []interface{}
+		membership bool
+	}{
+		{name: "any time", filter: models.MusicFilter{MemberId: 3}, args: []interface{}{3}},
+		{name: "during a year", filter: models.MusicFilter{MemberId: 3, MemberYear: 1985}, args: []interface{}{3, 1985}, membership: true},
+		{name: "after other filters", filter: models.MusicFilter{Song: "song", MemberId: 3, MemberYear: 1985}, args: []interface{}{"song", 3, 1985}, membership: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := generateConditions(tt.filter)
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("generateConditions() args = %v, want %v", args, tt.args)
+			}
+
+			n := len(tt.args)
+			bounded := strings.Contains(where, "joined_on <= make_date($"+strconv.Itoa(n)+", 12, 31)") &&
+				strings.Contains(where, "left_on >= make_date($"+strconv.Itoa(n)+", 1, 1)") &&
+				strings.Contains(where, "person_id = $"+strconv.Itoa(n-1))
+			if bounded != tt.membership {
+				t.Errorf("generateConditions() = %s, membership bounded: %v, want %v", where, bounded, tt.membership)
+			}
+		})
+	}
+}
+
+// A song of several groups is one row per group of the filtered set, but
+// one song of the total.
+func TestGenerateFacetQueryCountsSongsOnce(t *testing.T) {
+	query, _ := generateFacetQuery(models.MusicFilter{}, 10, 1)
+	if !strings.Contains(query, "'total', (SELECT COUNT(DISTINCT id) FROM filtered)") {
+		t.Errorf("generateFacetQuery() total does not count distinct songs:\n%s", query)
+	}
+}
@@ -0,0 +1,172 @@
+package musicrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrStatsNotFound = errors.New("lyrics statistics not found")
+	ErrGroupNotFound = errors.New("group not found")
+)
+
+// replaceStats stores the statistics of a song in place of the previous
+// ones; nil stats only drop them.
+func (r *Music) replaceStats(tx *sqlx.Tx, musicId int, stats *models.LyricStats) error {
+	if _, err := tx.Exec(`DELETE FROM lyric_stats WHERE music_id = $1`, musicId); err != nil {
+		return err
+	}
+
+	if stats == nil {
+		return nil
+	}
+
+	query := `INSERT INTO lyric_stats (music_id, words, lines, repeated_lines, line_chars) VALUES ($1, $2, $3, $4, $5);`
+	_, err := tx.Exec(query, musicId, stats.Words, stats.Lines, stats.RepeatedLines, stats.LineChars)
+	if err != nil {
+		return err
+	}
+
+	words := make([]string, len(stats.Counts))
+	counts := make([]int64, len(stats.Counts))
+	stopwords := make([]bool, len(stats.Counts))
+	for i, c := range stats.Counts {
+		words[i], counts[i], stopwords[i] = c.Word, int64(c.Count), c.Stopword
+	}
+
+	query = `INSERT INTO lyric_words (music_id, word, count, stopword)
+	SELECT $1, * FROM unnest($2::text[], $3::int[], $4::bool[])`
+	_, err = tx.Exec(query, musicId, pq.Array(words), pq.Array(counts), pq.Array(stopwords))
+	return err
+}
+
+// SetStats caches the statistics of a song computed after it was stored.
+func (r *Music) SetStats(musicId int, stats models.LyricStats) error {
+	const op = "storage.music.SetStats"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = r.replaceStats(tx, musicId, &stats); err != nil {
+		_ = tx.Rollback()
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetStats returns the cached statistics of a song with its top most
+// frequent words that are not stopwords.
+func (r *Music) GetStats(musicId, top int) (models.LyricStats, error) {
+	const op = "storage.music.GetStats"
+	query := `SELECT 1 AS songs, s.words, s.lines, s.repeated_lines, s.line_chars,
+	       (SELECT count(*) FROM lyric_words w WHERE w.music_id = s.music_id) AS unique_words
+	FROM lyric_stats s
+	WHERE s.music_id = $1`
+
+	var stats models.LyricStats
+	if err := r.db.Get(&stats, query, musicId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.LyricStats{}, fmt.Errorf("%s: %w", op, ErrStatsNotFound)
+		}
+		return models.LyricStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `SELECT word, count, stopword FROM lyric_words
+	WHERE music_id = $1 AND NOT stopword
+	ORDER BY count DESC, word
+	LIMIT $2`
+	if err := r.db.Select(&stats.Counts, query, musicId, top); err != nil {
+		return models.LyricStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return stats, nil
+}
+
+// GetGroupStats sums the cached statistics of the songs of a group. Songs
+// without cached statistics are left out; Songs tells how many were counted.
+func (r *Music) GetGroupStats(group string, top int) (models.LyricStats, error) {
+	const op = "storage.music.GetGroupStats"
+
+	var groupId int
+	err := r.db.Get(&groupId, `SELECT id FROM groups WHERE id = group_id_by_name($1)`, group)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.LyricStats{}, fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+		return models.LyricStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT count(*) AS songs,
+	       COALESCE(sum(s.words), 0) AS words,
+	       COALESCE(sum(s.lines), 0) AS lines,
+	       COALESCE(sum(s.repeated_lines), 0) AS repeated_lines,
+	       COALESCE(sum(s.line_chars), 0) AS line_chars,
+	       (SELECT count(DISTINCT w.word)
+	        FROM lyric_words w
+	        JOIN music_groups mg ON mg.music_id = w.music_id
+	        WHERE mg.group_id = $1) AS unique_words
+	FROM lyric_stats s
+	JOIN music_groups mg ON mg.music_id = s.music_id
+	WHERE mg.group_id = $1`
+
+	var stats models.LyricStats
+	if err = r.db.Get(&stats, query, groupId); err != nil {
+		return models.LyricStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// A word is a stopword of the group when it is one in every song it
+	// appears in, which only differs across songs in different languages.
+	query = `SELECT w.word, sum(w.count) AS count, bool_and(w.stopword) AS stopword
+	FROM lyric_words w
+	JOIN music_groups mg ON mg.music_id = w.music_id
+	WHERE mg.group_id = $1
+	GROUP BY w.word
+	HAVING NOT bool_and(w.stopword)
+	ORDER BY count DESC, w.word
+	LIMIT $2`
+	if err = r.db.Select(&stats.Counts, query, groupId, top); err != nil {
+		return models.LyricStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return stats, nil
+}
+
+// GetUnanalyzed returns the lyrics of the songs without cached statistics,
+// which were added before statistics were cached.
+func (r *Music) GetUnanalyzed() ([]models.Lyrics, error) {
+	const op = "storage.music.GetUnanalyzed"
+	query := `SELECT m.id, m.text_song, COALESCE(m.lang, '')
+	FROM music m
+	WHERE NOT EXISTS (SELECT 1 FROM lyric_stats s WHERE s.music_id = m.id)`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []models.Lyrics
+	for rows.Next() {
+		var lyrics models.Lyrics
+		if err = rows.Scan(&lyrics.MusicId, &lyrics.Text, &lyrics.Lang); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		res = append(res, lyrics)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package musicrepo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// SetLanguage sets the language of the original text by hand, with the
+// statistics computed for it; an empty lang clears it and leaves the
+// language to detection again.
+func (r *Music) SetLanguage(id int, lang string, stats models.LyricStats) error {
+	const op = "storage.music.SetLanguage"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.Exec(`UPDATE music SET lang = NULLIF($1, ''), lang_manual = $1 <> '' WHERE id = $2`, lang, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		err = ErrMusicNotFound
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = r.replaceStats(tx, id, &stats); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SetTranslation adds a translation or replaces the one in the same language.
+func (r *Music) SetTranslation(musicId int, translation models.Translation) error {
+	const op = "storage.music.SetTranslation"
+	query := `INSERT INTO lyric_translations (music_id, lang, text) VALUES ($1, $2, $3)
+	ON CONFLICT (music_id, lang) DO UPDATE SET text = EXCLUDED.text;`
+
+	_, err := r.db.Exec(query, musicId, translation.Lang, translation.Text)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Music) DeleteTranslation(musicId int, lang string) error {
+	const op = "storage.music.DeleteTranslation"
+	res, err := r.db.Exec(`DELETE FROM lyric_translations WHERE music_id = $1 AND lang = $2`, musicId, lang)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrTranslationNotFound)
+	}
+	return nil
+}
+
+func (r *Music) GetTranslations(musicId int) ([]models.Translation, error) {
+	const op = "storage.music.GetTranslations"
+
+	var translations []models.Translation
+	query := `SELECT lang, text FROM lyric_translations WHERE music_id = $1 ORDER BY lang`
+	if err := r.db.Select(&translations, query, musicId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return translations, nil
+}
@@ -0,0 +1,224 @@
+package personrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+	"strings"
+)
+
+var (
+	ErrPersonNotFound        = errors.New("person not found")
+	ErrPersonAlreadyExists   = errors.New("person already exists")
+	ErrMusicNotFound         = errors.New("music not found")
+	ErrGroupNotFound         = errors.New("group not found")
+	ErrCreditNotFound        = errors.New("credit not found")
+	ErrCreditAlreadyExists   = errors.New("credit already exists")
+	ErrMembershipNotFound    = errors.New("membership not found")
+	ErrInvalidMembershipDate = errors.New("membership ends before it starts")
+)
+
+type Person struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Person {
+	return &Person{
+		db: db,
+	}
+}
+
+func (r *Person) Add(name string) (int, error) {
+	const op = "storage.person.Add"
+	query := `INSERT INTO people (name) VALUES ($1) RETURNING id;`
+
+	var id int
+	if err := r.db.QueryRow(query, name).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, translateErr(err))
+	}
+	return id, nil
+}
+
+func (r *Person) Delete(id int) error {
+	const op = "storage.person.Delete"
+	res, err := r.db.Exec(`DELETE FROM people WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+	}
+	return nil
+}
+
+func (r *Person) Get(id int) (models.Person, error) {
+	const op = "storage.person.Get"
+
+	var person models.Person
+	if err := r.db.Get(&person, `SELECT id, name FROM people WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Person{}, fmt.Errorf("%s: %w", op, ErrPersonNotFound)
+		}
+		return models.Person{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return person, nil
+}
+
+func (r *Person) AddCredit(musicId, personId int, role string) error {
+	const op = "storage.person.AddCredit"
+	query := `INSERT INTO music_credits (music_id, person_id, role) VALUES ($1, $2, $3);`
+
+	if _, err := r.db.Exec(query, musicId, personId, role); err != nil {
+		return fmt.Errorf("%s: %w", op, translateErr(err))
+	}
+	return nil
+}
+
+func (r *Person) RemoveCredit(musicId, personId int, role string) error {
+	const op = "storage.person.RemoveCredit"
+	query := `DELETE FROM music_credits WHERE music_id = $1 AND person_id = $2 AND role = $3`
+
+	res, err := r.db.Exec(query, musicId, personId, role)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrCreditNotFound)
+	}
+	return nil
+}
+
+// GetCredits returns the credits of a song with musicId set, or of a
+// person with personId set.
+func (r *Person) GetCredits(musicId, personId int) ([]models.Credit, error) {
+	const op = "storage.person.GetCredits"
+	query := `SELECT c.music_id, m.song, COALESCE(g.name, '') AS "group", c.person_id, p.name AS person, c.role
+	FROM music_credits c
+	JOIN music m ON m.id = c.music_id
+	JOIN people p ON p.id = c.person_id
+	LEFT JOIN music_groups mg ON mg.music_id = m.id
+	LEFT JOIN groups g ON g.id = mg.group_id
+	WHERE ($1 = 0 OR c.music_id = $1) AND ($2 = 0 OR c.person_id = $2)
+	ORDER BY m.song, c.music_id, p.name, c.role`
+
+	credits := []models.Credit{}
+	if err := r.db.Select(&credits, query, musicId, personId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(credits) == 0 && musicId != 0 {
+		var exists bool
+		if err := r.db.Get(&exists, `SELECT EXISTS (SELECT 1 FROM music WHERE id = $1)`, musicId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+		}
+	}
+	return credits, nil
+}
+
+func (r *Person) AddMember(membership models.Membership) (int, error) {
+	const op = "storage.person.AddMember"
+	query := `INSERT INTO group_members (group_id, person_id, joined_on, left_on) VALUES ($1, $2, $3, $4) RETURNING id;`
+
+	var id int
+	err := r.db.QueryRow(query, membership.GroupId, membership.PersonId, membership.From, membership.To).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, translateErr(err))
+	}
+	return id, nil
+}
+
+func (r *Person) RemoveMember(id int) error {
+	const op = "storage.person.RemoveMember"
+	res, err := r.db.Exec(`DELETE FROM group_members WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrMembershipNotFound)
+	}
+	return nil
+}
+
+// GetMembers returns the memberships of a group with groupId set, or of
+// a person with personId set. A year other than 0 keeps the memberships
+// overlapping it, open bounds included.
+func (r *Person) GetMembers(groupId, personId, year int) ([]models.Membership, error) {
+	const op = "storage.person.GetMembers"
+	query := `SELECT gm.id, gm.group_id, g.name AS "group", gm.person_id, p.name AS person, gm.joined_on, gm.left_on
+	FROM group_members gm
+	JOIN groups g ON g.id = gm.group_id
+	JOIN people p ON p.id = gm.person_id
+	WHERE ($1 = 0 OR gm.group_id = $1) AND ($2 = 0 OR gm.person_id = $2)
+	  AND ($3 = 0 OR ((gm.joined_on IS NULL OR gm.joined_on <= make_date($3, 12, 31))
+	              AND (gm.left_on IS NULL OR gm.left_on >= make_date($3, 1, 1))))
+	ORDER BY gm.joined_on NULLS FIRST, p.name, gm.id`
+
+	var members []models.Membership
+	if err := r.db.Select(&members, query, groupId, personId, year); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(members) == 0 && groupId != 0 {
+		var exists bool
+		if err := r.db.Get(&exists, `SELECT EXISTS (SELECT 1 FROM groups WHERE id = $1)`, groupId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("%s: %w", op, ErrGroupNotFound)
+		}
+	}
+	return members, nil
+}
+
+// translateErr tells apart the foreign keys by the column in their
+// default constraint names, such as music_credits_person_id_fkey.
+func translateErr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			if pqErr.Table == "people" {
+				return ErrPersonAlreadyExists
+			}
+			return ErrCreditAlreadyExists
+		case "23503":
+			switch {
+			case strings.Contains(pqErr.Constraint, "person_id"):
+				return ErrPersonNotFound
+			case strings.Contains(pqErr.Constraint, "group_id"):
+				return ErrGroupNotFound
+			default:
+				return ErrMusicNotFound
+			}
+		case "23514":
+			return ErrInvalidMembershipDate
+		}
+	}
+	return err
+}
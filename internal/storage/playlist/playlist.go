@@ -0,0 +1,378 @@
+package playlistrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrPlaylistNotFound = errors.New("playlist not found")
+	ErrMusicNotFound    = errors.New("music not found")
+	ErrInvalidPosition  = errors.New("invalid position")
+)
+
+type Playlist struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Playlist {
+	return &Playlist{
+		db: db,
+	}
+}
+
+func (r *Playlist) Create(userId int, name string) (int, error) {
+	const op = "storage.playlist.Create"
+	query := `INSERT INTO playlists (user_id, name) VALUES ($1, $2) RETURNING id;`
+
+	var id int
+	if err := r.db.QueryRow(query, userId, name).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+func (r *Playlist) GetOwner(id int) (int, error) {
+	const op = "storage.playlist.GetOwner"
+	query := `SELECT user_id FROM playlists WHERE id = $1`
+
+	var userId int
+	if err := r.db.Get(&userId, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, ErrPlaylistNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return userId, nil
+}
+
+func (r *Playlist) Get(id int) (models.Playlist, error) {
+	const op = "storage.playlist.Get"
+	playlist, err := r.get(`p.id = $1`, id)
+	if err != nil {
+		return models.Playlist{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return playlist, nil
+}
+
+func (r *Playlist) GetByToken(token string) (models.Playlist, error) {
+	const op = "storage.playlist.GetByToken"
+	playlist, err := r.get(`p.public_token = $1`, token)
+	if err != nil {
+		return models.Playlist{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return playlist, nil
+}
+
+func (r *Playlist) get(condition string, arg interface{}) (models.Playlist, error) {
+	query := `SELECT p.id, p.user_id, p.name, p.public_token, p.created_at, p.updated_at,
+       (SELECT COUNT(*) FROM playlist_tracks t WHERE t.playlist_id = p.id) AS track_count
+       FROM playlists p
+       WHERE ` + condition
+
+	var playlist models.Playlist
+	if err := r.db.Get(&playlist, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Playlist{}, ErrPlaylistNotFound
+		}
+		return models.Playlist{}, err
+	}
+
+	tracksQuery := `SELECT position, music_id, song, group_name, added_at
+	FROM playlist_tracks
+	WHERE playlist_id = $1
+	ORDER BY position`
+
+	if err := r.db.Select(&playlist.Tracks, tracksQuery, playlist.Id); err != nil {
+		return models.Playlist{}, err
+	}
+	return playlist, nil
+}
+
+func (r *Playlist) GetAllByUser(userId int) ([]models.Playlist, error) {
+	const op = "storage.playlist.GetAllByUser"
+	query := `SELECT p.id, p.user_id, p.name, p.public_token, p.created_at, p.updated_at,
+       COUNT(t.position) AS track_count
+       FROM playlists p
+       LEFT JOIN playlist_tracks t ON t.playlist_id = p.id
+       WHERE p.user_id = $1
+       GROUP BY p.id
+       ORDER BY p.updated_at DESC, p.id`
+
+	var playlists []models.Playlist
+	if err := r.db.Select(&playlists, query, userId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(playlists) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrPlaylistNotFound)
+	}
+	return playlists, nil
+}
+
+func (r *Playlist) Rename(id int, name string) error {
+	const op = "storage.playlist.Rename"
+	query := `UPDATE playlists SET name = $1, updated_at = now() WHERE id = $2`
+	if err := r.execOne(query, name, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Playlist) SetToken(id int, token *string) error {
+	const op = "storage.playlist.SetToken"
+	query := `UPDATE playlists SET public_token = $1, updated_at = now() WHERE id = $2`
+	if err := r.execOne(query, token, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Playlist) Delete(id int) error {
+	const op = "storage.playlist.Delete"
+	query := `DELETE FROM playlists WHERE id = $1`
+	if err := r.execOne(query, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Playlist) execOne(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrPlaylistNotFound
+	}
+	return nil
+}
+
+// AddTrack inserts a song at position, shifting the following tracks down.
+// A zero position appends the song to the end of the playlist.
+func (r *Playlist) AddTrack(id, musicId, position int) (int, error) {
+	const op = "storage.playlist.AddTrack"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	count, err := lockTracks(tx, id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if position == 0 {
+		position = count + 1
+	}
+
+	if position < 1 || position > count+1 {
+		err = ErrInvalidPosition
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shift := `UPDATE playlist_tracks SET position = position + 1 WHERE playlist_id = $1 AND position >= $2`
+	if _, err = tx.Exec(shift, id, position); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	insert := `INSERT INTO playlist_tracks (playlist_id, position, music_id, song, group_name)
+	SELECT $1, $2, m.id, m.song, COALESCE(g.name, '')
+	FROM music m
+	LEFT JOIN music_groups mg ON mg.music_id = m.id
+	LEFT JOIN groups g ON g.id = mg.group_id
+	WHERE m.id = $3
+	LIMIT 1`
+
+	res, err := tx.Exec(insert, id, position, musicId)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		err = ErrMusicNotFound
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = touch(tx, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return position, nil
+}
+
+// RemoveTrack deletes the track at position and closes the gap it leaves.
+func (r *Playlist) RemoveTrack(id, position int) error {
+	const op = "storage.playlist.RemoveTrack"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	count, err := lockTracks(tx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if position < 1 || position > count {
+		err = ErrInvalidPosition
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `DELETE FROM playlist_tracks WHERE playlist_id = $1 AND position = $2`
+	if _, err = tx.Exec(query, id, position); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	shift := `UPDATE playlist_tracks SET position = position - 1 WHERE playlist_id = $1 AND position > $2`
+	if _, err = tx.Exec(shift, id, position); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = touch(tx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// MoveTrack moves the track at from to position to, shifting the tracks in between.
+func (r *Playlist) MoveTrack(id, from, to int) error {
+	const op = "storage.playlist.MoveTrack"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	count, err := lockTracks(tx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if from < 1 || from > count || to < 1 || to > count {
+		err = ErrInvalidPosition
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `UPDATE playlist_tracks SET position = CASE
+		WHEN position = $2 THEN $3
+		WHEN $2 < $3 AND position > $2 AND position <= $3 THEN position - 1
+		WHEN $2 > $3 AND position >= $3 AND position < $2 THEN position + 1
+		ELSE position END
+	WHERE playlist_id = $1`
+
+	if _, err = tx.Exec(query, id, from, to); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = touch(tx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Duplicate copies the playlist with all its tracks to userId under a new name.
+func (r *Playlist) Duplicate(id, userId int, name string) (int, error) {
+	const op = "storage.playlist.Duplicate"
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = lockTracks(tx, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var newId int
+	query := `INSERT INTO playlists (user_id, name) VALUES ($1, $2) RETURNING id;`
+	if err = tx.QueryRow(query, userId, name).Scan(&newId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	copyTracks := `INSERT INTO playlist_tracks (playlist_id, position, music_id, song, group_name, added_at)
+	SELECT $1, position, music_id, song, group_name, added_at
+	FROM playlist_tracks
+	WHERE playlist_id = $2`
+
+	if _, err = tx.Exec(copyTracks, newId, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return newId, nil
+}
+
+// lockTracks locks the playlist row against concurrent track changes
+// and returns the current number of tracks.
+func lockTracks(tx *sqlx.Tx, id int) (int, error) {
+	var locked int
+	query := `SELECT id FROM playlists WHERE id = $1 FOR UPDATE`
+	if err := tx.Get(&locked, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrPlaylistNotFound
+		}
+		return 0, err
+	}
+
+	var count int
+	countQuery := `SELECT COUNT(*) FROM playlist_tracks WHERE playlist_id = $1`
+	if err := tx.Get(&count, countQuery, id); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func touch(tx *sqlx.Tx, id int) error {
+	_, err := tx.Exec(`UPDATE playlists SET updated_at = now() WHERE id = $1`, id)
+	return err
+}
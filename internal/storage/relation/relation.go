@@ -0,0 +1,114 @@
+package relationrepo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrMusicNotFound         = errors.New("music not found")
+	ErrRelationNotFound      = errors.New("relation not found")
+	ErrRelationAlreadyExists = errors.New("relation already exists")
+	ErrSelfRelation          = errors.New("song cannot be related to itself")
+)
+
+type Relation struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Relation {
+	return &Relation{
+		db: db,
+	}
+}
+
+func (r *Relation) Add(relation models.Relation) error {
+	const op = "storage.relation.Add"
+	query := `INSERT INTO music_relations (music_id, related_id, type) VALUES ($1, $2, $3);`
+
+	if _, err := r.db.Exec(query, relation.MusicId, relation.RelatedId, relation.Type); err != nil {
+		return fmt.Errorf("%s: %w", op, translateErr(err))
+	}
+	return nil
+}
+
+func (r *Relation) Delete(relation models.Relation) error {
+	const op = "storage.relation.Delete"
+	query := `DELETE FROM music_relations WHERE music_id = $1 AND related_id = $2 AND type = $3`
+
+	res, err := r.db.Exec(query, relation.MusicId, relation.RelatedId, relation.Type)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, ErrRelationNotFound)
+	}
+	return nil
+}
+
+// GetGraph returns the songs reachable from musicId through at most depth
+// relations followed in either direction, and the relations between them.
+func (r *Relation) GetGraph(musicId, depth int) (models.RelationGraph, error) {
+	const op = "storage.relation.GetGraph"
+	query := `WITH RECURSIVE walk (id, depth) AS (
+		SELECT id, 0 FROM music WHERE id = $1
+		UNION
+		SELECT CASE WHEN r.music_id = w.id THEN r.related_id ELSE r.music_id END, w.depth + 1
+		FROM walk w
+		JOIN music_relations r ON w.id IN (r.music_id, r.related_id)
+		WHERE w.depth < $2
+	)
+	SELECT m.id, m.song, COALESCE(g.name, '') AS "group", n.depth
+	FROM (SELECT id, min(depth) AS depth FROM walk GROUP BY id) n
+	JOIN music m ON m.id = n.id
+	LEFT JOIN music_groups mg ON mg.music_id = m.id
+	LEFT JOIN groups g ON g.id = mg.group_id
+	ORDER BY n.depth, m.id`
+
+	var graph models.RelationGraph
+	if err := r.db.Select(&graph.Nodes, query, musicId, depth); err != nil {
+		return models.RelationGraph{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(graph.Nodes) == 0 {
+		return models.RelationGraph{}, fmt.Errorf("%s: %w", op, ErrMusicNotFound)
+	}
+
+	ids := make([]int64, len(graph.Nodes))
+	for i, n := range graph.Nodes {
+		ids[i] = int64(n.Id)
+	}
+
+	query = `SELECT music_id, related_id, type FROM music_relations
+	WHERE music_id = ANY($1) AND related_id = ANY($1)
+	ORDER BY music_id, related_id, type`
+	graph.Edges = []models.Relation{}
+	if err := r.db.Select(&graph.Edges, query, pq.Array(ids)); err != nil {
+		return models.RelationGraph{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return graph, nil
+}
+
+func translateErr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return ErrRelationAlreadyExists
+		case "23503":
+			return ErrMusicNotFound
+		case "23514":
+			return ErrSelfRelation
+		}
+	}
+	return err
+}
@@ -2,15 +2,48 @@ package storage
 
 import (
 	"github.com/jmoiron/sqlx"
+	"library-music/internal/storage/album"
+	"library-music/internal/storage/artwork"
+	"library-music/internal/storage/audio"
+	"library-music/internal/storage/genre"
+	"library-music/internal/storage/group"
+	"library-music/internal/storage/lyrics"
 	"library-music/internal/storage/music"
+	"library-music/internal/storage/person"
+	"library-music/internal/storage/playlist"
+	"library-music/internal/storage/relation"
+	"library-music/internal/storage/tag"
+	"library-music/internal/storage/user"
 )
 
 type Repository struct {
-	Music *musicrepo.Music
+	Music    *musicrepo.Music
+	User     *userrepo.User
+	Playlist *playlistrepo.Playlist
+	Album    *albumrepo.Album
+	Genre    *genrerepo.Genre
+	Tag      *tagrepo.Tag
+	Lyrics   *lyricsrepo.Lyrics
+	Relation *relationrepo.Relation
+	Person   *personrepo.Person
+	Group    *grouprepo.Group
+	Artwork  *artworkrepo.Artwork
+	Audio    *audiorepo.Audio
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
 	return &Repository{
-		Music: musicrepo.New(db),
+		Music:    musicrepo.New(db),
+		User:     userrepo.New(db),
+		Playlist: playlistrepo.New(db),
+		Album:    albumrepo.New(db),
+		Genre:    genrerepo.New(db),
+		Tag:      tagrepo.New(db),
+		Lyrics:   lyricsrepo.New(db),
+		Relation: relationrepo.New(db),
+		Person:   personrepo.New(db),
+		Group:    grouprepo.New(db),
+		Artwork:  artworkrepo.New(db),
+		Audio:    audiorepo.New(db),
 	}
 }
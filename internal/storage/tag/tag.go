@@ -0,0 +1,147 @@
+package tagrepo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+)
+
+var (
+	ErrTagNotFound   = errors.New("tag not found")
+	ErrMusicNotFound = errors.New("music not found")
+	ErrGroupNotFound = errors.New("group not found")
+)
+
+type Tag struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Tag {
+	return &Tag{
+		db: db,
+	}
+}
+
+func (r *Tag) AddToMusic(musicId int, names []string) error {
+	const op = "storage.tag.AddToMusic"
+	query := `INSERT INTO music_tags (music_id, tag_id) SELECT $1, id FROM tags WHERE name = ANY($2) ON CONFLICT DO NOTHING;`
+	if err := r.attach(query, musicId, names, ErrMusicNotFound); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Tag) AddToGroup(groupId int, names []string) error {
+	const op = "storage.tag.AddToGroup"
+	query := `INSERT INTO group_tags (group_id, tag_id) SELECT $1, id FROM tags WHERE name = ANY($2) ON CONFLICT DO NOTHING;`
+	if err := r.attach(query, groupId, names, ErrGroupNotFound); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// attach creates missing tags and links them to the owner with query,
+// reporting ownerNotFound when the owner row does not exist.
+func (r *Tag) attach(query string, ownerId int, names []string, ownerNotFound error) (err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	create := `INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT DO NOTHING;`
+	if _, err = tx.Exec(create, pq.Array(names)); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(query, ownerId, pq.Array(names)); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			err = ownerNotFound
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Tag) RemoveFromMusic(musicId int, name string) error {
+	const op = "storage.tag.RemoveFromMusic"
+	query := `DELETE FROM music_tags WHERE music_id = $1 AND tag_id = (SELECT id FROM tags WHERE name = $2)`
+	if err := r.detach(query, musicId, name); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *Tag) RemoveFromGroup(groupId int, name string) error {
+	const op = "storage.tag.RemoveFromGroup"
+	query := `DELETE FROM group_tags WHERE group_id = $1 AND tag_id = (SELECT id FROM tags WHERE name = $2)`
+	if err := r.detach(query, groupId, name); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// detach unlinks a tag and drops it once nothing carries it anymore.
+func (r *Tag) detach(query string, ownerId int, name string) (err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.Exec(query, ownerId, name)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		err = ErrTagNotFound
+		return err
+	}
+
+	cleanup := `DELETE FROM tags t WHERE t.name = $1
+		AND NOT EXISTS (SELECT 1 FROM music_tags WHERE tag_id = t.id)
+		AND NOT EXISTS (SELECT 1 FROM group_tags WHERE tag_id = t.id)`
+	if _, err = tx.Exec(cleanup, name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Tag) GetCounts(limit int) ([]models.TagCount, error) {
+	const op = "storage.tag.GetCounts"
+	query := `SELECT t.name,
+       (SELECT COUNT(*) FROM music_tags mt WHERE mt.tag_id = t.id) AS songs,
+       (SELECT COUNT(*) FROM group_tags gt WHERE gt.tag_id = t.id) AS groups
+       FROM tags t
+       ORDER BY songs DESC, groups DESC, t.name
+       LIMIT $1`
+
+	var counts []models.TagCount
+	if err := r.db.Select(&counts, query, limit); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(counts) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrTagNotFound)
+	}
+	return counts, nil
+}
@@ -0,0 +1,215 @@
+package userrepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"library-music/internal/domain/models"
+	"time"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrSessionNotFound   = errors.New("session not found")
+	ErrMusicNotFound     = errors.New("music not found")
+)
+
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+)
+
+type User struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) *User {
+	return &User{
+		db: db,
+	}
+}
+
+func (r *User) Create(user models.User) (int, error) {
+	const op = "storage.user.Create"
+	query := `INSERT INTO users (login, password_hash, role) VALUES ($1, $2, $3) RETURNING id;`
+
+	var id int
+	err := r.db.QueryRow(query, user.Login, user.PasswordHash, user.Role).Scan(&id)
+	if err != nil {
+		if isPqError(err, pqUniqueViolation) {
+			return 0, fmt.Errorf("%s: %w", op, ErrUserAlreadyExists)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+func (r *User) GetByLogin(login string) (models.User, error) {
+	const op = "storage.user.GetByLogin"
+	query := `SELECT id, login, password_hash, role, created_at FROM users WHERE login = $1`
+
+	var user models.User
+	err := r.db.Get(&user, query, login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (r *User) CreateSession(userId int, tokenHash []byte, expiresAt time.Time) error {
+	const op = "storage.user.CreateSession"
+	query := `INSERT INTO sessions (token_hash, user_id, expires_at) VALUES ($1, $2, $3);`
+
+	_, err := r.db.Exec(query, tokenHash, userId, expiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *User) GetSessionUser(tokenHash []byte) (models.User, error) {
+	const op = "storage.user.GetSessionUser"
+	query := `SELECT u.id, u.login, u.password_hash, u.role, u.created_at
+	FROM sessions s
+	JOIN users u ON u.id = s.user_id
+	WHERE s.token_hash = $1 AND s.expires_at > now()`
+
+	var user models.User
+	err := r.db.Get(&user, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, ErrSessionNotFound)
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (r *User) DeleteSession(tokenHash []byte) error {
+	const op = "storage.user.DeleteSession"
+	query := `DELETE FROM sessions WHERE token_hash = $1 OR expires_at <= now()`
+
+	_, err := r.db.Exec(query, tokenHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *User) AddFavorite(userId, musicId int) error {
+	const op = "storage.user.AddFavorite"
+	if err := r.addMusic("user_favorites", userId, musicId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *User) RemoveFavorite(userId, musicId int) error {
+	const op = "storage.user.RemoveFavorite"
+	if err := r.removeMusic("user_favorites", userId, musicId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *User) GetFavorites(userId, countSongs, page int) ([]models.Music, error) {
+	const op = "storage.user.GetFavorites"
+	musics, err := r.getMusic("user_favorites", userId, countSongs, page)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return musics, nil
+}
+
+func (r *User) AddToLibrary(userId, musicId int) error {
+	const op = "storage.user.AddToLibrary"
+	if err := r.addMusic("user_library", userId, musicId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *User) RemoveFromLibrary(userId, musicId int) error {
+	const op = "storage.user.RemoveFromLibrary"
+	if err := r.removeMusic("user_library", userId, musicId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *User) GetLibrary(userId, countSongs, page int) ([]models.Music, error) {
+	const op = "storage.user.GetLibrary"
+	musics, err := r.getMusic("user_library", userId, countSongs, page)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return musics, nil
+}
+
+// addMusic, removeMusic and getMusic share the logic of the per-user
+// collections; table is always one of the package's own table names.
+func (r *User) addMusic(table string, userId, musicId int) error {
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, music_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`, table)
+	_, err := r.db.Exec(query, userId, musicId)
+	if err != nil {
+		if isPqError(err, pqForeignKeyViolation) {
+			return ErrMusicNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *User) removeMusic(table string, userId, musicId int) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND music_id = $2`, table)
+	res, err := r.db.Exec(query, userId, musicId)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrMusicNotFound
+	}
+	return nil
+}
+
+func (r *User) getMusic(table string, userId, countSongs, page int) ([]models.Music, error) {
+	query := fmt.Sprintf(`SELECT m.id, m.song, m.text_song, m.link, m.release_date,
+       COALESCE(m.lang, '') AS lang, m.explicit,
+       g.id AS "group.id",
+       g.name AS "group.name"
+       FROM %s c
+       JOIN music m ON m.id = c.music_id
+       LEFT JOIN music_groups mg ON mg.music_id = m.id
+       LEFT JOIN groups g ON g.id = mg.group_id
+       WHERE c.user_id = $1
+       ORDER BY c.added_at DESC, m.id
+       LIMIT $2 OFFSET $3`, table)
+
+	var musics []models.Music
+	err := r.db.Select(&musics, query, userId, countSongs, (page-1)*countSongs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(musics) == 0 {
+		return nil, ErrMusicNotFound
+	}
+	return musics, nil
+}
+
+func isPqError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
@@ -0,0 +1,58 @@
+// Package audiofmt recognizes audio files by their first bytes, so the
+// type of an upload does not depend on what the client claims.
+package audiofmt
+
+import (
+	"bytes"
+)
+
+// SniffLen is the number of leading bytes Sniff needs at most.
+const SniffLen = 12
+
+// Format is a recognized audio format.
+type Format struct {
+	ContentType string
+	Ext         string
+}
+
+var (
+	MP3  = Format{ContentType: "audio/mpeg", Ext: "mp3"}
+	AAC  = Format{ContentType: "audio/aac", Ext: "aac"}
+	M4A  = Format{ContentType: "audio/mp4", Ext: "m4a"}
+	FLAC = Format{ContentType: "audio/flac", Ext: "flac"}
+	Ogg  = Format{ContentType: "audio/ogg", Ext: "ogg"}
+	WAV  = Format{ContentType: "audio/wav", Ext: "wav"}
+)
+
+// m4aBrands are the ISO base media brands used for audio-only files.
+var m4aBrands = [][]byte{[]byte("M4A "), []byte("M4B "), []byte("mp42"), []byte("isom"), []byte("dash")}
+
+// Sniff returns the format of an audio file from its first bytes, or
+// false when they are not the start of a supported audio file.
+func Sniff(head []byte) (Format, bool) {
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")):
+		return MP3, true
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return FLAC, true
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return Ogg, true
+	case len(head) >= 12 && bytes.Equal(head[:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WAVE")):
+		return WAV, true
+	case len(head) >= 12 && bytes.Equal(head[4:8], []byte("ftyp")):
+		for _, brand := range m4aBrands {
+			if bytes.Equal(head[8:12], brand) {
+				return M4A, true
+			}
+		}
+	case len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0:
+		// An MPEG frame sync; ADTS frames carry layer 0, MP3 frames layer 3.
+		if head[1]&0x06 == 0 {
+			return AAC, true
+		}
+		if head[1]&0x06 == 0x02 {
+			return MP3, true
+		}
+	}
+	return Format{}, false
+}
@@ -0,0 +1,102 @@
+// Package audiotag reads the metadata embedded in audio files: ID3v2
+// tags of MP3 files and the Vorbis comments of FLAC and Ogg files. Only
+// the start of a file is read, as that is where these tags are kept.
+package audiotag
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoTags      = errors.New("file has no supported tags")
+	ErrInvalidTags = errors.New("tags are malformed")
+)
+
+// maxTagSize bounds the tags read, embedded pictures included.
+const maxTagSize = 16 << 20
+
+// Tags are the fields read from a file; missing fields are left zero.
+type Tags struct {
+	Title  string
+	Artist string
+	Album  string
+	// Date is the release date; with only a year known, DateIsYear is set
+	// and Date falls on the first of January.
+	Date        time.Time
+	DateIsYear  bool
+	TrackNumber int
+	Lyrics      string
+	Picture     *Picture
+}
+
+// Picture is an embedded image, preferably the front cover.
+type Picture struct {
+	MIMEType string
+	Data     []byte
+}
+
+// Read reads the tags at the start of r.
+func Read(r io.Reader) (Tags, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")):
+		return readID3(br)
+	case bytes.Equal(head, []byte("fLaC")):
+		return readFLAC(br)
+	case bytes.Equal(head, []byte("OggS")):
+		return readOgg(br)
+	default:
+		return Tags{}, ErrNoTags
+	}
+}
+
+// setDate parses a date given as YYYY, YYYY-MM or YYYY-MM-DD, possibly
+// followed by a time, keeping the first date seen.
+func (t *Tags) setDate(value string) {
+	if !t.Date.IsZero() {
+		return
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) >= 10 {
+		if date, err := time.Parse("2006-01-02", value[:10]); err == nil {
+			t.Date = date
+			return
+		}
+	}
+	if len(value) >= 4 {
+		if year, err := strconv.Atoi(value[:4]); err == nil && year > 0 {
+			t.Date = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+			t.DateIsYear = true
+		}
+	}
+}
+
+// setTrack parses a track number given alone or as "3/12".
+func (t *Tags) setTrack(value string) {
+	value, _, _ = strings.Cut(strings.TrimSpace(value), "/")
+	if n, err := strconv.Atoi(value); err == nil && n > 0 {
+		t.TrackNumber = n
+	}
+}
+
+// setPicture keeps the front cover over any other picture.
+func (t *Tags) setPicture(mimeType string, kind byte, data []byte) {
+	const frontCover = 3
+	if len(data) == 0 || (t.Picture != nil && kind != frontCover) {
+		return
+	}
+	t.Picture = &Picture{MIMEType: mimeType, Data: data}
+}
+
+func (t *Tags) empty() bool {
+	return t.Title == "" && t.Artist == "" && t.Album == "" && t.Date.IsZero() &&
+		t.TrackNumber == 0 && t.Lyrics == "" && t.Picture == nil
+}
@@ -0,0 +1,553 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// Fixtures are built here byte by byte, as the formats lay them out.
+
+func syncsafeBytes(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+// id3Frame encodes a frame, its size syncsafe in ID3v2.4 and plain in v2.3.
+func id3Frame(version byte, id string, flags byte, data []byte) []byte {
+	frame := []byte(id)
+	if version == 4 {
+		frame = append(frame, syncsafeBytes(len(data))...)
+	} else {
+		frame = binary.BigEndian.AppendUint32(frame, uint32(len(data)))
+	}
+	frame = append(frame, 0, flags)
+	return append(frame, data...)
+}
+
+func id3Tag(version, flags byte, body []byte) []byte {
+	tag := append([]byte("ID3"), version, 0, flags)
+	tag = append(tag, syncsafeBytes(len(body))...)
+	return append(tag, body...)
+}
+
+func latin1(text string) []byte {
+	return append([]byte{0}, text...)
+}
+
+// utf16BOM encodes text as UTF-16 with a byte order mark.
+func utf16BOM(text string, order binary.AppendByteOrder) []byte {
+	data := []byte{1}
+	if order == binary.LittleEndian {
+		data = append(data, 0xFF, 0xFE)
+	} else {
+		data = append(data, 0xFE, 0xFF)
+	}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = order.AppendUint16(data, unit)
+	}
+	return data
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// unsynchronise inserts a zero byte after every 0xFF, as the
+// unsynchronisation scheme does.
+func unsynchronise(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte{0xFF}, []byte{0xFF, 0x00})
+}
+
+// picture is image data holding 0xFF bytes, which unsynchronisation changes.
+var picture = []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'}
+
+func apic(enc byte, mimeType string, kind byte, description []byte, data []byte) []byte {
+	frame := append([]byte{enc}, mimeType...)
+	frame = append(frame, 0, kind)
+	frame = append(frame, description...)
+	return append(frame, data...)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestReadID3(t *testing.T) {
+	uslt := func(enc byte, description, lyrics []byte) []byte {
+		return concat([]byte{enc}, []byte("rus"), description, lyrics)
+	}
+
+	v23 := concat(
+		id3Frame(3, "TIT2", 0, latin1("Caf\xe9")),
+		id3Frame(3, "TPE1", 0, latin1("Kino\x00Other")),
+		id3Frame(3, "TALB", 0, utf16BOM("Черный альбом", binary.LittleEndian)),
+		id3Frame(3, "TYER", 0, latin1("1990")),
+		id3Frame(3, "TRCK", 0, latin1("3/10")),
+		id3Frame(3, "USLT", 0, uslt(1, []byte{0xFF, 0xFE, 0, 0}, utf16BOM("Песен, ещё не написанных", binary.LittleEndian)[1:])),
+		id3Frame(3, "APIC", 0, apic(0, "image/png", 0, []byte("back\x00"), []byte("png"))),
+		id3Frame(3, "APIC", 0, apic(0, "image/jpeg", 3, []byte("front\x00"), picture)),
+	)
+
+	v24 := concat(
+		id3Frame(4, "TIT2", 0, append([]byte{3}, "Кукушка"...)),
+		id3Frame(4, "TPE1", 0, utf16BOM("Кино", binary.BigEndian)),
+		id3Frame(4, "TDRC", 0, append([]byte{3}, "1990-06-21T10:00"...)),
+		id3Frame(4, "USLT", 0, uslt(3, []byte("\x00"), []byte("line one\nline two"))),
+		// Frame-level unsynchronisation, with a data length indicator.
+		id3Frame(4, "APIC", 0x02|0x01, concat(syncsafeBytes(len(picture)+14), unsynchronise(apic(0, "image/jpeg", 3, []byte{0}, picture)))),
+	)
+
+	// A large frame gets a size above 127, where syncsafe and plain differ.
+	long := strings.Repeat("la ", 100)
+
+	tests := []struct {
+		name string
+		data []byte
+		want Tags
+	}{
+		{
+			name: "v2.3",
+			data: id3Tag(3, 0, v23),
+			want: Tags{
+				Title:       "Café",
+				Artist:      "Kino",
+				Album:       "Черный альбом",
+				Date:        date(1990, time.January, 1),
+				DateIsYear:  true,
+				TrackNumber: 3,
+				Lyrics:      "Песен, ещё не написанных",
+				Picture:     &Picture{MIMEType: "image/jpeg", Data: picture},
+			},
+		},
+		{
+			name: "v2.4",
+			data: id3Tag(4, 0, v24),
+			want: Tags{
+				Title:   "Кукушка",
+				Artist:  "Кино",
+				Date:    date(1990, time.June, 21),
+				Lyrics:  "line one\nline two",
+				Picture: &Picture{MIMEType: "image/jpeg", Data: picture},
+			},
+		},
+		{
+			name: "v2.4 syncsafe frame size",
+			data: id3Tag(4, 0, concat(id3Frame(4, "TIT2", 0, latin1("Song")), id3Frame(4, "USLT", 0, uslt(0, []byte{0}, []byte(long))))),
+			want: Tags{Title: "Song", Lyrics: long},
+		},
+		{
+			name: "v2.3 plain frame size",
+			data: id3Tag(3, 0, concat(id3Frame(3, "USLT", 0, uslt(0, []byte{0}, []byte(long))), id3Frame(3, "TIT2", 0, latin1("Song")))),
+			want: Tags{Title: "Song", Lyrics: long},
+		},
+		{
+			name: "v2.3 unsynchronised tag",
+			data: id3Tag(3, 0x80, unsynchronise(concat(
+				id3Frame(3, "TIT2", 0, utf16BOM("ÿ song", binary.LittleEndian)),
+				id3Frame(3, "APIC", 0, apic(0, "image/jpeg", 3, []byte{0}, picture)),
+			))),
+			want: Tags{Title: "ÿ song", Picture: &Picture{MIMEType: "image/jpeg", Data: picture}},
+		},
+		{
+			name: "v2.3 extended header",
+			data: id3Tag(3, 0x40, concat(
+				[]byte{0, 0, 0, 6, 0, 0, 0, 0, 0, 0},
+				id3Frame(3, "TIT2", 0, latin1("Song")),
+			)),
+			want: Tags{Title: "Song"},
+		},
+		{
+			name: "v2.4 extended header",
+			data: id3Tag(4, 0x40, concat(
+				syncsafeBytes(6), []byte{1, 0},
+				id3Frame(4, "TIT2", 0, latin1("Song")),
+			)),
+			want: Tags{Title: "Song"},
+		},
+		{
+			name: "padding",
+			data: id3Tag(3, 0, concat(id3Frame(3, "TIT2", 0, latin1("Song")), make([]byte, 64))),
+			want: Tags{Title: "Song"},
+		},
+		{
+			name: "compressed and encrypted frames skipped",
+			data: id3Tag(4, 0, concat(
+				id3Frame(4, "TIT2", 0x08, latin1("Compressed")),
+				id3Frame(4, "TPE1", 0x04, latin1("Encrypted")),
+				id3Frame(4, "TALB", 0, latin1("Album")),
+			)),
+			want: Tags{Album: "Album"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Audio follows the tag and is not read.
+			got, err := Read(bytes.NewReader(concat(tt.data, []byte{0xFF, 0xFB, 0x90, 0x00})))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			assertTags(t, got, tt.want)
+		})
+	}
+}
+
+func TestReadID3Errors(t *testing.T) {
+	valid := id3Tag(3, 0, id3Frame(3, "TIT2", 0, latin1("Song")))
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "truncated header", data: valid[:7], err: ErrInvalidTags},
+		{name: "truncated body", data: valid[:len(valid)-2], err: ErrInvalidTags},
+		{name: "v2.2", data: id3Tag(2, 0, []byte("TT2\x00\x00\x05\x00Song")), err: ErrNoTags},
+		{name: "frame larger than the tag", data: id3Tag(3, 0, concat([]byte("TIT2"), []byte{0, 0, 1, 0, 0, 0}, latin1("Song"))), err: ErrInvalidTags},
+		{name: "negative frame size", data: id3Tag(3, 0, concat([]byte("TIT2"), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0}, latin1("Song"))), err: ErrInvalidTags},
+		{name: "extended header too short", data: id3Tag(3, 0x40, []byte{0, 0}), err: ErrInvalidTags},
+		{name: "extended header past the tag", data: id3Tag(4, 0x40, concat(syncsafeBytes(100), id3Frame(4, "TIT2", 0, latin1("Song")))), err: ErrInvalidTags},
+		{name: "tag over the size limit", data: concat([]byte("ID3\x04\x00\x00"), []byte{0x7F, 0x7F, 0x7F, 0x7F}), err: ErrInvalidTags},
+		{name: "only padding", data: id3Tag(4, 0, make([]byte, 32)), err: ErrNoTags},
+		{
+			name: "empty and short frames",
+			data: id3Tag(3, 0, concat(
+				id3Frame(3, "TIT2", 0, nil),
+				id3Frame(3, "USLT", 0, []byte{0, 'e'}),
+				id3Frame(3, "APIC", 0, []byte{0, 'i', 'm'}),
+			)),
+			err: ErrNoTags,
+		},
+		{name: "data length without data", data: id3Tag(4, 0, id3Frame(4, "TIT2", 0x01, []byte{0, 0})), err: ErrNoTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.data)); !errors.Is(err, tt.err) {
+				t.Errorf("Read() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  byte
+		data []byte
+		want string
+	}{
+		{name: "latin-1", enc: 0, data: []byte("Caf\xe9"), want: "Café"},
+		{name: "utf-16 little endian", enc: 1, data: utf16BOM("Кино", binary.LittleEndian)[1:], want: "Кино"},
+		{name: "utf-16 big endian", enc: 1, data: utf16BOM("Кино", binary.BigEndian)[1:], want: "Кино"},
+		{name: "utf-16 surrogate pair", enc: 1, data: utf16BOM("𝄞", binary.LittleEndian)[1:], want: "𝄞"},
+		{name: "utf-16be", enc: 2, data: []byte{0x04, 0x1A}, want: "К"},
+		{name: "utf-16 odd length", enc: 1, data: []byte{0xFF, 0xFE, 'a', 0, 'b'}, want: "a"},
+		{name: "utf-8", enc: 3, data: []byte("Кино"), want: "Кино"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeText(tt.enc, tt.data); got != tt.want {
+				t.Errorf("decodeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func vorbisComments(vendor string, comments ...string) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(vendor)))
+	data = append(data, vendor...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(comments)))
+	for _, comment := range comments {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(comment)))
+		data = append(data, comment...)
+	}
+	return data
+}
+
+func flacPicture(kind uint32, mimeType string, data []byte) []byte {
+	block := binary.BigEndian.AppendUint32(nil, kind)
+	block = binary.BigEndian.AppendUint32(block, uint32(len(mimeType)))
+	block = append(block, mimeType...)
+	block = binary.BigEndian.AppendUint32(block, 0)
+	block = append(block, make([]byte, 16)...)
+	block = binary.BigEndian.AppendUint32(block, uint32(len(data)))
+	return append(block, data...)
+}
+
+func flacBlock(last bool, kind byte, data []byte) []byte {
+	if last {
+		kind |= 0x80
+	}
+	return append([]byte{kind, byte(len(data) >> 16), byte(len(data) >> 8), byte(len(data))}, data...)
+}
+
+func flac(blocks ...[]byte) []byte {
+	return concat(append([][]byte{[]byte("fLaC")}, blocks...)...)
+}
+
+func TestReadFLAC(t *testing.T) {
+	const streamInfo, padding, comment, pictureBlock = 0, 1, 4, 6
+	comments := vorbisComments("reference libFLAC 1.4.3",
+		"title=Кукушка",
+		"ARTIST=Кино",
+		"ARTIST=Виктор Цой",
+		"ALBUM=Черный альбом",
+		"DATE=1990-06-21",
+		"TRACKNUMBER=3/10",
+		"UNSYNCEDLYRICS=Песен, ещё не написанных",
+		"NOT A COMMENT",
+	)
+
+	tests := []struct {
+		name string
+		data []byte
+		want Tags
+	}{
+		{
+			name: "comments and pictures",
+			data: flac(
+				flacBlock(false, streamInfo, make([]byte, 34)),
+				flacBlock(false, comment, comments),
+				flacBlock(false, pictureBlock, flacPicture(4, "image/png", []byte("back"))),
+				flacBlock(false, pictureBlock, flacPicture(3, "image/jpeg", picture)),
+				flacBlock(true, padding, make([]byte, 128)),
+			),
+			want: Tags{
+				Title:       "Кукушка",
+				Artist:      "Кино",
+				Album:       "Черный альбом",
+				Date:        date(1990, time.June, 21),
+				TrackNumber: 3,
+				Lyrics:      "Песен, ещё не написанных",
+				Picture:     &Picture{MIMEType: "image/jpeg", Data: picture},
+			},
+		},
+		{
+			name: "picture only",
+			data: flac(flacBlock(true, pictureBlock, flacPicture(0, "image/png", []byte("png")))),
+			want: Tags{Picture: &Picture{MIMEType: "image/png", Data: []byte("png")}},
+		},
+		{
+			name: "malformed picture ignored",
+			data: flac(
+				flacBlock(false, comment, vorbisComments("", "TITLE=Song")),
+				flacBlock(true, pictureBlock, flacPicture(3, "image/png", []byte("png"))[:20]),
+			),
+			want: Tags{Title: "Song"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			assertTags(t, got, tt.want)
+		})
+	}
+}
+
+func TestReadFLACErrors(t *testing.T) {
+	const streamInfo, comment = 0, 4
+	valid := flac(flacBlock(true, comment, vorbisComments("vendor", "TITLE=Song")))
+	tooMany := vorbisComments("vendor", "TITLE=Song")
+	binary.LittleEndian.PutUint32(tooMany[10:], 5)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "no blocks", data: []byte("fLaC"), err: ErrInvalidTags},
+		{name: "truncated block header", data: valid[:6], err: ErrInvalidTags},
+		{name: "truncated block", data: valid[:len(valid)-3], err: ErrInvalidTags},
+		{name: "no last block", data: flac(flacBlock(false, streamInfo, make([]byte, 34))), err: ErrInvalidTags},
+		{name: "no comments", data: flac(flacBlock(true, streamInfo, make([]byte, 34))), err: ErrNoTags},
+		{name: "vendor past the block", data: flac(flacBlock(true, comment, []byte{0xFF, 0xFF, 0xFF, 0xFF, 'v'})), err: ErrInvalidTags},
+		{name: "count missing", data: flac(flacBlock(true, comment, []byte{1, 0, 0, 0, 'v'})), err: ErrInvalidTags},
+		{name: "more comments than stored", data: flac(flacBlock(true, comment, tooMany)), err: ErrInvalidTags},
+		{name: "block size past the file", data: flac([]byte{streamInfo, 0xFF, 0xFF, 0xFF}), err: ErrInvalidTags},
+		{name: "blocks over the size limit", data: flac(
+			[]byte{streamInfo, 0xFF, 0xFF, 0xFF}, make([]byte, 0xFFFFFF),
+			[]byte{streamInfo | 0x80, 0, 0, 0x10},
+		), err: ErrInvalidTags},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.data)); !errors.Is(err, tt.err) {
+				t.Errorf("Read() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+// oggPage encodes a page holding packets, splitting them into segments
+// of at most 255 bytes. A packet of a multiple of 255 bytes ends with an
+// empty segment.
+func oggPage(packets ...[]byte) []byte {
+	var lacing, body []byte
+	for _, packet := range packets {
+		n := len(packet)
+		for ; n >= 255; n -= 255 {
+			lacing = append(lacing, 255)
+		}
+		lacing = append(lacing, byte(n))
+		body = append(body, packet...)
+	}
+	header := append([]byte("OggS"), make([]byte, 22)...)
+	header = append(header, byte(len(lacing)))
+	return concat(header, lacing, body)
+}
+
+func TestReadOgg(t *testing.T) {
+	lyrics := strings.Repeat("Песен, ещё не написанных, сколько?\n", 20)
+	vorbis := concat([]byte("\x03vorbis"), vorbisComments("Xiph.Org libVorbis",
+		"TITLE=Кукушка",
+		"ARTIST=Кино",
+		"YEAR=1990",
+		"LYRICS="+lyrics,
+		"METADATA_BLOCK_PICTURE="+base64.StdEncoding.EncodeToString(flacPicture(3, "image/jpeg", picture)),
+		"METADATA_BLOCK_PICTURE=not base64",
+	), []byte{1})
+	opus := concat([]byte("OpusTags"), vorbisComments("libopus", "title=Song", "artist=Group"))
+
+	tests := []struct {
+		name string
+		data []byte
+		want Tags
+	}{
+		{
+			name: "vorbis over several segments",
+			data: concat(oggPage([]byte("\x01vorbis identification")), oggPage(vorbis, []byte("\x05vorbis setup"))),
+			want: Tags{
+				Title:      "Кукушка",
+				Artist:     "Кино",
+				Date:       date(1990, time.January, 1),
+				DateIsYear: true,
+				Lyrics:     lyrics,
+				Picture:    &Picture{MIMEType: "image/jpeg", Data: picture},
+			},
+		},
+		{
+			name: "opus in the first page",
+			data: oggPage([]byte("OpusHead identification"), opus),
+			want: Tags{Title: "Song", Artist: "Group"},
+		},
+		{
+			name: "packet of 255 bytes",
+			data: oggPage([]byte("OpusHead"), concat(opus, make([]byte, 255-len(opus)))),
+			want: Tags{Title: "Song", Artist: "Group"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			assertTags(t, got, tt.want)
+		})
+	}
+}
+
+func TestReadOggErrors(t *testing.T) {
+	opus := concat([]byte("OpusTags"), vorbisComments("libopus", "TITLE=Song"))
+	valid := oggPage([]byte("OpusHead"), opus)
+	badCapture := bytes.Clone(valid)
+	copy(badCapture, "OggX")
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "truncated page header", data: valid[:20], err: ErrInvalidTags},
+		{name: "truncated lacing", data: valid[:28], err: ErrInvalidTags},
+		{name: "truncated packet", data: valid[:len(valid)-4], err: ErrInvalidTags},
+		{name: "single packet", data: oggPage([]byte("OpusHead")), err: ErrInvalidTags},
+		{name: "bad second page", data: concat(oggPage([]byte("OpusHead")), badCapture), err: ErrInvalidTags},
+		{name: "not a comment header", data: oggPage([]byte("\x01vorbis"), []byte("\x05vorbis setup")), err: ErrNoTags},
+		{name: "no fields", data: oggPage([]byte("OpusHead"), concat([]byte("OpusTags"), vorbisComments("libopus"))), err: ErrNoTags},
+		{name: "malformed comments", data: oggPage([]byte("OpusHead"), []byte("OpusTags\x01")), err: ErrInvalidTags},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.data)); !errors.Is(err, tt.err) {
+				t.Errorf("Read() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestReadUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "short", data: []byte("ID")},
+		{name: "mp3 without a tag", data: []byte{0xFF, 0xFB, 0x90, 0x00, 0x00}},
+		{name: "wav", data: []byte("RIFF\x24\x00\x00\x00WAVE")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.data)); !errors.Is(err, ErrNoTags) {
+				t.Errorf("Read() error = %v, want %v", err, ErrNoTags)
+			}
+		})
+	}
+}
+
+// TestReadGarbage feeds every prefix of the fixtures, and the fixtures
+// with bytes flipped, to Read, which must fail cleanly rather than panic.
+func TestReadGarbage(t *testing.T) {
+	const comment = 4
+	fixtures := [][]byte{
+		id3Tag(3, 0x80|0x40, concat([]byte{0, 0, 0, 6, 0, 0, 0, 0, 0, 0}, id3Frame(3, "APIC", 0, apic(1, "image/jpeg", 3, []byte{0xFF, 0xFE, 0, 0}, picture)))),
+		id3Tag(4, 0x40, concat(syncsafeBytes(6), []byte{1, 0}, id3Frame(4, "USLT", 0x03, concat(syncsafeBytes(9), []byte("\x01eng\x00\x00\xFF\xFEa\x00"))))),
+		flac(flacBlock(false, comment, vorbisComments("vendor", "TITLE=Song")), flacBlock(true, 6, flacPicture(3, "image/png", picture))),
+		oggPage([]byte("OpusHead"), concat([]byte("OpusTags"), vorbisComments("libopus", "TITLE=Song"))),
+	}
+
+	for _, fixture := range fixtures {
+		for n := range len(fixture) {
+			_, _ = Read(bytes.NewReader(fixture[:n]))
+		}
+		for i := range fixture {
+			for _, b := range []byte{0x00, 0x7F, 0x80, 0xFF} {
+				flipped := bytes.Clone(fixture)
+				flipped[i] = b
+				_, _ = Read(bytes.NewReader(flipped))
+			}
+		}
+	}
+}
+
+func assertTags(t *testing.T, got, want Tags) {
+	t.Helper()
+	if got.Title != want.Title || got.Artist != want.Artist || got.Album != want.Album {
+		t.Errorf("title, artist, album = %q, %q, %q, want %q, %q, %q", got.Title, got.Artist, got.Album, want.Title, want.Artist, want.Album)
+	}
+	if !got.Date.Equal(want.Date) || got.DateIsYear != want.DateIsYear {
+		t.Errorf("date = %v (year only %v), want %v (year only %v)", got.Date, got.DateIsYear, want.Date, want.DateIsYear)
+	}
+	if got.TrackNumber != want.TrackNumber {
+		t.Errorf("track = %d, want %d", got.TrackNumber, want.TrackNumber)
+	}
+	if got.Lyrics != want.Lyrics {
+		t.Errorf("lyrics = %q, want %q", got.Lyrics, want.Lyrics)
+	}
+	switch {
+	case got.Picture == nil && want.Picture == nil:
+	case got.Picture == nil || want.Picture == nil:
+		t.Errorf("picture = %v, want %v", got.Picture, want.Picture)
+	case got.Picture.MIMEType != want.Picture.MIMEType || !bytes.Equal(got.Picture.Data, want.Picture.Data):
+		t.Errorf("picture = %s %x, want %s %x", got.Picture.MIMEType, got.Picture.Data, want.Picture.MIMEType, want.Picture.Data)
+	}
+}
@@ -0,0 +1,185 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// readID3 reads an ID3v2.3 or ID3v2.4 tag.
+func readID3(r io.Reader) (Tags, error) {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Tags{}, ErrInvalidTags
+	}
+
+	version, flags := header[3], header[5]
+	if version != 3 && version != 4 {
+		return Tags{}, ErrNoTags
+	}
+
+	size := syncsafe(header[6:10])
+	if size > maxTagSize {
+		return Tags{}, ErrInvalidTags
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return Tags{}, ErrInvalidTags
+	}
+
+	const unsynchronised, extended = 0x80, 0x40
+	if flags&unsynchronised != 0 {
+		body = bytes.ReplaceAll(body, []byte{0xFF, 0x00}, []byte{0xFF})
+	}
+	if flags&extended != 0 {
+		if len(body) < 4 {
+			return Tags{}, ErrInvalidTags
+		}
+		skip := int(binary.BigEndian.Uint32(body)) + 4
+		if version == 4 {
+			skip = syncsafe(body[:4])
+		}
+		if skip > len(body) {
+			return Tags{}, ErrInvalidTags
+		}
+		body = body[skip:]
+	}
+
+	var tags Tags
+	for len(body) >= 10 && body[0] != 0 {
+		id := string(body[:4])
+		size := int(binary.BigEndian.Uint32(body[4:8]))
+		if version == 4 {
+			size = syncsafe(body[4:8])
+		}
+		frameFlags := body[9]
+		if size < 0 || size > len(body)-10 {
+			return Tags{}, ErrInvalidTags
+		}
+		frame := body[10 : 10+size]
+		body = body[10+size:]
+
+		// Compressed and encrypted frames are skipped.
+		if (version == 3 && frameFlags&0xC0 != 0) || (version == 4 && frameFlags&0x0C != 0) {
+			continue
+		}
+		if version == 4 {
+			const frameUnsynchronised, dataLength = 0x02, 0x01
+			if frameFlags&dataLength != 0 {
+				if len(frame) < 4 {
+					continue
+				}
+				frame = frame[4:]
+			}
+			if frameFlags&frameUnsynchronised != 0 {
+				frame = bytes.ReplaceAll(frame, []byte{0xFF, 0x00}, []byte{0xFF})
+			}
+		}
+		readFrame(&tags, id, frame)
+	}
+
+	if tags.empty() {
+		return Tags{}, ErrNoTags
+	}
+	return tags, nil
+}
+
+func readFrame(tags *Tags, id string, frame []byte) {
+	if len(frame) == 0 {
+		return
+	}
+
+	switch id {
+	case "TIT2":
+		tags.Title = textFrame(frame)
+	case "TPE1":
+		tags.Artist = textFrame(frame)
+	case "TALB":
+		tags.Album = textFrame(frame)
+	case "TDRC", "TYER":
+		tags.setDate(textFrame(frame))
+	case "TRCK":
+		tags.setTrack(textFrame(frame))
+	case "USLT":
+		// Encoding, language, content descriptor, then the lyrics.
+		if len(frame) < 4 {
+			return
+		}
+		enc := frame[0]
+		_, lyrics := splitText(enc, frame[4:])
+		if tags.Lyrics == "" {
+			tags.Lyrics = decodeText(enc, lyrics)
+		}
+	case "APIC":
+		// Encoding, MIME type, picture type, description, then the data.
+		enc := frame[0]
+		mimeType, rest, ok := bytes.Cut(frame[1:], []byte{0})
+		if !ok || len(rest) < 1 {
+			return
+		}
+		kind := rest[0]
+		_, data := splitText(enc, rest[1:])
+		tags.setPicture(string(mimeType), kind, data)
+	}
+}
+
+// textFrame decodes a text frame, keeping the first of several values.
+func textFrame(frame []byte) string {
+	value, _ := splitText(frame[0], frame[1:])
+	return strings.TrimSpace(decodeText(frame[0], value))
+}
+
+// splitText splits data after its first string terminated as text of
+// the encoding enc is.
+func splitText(enc byte, data []byte) ([]byte, []byte) {
+	if enc == 1 || enc == 2 {
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return data[:i], data[i+2:]
+			}
+		}
+		return data, nil
+	}
+
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return data[:i], data[i+1:]
+	}
+	return data, nil
+}
+
+// decodeText decodes text in one of the ID3v2 encodings: ISO-8859-1,
+// UTF-16 with a byte order mark, UTF-16BE or UTF-8.
+func decodeText(enc byte, data []byte) string {
+	switch enc {
+	case 0:
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	case 1, 2:
+		order := binary.ByteOrder(binary.BigEndian)
+		if len(data) >= 2 {
+			switch {
+			case data[0] == 0xFF && data[1] == 0xFE:
+				order, data = binary.LittleEndian, data[2:]
+			case data[0] == 0xFE && data[1] == 0xFF:
+				data = data[2:]
+			}
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = order.Uint16(data[2*i:])
+		}
+		return string(utf16.Decode(units))
+	default:
+		return string(data)
+	}
+}
+
+// syncsafe decodes a 28-bit integer stored 7 bits per byte.
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
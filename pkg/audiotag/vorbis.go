@@ -0,0 +1,212 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+// readFLAC reads the Vorbis comment and picture blocks of a FLAC file.
+func readFLAC(r io.Reader) (Tags, error) {
+	if _, err := io.ReadFull(r, make([]byte, 4)); err != nil {
+		return Tags{}, ErrInvalidTags
+	}
+
+	const vorbisComment, picture = 4, 6
+	var tags Tags
+	read := 0
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return Tags{}, ErrInvalidTags
+		}
+		last, kind := header[0]&0x80 != 0, header[0]&0x7F
+		size := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+
+		read += size
+		if read > maxTagSize {
+			return Tags{}, ErrInvalidTags
+		}
+		block := make([]byte, size)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return Tags{}, ErrInvalidTags
+		}
+
+		switch kind {
+		case vorbisComment:
+			if err := readComments(&tags, block); err != nil {
+				return Tags{}, err
+			}
+		case picture:
+			readPicture(&tags, block)
+		}
+		if last {
+			break
+		}
+	}
+
+	if tags.empty() {
+		return Tags{}, ErrNoTags
+	}
+	return tags, nil
+}
+
+// readOgg reads the comment header, the second packet of an Ogg Vorbis
+// or Ogg Opus stream.
+func readOgg(r io.Reader) (Tags, error) {
+	var packet []byte
+	packets := 0
+	for packets < 2 {
+		header := make([]byte, 27)
+		if _, err := io.ReadFull(r, header); err != nil || !bytes.Equal(header[:4], []byte("OggS")) {
+			return Tags{}, ErrInvalidTags
+		}
+		segments := make([]byte, header[26])
+		if _, err := io.ReadFull(r, segments); err != nil {
+			return Tags{}, ErrInvalidTags
+		}
+
+		for _, size := range segments {
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return Tags{}, ErrInvalidTags
+			}
+			if packets == 1 {
+				packet = append(packet, data...)
+				if len(packet) > maxTagSize {
+					return Tags{}, ErrInvalidTags
+				}
+			}
+			// A segment shorter than 255 bytes ends a packet.
+			if size < 255 {
+				packets++
+				if packets == 2 {
+					break
+				}
+			}
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x03vorbis")):
+		packet = packet[7:]
+	case bytes.HasPrefix(packet, []byte("OpusTags")):
+		packet = packet[8:]
+	default:
+		return Tags{}, ErrNoTags
+	}
+
+	var tags Tags
+	if err := readComments(&tags, packet); err != nil {
+		return Tags{}, err
+	}
+	if tags.empty() {
+		return Tags{}, ErrNoTags
+	}
+	return tags, nil
+}
+
+// readComments reads a Vorbis comment list: a vendor string, then
+// NAME=value pairs, all prefixed by little-endian lengths.
+func readComments(tags *Tags, data []byte) error {
+	next := func() ([]byte, bool) {
+		if len(data) < 4 {
+			return nil, false
+		}
+		n := binary.LittleEndian.Uint32(data)
+		if uint64(n) > uint64(len(data)-4) {
+			return nil, false
+		}
+		value := data[4 : 4+n]
+		data = data[4+n:]
+		return value, true
+	}
+
+	if _, ok := next(); !ok {
+		return ErrInvalidTags
+	}
+	if len(data) < 4 {
+		return ErrInvalidTags
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	for i := uint32(0); i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			return ErrInvalidTags
+		}
+		name, value, ok := strings.Cut(string(comment), "=")
+		if !ok {
+			continue
+		}
+
+		switch strings.ToUpper(name) {
+		case "TITLE":
+			setOnce(&tags.Title, value)
+		case "ARTIST":
+			setOnce(&tags.Artist, value)
+		case "ALBUM":
+			setOnce(&tags.Album, value)
+		case "DATE", "YEAR":
+			tags.setDate(value)
+		case "TRACKNUMBER":
+			tags.setTrack(value)
+		case "LYRICS", "UNSYNCEDLYRICS":
+			if tags.Lyrics == "" {
+				tags.Lyrics = value
+			}
+		case "METADATA_BLOCK_PICTURE":
+			if block, err := base64.StdEncoding.DecodeString(value); err == nil {
+				readPicture(tags, block)
+			}
+		}
+	}
+	return nil
+}
+
+// readPicture reads a FLAC picture block: picture type, MIME type,
+// description, four image properties, then the data.
+func readPicture(tags *Tags, block []byte) {
+	next := func() ([]byte, bool) {
+		if len(block) < 4 {
+			return nil, false
+		}
+		n := binary.BigEndian.Uint32(block)
+		if uint64(n) > uint64(len(block)-4) {
+			return nil, false
+		}
+		value := block[4 : 4+n]
+		block = block[4+n:]
+		return value, true
+	}
+
+	if len(block) < 4 {
+		return
+	}
+	kind := binary.BigEndian.Uint32(block)
+	block = block[4:]
+
+	mimeType, ok := next()
+	if !ok {
+		return
+	}
+	if _, ok = next(); !ok || len(block) < 16 {
+		return
+	}
+	block = block[16:]
+
+	data, ok := next()
+	if !ok || kind > 255 {
+		return
+	}
+	tags.setPicture(string(mimeType), byte(kind), data)
+}
+
+func setOnce(field *string, value string) {
+	if *field == "" {
+		*field = strings.TrimSpace(value)
+	}
+}
@@ -0,0 +1,32 @@
+// Package blob stores files under slash-separated keys, such as
+// "artwork/3f2a/original.png", behind an interface so the local
+// filesystem can later be swapped for an object store.
+package blob
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Info describes a stored blob.
+type Info struct {
+	Size    int64
+	ModTime time.Time
+}
+
+type Store interface {
+	// Put stores the contents of r under key, replacing any blob there.
+	// Readers never see a partly written blob.
+	Put(key string, r io.Reader) error
+	// Open returns the blob under key for reading and seeking.
+	Open(key string) (io.ReadSeekCloser, Info, error)
+	// Delete removes the blob under key and every blob whose key starts
+	// with key followed by a slash. Missing blobs are not an error.
+	Delete(key string) error
+}
@@ -0,0 +1,115 @@
+package chordpro
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// NoChord marks a bar without harmony; it is kept as is when transposing.
+const NoChord = "N.C."
+
+var (
+	chordPattern = regexp.MustCompile(`^([A-G])([#b]?)([a-zA-Z0-9+#()°ø^-]*)(?:/([A-G])([#b]?))?$`)
+	sharps       = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	flats        = []string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+	naturals     = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+)
+
+// flatKeys are the keys conventionally written with flats.
+var flatKeys = map[string]bool{
+	"F": true, "Bb": true, "Eb": true, "Ab": true, "Db": true, "Gb": true,
+	"Dm": true, "Gm": true, "Cm": true, "Fm": true, "Bbm": true, "Ebm": true,
+}
+
+type Chord struct {
+	// Root and Bass are pitch classes from 0 (C) to 11 (B); Bass is -1
+	// when the chord has no slash bass.
+	Root    int
+	Bass    int
+	Quality string
+	// Flat records whether the chord was spelled with flats.
+	Flat bool
+	// none is set for NoChord.
+	none bool
+}
+
+func ParseChord(s string) (Chord, error) {
+	if s == NoChord {
+		return Chord{none: true, Bass: -1}, nil
+	}
+
+	m := chordPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Chord{}, fmt.Errorf("%w %q", ErrUnknownChord, s)
+	}
+
+	chord := Chord{
+		Root:    pitch(m[1], m[2]),
+		Bass:    -1,
+		Quality: m[3],
+		Flat:    m[2] == "b" || m[5] == "b",
+	}
+	if m[4] != "" {
+		chord.Bass = pitch(m[4], m[5])
+	}
+	return chord, nil
+}
+
+func (c Chord) String() string {
+	if c.none {
+		return NoChord
+	}
+
+	names := sharps
+	if c.Flat {
+		names = flats
+	}
+	if c.Bass < 0 {
+		return names[c.Root] + c.Quality
+	}
+	return names[c.Root] + c.Quality + "/" + names[c.Bass]
+}
+
+// Transpose moves the chord by n semitones, spelling it with flats when
+// flat is set.
+func (c Chord) Transpose(n int, flat bool) Chord {
+	if c.none {
+		return c
+	}
+
+	c.Root = mod12(c.Root + n)
+	if c.Bass >= 0 {
+		c.Bass = mod12(c.Bass + n)
+	}
+	c.Flat = flat
+	return c
+}
+
+// transposeKey moves a key such as "Bb" or "F#m" by n semitones and
+// reports whether the resulting key is written with flats.
+func transposeKey(key string, n int) (string, bool) {
+	chord, err := ParseChord(key)
+	if err != nil || chord.none {
+		return key, false
+	}
+
+	if flat := chord.Transpose(n, true).String(); flatKeys[flat] {
+		return flat, true
+	}
+	return chord.Transpose(n, false).String(), false
+}
+
+func pitch(natural, accidental string) int {
+	p := naturals[natural[0]]
+	switch accidental {
+	case "#":
+		p++
+	case "b":
+		p--
+	}
+	return mod12(p)
+}
+
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
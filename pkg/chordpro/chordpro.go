@@ -0,0 +1,264 @@
+// Package chordpro parses ChordPro documents, lyrics with chords written
+// inline as "[G]words", and renders them as plain lyrics, text chord sheets
+// or HTML, optionally transposed or arranged for a capo.
+package chordpro
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownChord      = errors.New("unknown chord")
+	ErrUnclosedChord     = errors.New("unclosed chord")
+	ErrUnclosedDirective = errors.New("unclosed directive")
+	ErrUnbalancedSection = errors.New("unbalanced section")
+	ErrInvalidCapo       = errors.New("invalid capo")
+	ErrEmpty             = errors.New("document has no lyrics")
+)
+
+// Section types set by the start_of_* environments; lines outside of any
+// environment belong to a block with an empty type.
+const (
+	SectionVerse  = "verse"
+	SectionChorus = "chorus"
+	SectionBridge = "bridge"
+)
+
+// MaxCapo is the highest capo position accepted.
+const MaxCapo = 11
+
+type Song struct {
+	Title    string
+	Subtitle string
+	Artist   string
+	// Key is the sounding key, independent of the capo.
+	Key    string
+	Capo   int
+	Blocks []Block
+}
+
+type Block struct {
+	Type  string
+	Lines []Line
+}
+
+// Line is either a comment or lyrics split at each chord.
+type Line struct {
+	Comment  string
+	Segments []Segment
+}
+
+// Segment is the text sung from a chord change up to the next one. The
+// first segment of a line has no chord when the line starts with text.
+type Segment struct {
+	Chord *Chord
+	Text  string
+}
+
+var environments = map[string]string{
+	"start_of_chorus": SectionChorus, "soc": SectionChorus,
+	"start_of_verse": SectionVerse, "sov": SectionVerse,
+	"start_of_bridge": SectionBridge, "sob": SectionBridge,
+}
+
+var environmentEnds = map[string]string{
+	"end_of_chorus": SectionChorus, "eoc": SectionChorus,
+	"end_of_verse": SectionVerse, "eov": SectionVerse,
+	"end_of_bridge": SectionBridge, "eob": SectionBridge,
+}
+
+// Parse reads and validates a ChordPro document. Errors name the line
+// they were found on. Directives it does not know are ignored.
+func Parse(text string) (Song, error) {
+	var song Song
+	var block Block
+	flush := func() {
+		if len(block.Lines) > 0 {
+			song.Blocks = append(song.Blocks, block)
+		}
+		block = Block{Type: block.Type}
+	}
+
+	for n, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimRight(raw, " \t")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "#"):
+		case strings.HasPrefix(trimmed, "{"):
+			if !strings.HasSuffix(trimmed, "}") {
+				return Song{}, fmt.Errorf("line %d: %w", n+1, ErrUnclosedDirective)
+			}
+
+			name, value := splitDirective(trimmed[1 : len(trimmed)-1])
+			switch name {
+			case "title", "t":
+				song.Title = value
+			case "subtitle", "st":
+				song.Subtitle = value
+			case "artist":
+				song.Artist = value
+			case "key":
+				if _, err := ParseChord(value); err != nil {
+					return Song{}, fmt.Errorf("line %d: %w", n+1, err)
+				}
+				song.Key = value
+			case "capo":
+				capo, err := strconv.Atoi(value)
+				if err != nil || capo < 0 || capo > MaxCapo {
+					return Song{}, fmt.Errorf("line %d: %w %q", n+1, ErrInvalidCapo, value)
+				}
+				song.Capo = capo
+			case "comment", "c", "comment_italic", "ci", "highlight":
+				block.Lines = append(block.Lines, Line{Comment: value})
+			default:
+				if typ, ok := environments[name]; ok {
+					if block.Type != "" {
+						return Song{}, fmt.Errorf("line %d: %w: %s inside %s", n+1, ErrUnbalancedSection, typ, block.Type)
+					}
+					flush()
+					block.Type = typ
+				} else if typ, ok := environmentEnds[name]; ok {
+					if block.Type != typ {
+						return Song{}, fmt.Errorf("line %d: %w: end of %s", n+1, ErrUnbalancedSection, typ)
+					}
+					flush()
+					block.Type = ""
+				}
+			}
+		default:
+			segments, err := parseSegments(line)
+			if err != nil {
+				return Song{}, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			block.Lines = append(block.Lines, Line{Segments: segments})
+		}
+	}
+
+	if block.Type != "" {
+		return Song{}, fmt.Errorf("%w: %s is never closed", ErrUnbalancedSection, block.Type)
+	}
+	flush()
+
+	if len(song.Blocks) == 0 {
+		return Song{}, ErrEmpty
+	}
+	return song, nil
+}
+
+// splitDirective separates "name: value" or "name value".
+func splitDirective(s string) (string, string) {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok {
+		name, value, _ = strings.Cut(strings.TrimSpace(s), " ")
+	}
+	return strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+}
+
+func parseSegments(line string) ([]Segment, error) {
+	var segments []Segment
+	if i := strings.IndexByte(line, '['); i != 0 {
+		if i < 0 {
+			i = len(line)
+		}
+		segments = append(segments, Segment{Text: line[:i]})
+		line = line[i:]
+	}
+
+	for line != "" {
+		end := strings.IndexByte(line, ']')
+		if end < 0 {
+			return nil, ErrUnclosedChord
+		}
+
+		chord, err := ParseChord(strings.TrimSpace(line[1:end]))
+		if err != nil {
+			return nil, err
+		}
+
+		line = line[end+1:]
+		next := strings.IndexByte(line, '[')
+		if next < 0 {
+			next = len(line)
+		}
+		segments = append(segments, Segment{Chord: &chord, Text: line[:next]})
+		line = line[next:]
+	}
+	return segments, nil
+}
+
+// Transpose returns the song moved by n semitones, key included. Chords
+// are spelled after the new key, or as written when the song has none.
+func (s Song) Transpose(n int) Song {
+	key, flat := transposeKey(s.Key, n)
+	res := s.mapChords(func(c Chord) Chord {
+		if s.Key == "" {
+			return c.Transpose(n, c.Flat)
+		}
+		return c.Transpose(n, flat)
+	})
+	res.Key = key
+	return res
+}
+
+// WithCapo returns the chord shapes to play with a capo on the given fret,
+// keeping the sounding key. The capo the document was written for is
+// taken into account.
+func (s Song) WithCapo(capo int) (Song, error) {
+	if capo < 0 || capo > MaxCapo {
+		return Song{}, fmt.Errorf("%w %d", ErrInvalidCapo, capo)
+	}
+
+	n := s.Capo - capo
+	_, flat := transposeKey(s.Key, -capo)
+	res := s.mapChords(func(c Chord) Chord {
+		if s.Key == "" {
+			return c.Transpose(n, c.Flat)
+		}
+		return c.Transpose(n, flat)
+	})
+	res.Capo = capo
+	return res, nil
+}
+
+func (s Song) mapChords(f func(Chord) Chord) Song {
+	res := s
+	res.Blocks = make([]Block, len(s.Blocks))
+	for i, b := range s.Blocks {
+		res.Blocks[i] = Block{Type: b.Type, Lines: make([]Line, len(b.Lines))}
+		for j, l := range b.Lines {
+			line := Line{Comment: l.Comment}
+			for _, seg := range l.Segments {
+				if seg.Chord != nil {
+					chord := f(*seg.Chord)
+					seg.Chord = &chord
+				}
+				line.Segments = append(line.Segments, seg)
+			}
+			res.Blocks[i].Lines[j] = line
+		}
+	}
+	return res
+}
+
+// Text returns the sung words of a line.
+func (l Line) Text() string {
+	var b strings.Builder
+	for _, seg := range l.Segments {
+		b.WriteString(seg.Text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (l Line) hasChords() bool {
+	for _, seg := range l.Segments {
+		if seg.Chord != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package chordpro
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const song = `{title: Song}
+{artist: Artist}
+{key: G}
+{capo: 2}
+
+[G]Hello [D/F#]there, [Em7]friend
+{start_of_chorus}
+{comment: loud}
+Sing [C]along [N.C.]now
+{end_of_chorus}
+`
+
+func TestParse(t *testing.T) {
+	s, err := Parse(song)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Title != "Song" || s.Artist != "Artist" || s.Key != "G" || s.Capo != 2 {
+		t.Errorf("Parse() metadata = %q %q %q %d", s.Title, s.Artist, s.Key, s.Capo)
+	}
+	if len(s.Blocks) != 2 || s.Blocks[0].Type != "" || s.Blocks[1].Type != SectionChorus {
+		t.Fatalf("Parse() blocks = %+v", s.Blocks)
+	}
+
+	first := s.Blocks[0].Lines[0]
+	if len(first.Segments) != 3 || first.Segments[1].Chord.String() != "D/F#" || first.Segments[1].Text != "there, " {
+		t.Errorf("Parse() segments = %+v", first.Segments)
+	}
+	if got := first.Text(); got != "Hello there, friend" {
+		t.Errorf("Text() = %q", got)
+	}
+
+	chorus := s.Blocks[1].Lines
+	if chorus[0].Comment != "loud" || chorus[1].Segments[0].Chord != nil || chorus[1].Segments[0].Text != "Sing " {
+		t.Errorf("Parse() chorus = %+v", chorus)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  error
+		line string
+	}{
+		{name: "unknown chord", text: "ok\n[H]bad", err: ErrUnknownChord, line: "line 2"},
+		{name: "unclosed chord", text: "[G bad", err: ErrUnclosedChord, line: "line 1"},
+		{name: "unclosed directive", text: "{title: x", err: ErrUnclosedDirective, line: "line 1"},
+		{name: "invalid key", text: "{key: X}\nla", err: ErrUnknownChord, line: "line 1"},
+		{name: "capo too high", text: "{capo: 12}\nla", err: ErrInvalidCapo, line: "line 1"},
+		{name: "capo not a number", text: "{capo: two}\nla", err: ErrInvalidCapo, line: "line 1"},
+		{name: "nested sections", text: "{soc}\n{sov}\nla\n{eov}\n{eoc}", err: ErrUnbalancedSection, line: "line 2"},
+		{name: "mismatched end", text: "{soc}\nla\n{eov}", err: ErrUnbalancedSection, line: "line 3"},
+		{name: "never closed", text: "{soc}\nla", err: ErrUnbalancedSection},
+		{name: "empty", text: "{title: x}\n# comment\n", err: ErrEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.text)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.err)
+			}
+			if !strings.Contains(err.Error(), tt.line) {
+				t.Errorf("Parse() error = %q, want it to name %q", err, tt.line)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	s, err := Parse(song)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{title: Song}
+{artist: Artist}
+{key: G}
+{capo: 2}
+
+[G]Hello [D/F#]there, [Em7]friend
+
+{start_of_chorus}
+{comment: loud}
+Sing [C]along [N.C.]now
+{end_of_chorus}
+`
+	if got := s.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	again, err := Parse(s.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != s.String() {
+		t.Errorf("Parse(String()).String() = %q, want %q", again.String(), s.String())
+	}
+}
+
+func TestParseChord(t *testing.T) {
+	for _, chord := range []string{"C", "C#m7", "Bbmaj7", "F#7b9", "Dsus4", "G/B", "Ebm/Gb", "A7(#11)", "Bdim°", NoChord} {
+		c, err := ParseChord(chord)
+		if err != nil {
+			t.Errorf("ParseChord(%q) error = %v", chord, err)
+			continue
+		}
+		if c.String() != chord {
+			t.Errorf("ParseChord(%q).String() = %q", chord, c.String())
+		}
+	}
+
+	for _, chord := range []string{"", "H", "c", "G/X", "Am/"} {
+		if _, err := ParseChord(chord); !errors.Is(err, ErrUnknownChord) {
+			t.Errorf("ParseChord(%q) error = %v, want %v", chord, err, ErrUnknownChord)
+		}
+	}
+}
+
+func chords(s Song) string {
+	var names []string
+	for _, b := range s.Blocks {
+		for _, l := range b.Lines {
+			for _, seg := range l.Segments {
+				if seg.Chord != nil {
+					names = append(names, seg.Chord.String())
+				}
+			}
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		n      int
+		key    string
+		chords string
+	}{
+		{name: "up to a sharp key", text: "{key: G}\n[G]a [D/F#]b [Em7]c [N.C.]d", n: 2, key: "A", chords: "A E/G# F#m7 N.C."},
+		{name: "down to a flat key", text: "{key: G}\n[G]a [D/F#]b [C]c", n: -2, key: "F", chords: "F C/E Bb"},
+		{name: "minor key", text: "{key: Am}\n[Am]a [E7]b", n: 5, key: "Dm", chords: "Dm A7"},
+		{name: "wraps around the octave", text: "{key: A}\n[A]a [B]b", n: 15, key: "C", chords: "C D"},
+		{name: "no key keeps spelling", text: "[Bb]a [F#]b", n: 1, key: "", chords: "B G"},
+		{name: "no key keeps flats", text: "[Eb]a [Ab]b", n: 2, key: "", chords: "F Bb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := s.Transpose(tt.n)
+			if got.Key != tt.key || chords(got) != tt.chords {
+				t.Errorf("Transpose(%d) = key %q chords %q, want key %q chords %q", tt.n, got.Key, chords(got), tt.key, tt.chords)
+			}
+			// The original song is left as it was.
+			if chords(s) == chords(got) && tt.n%12 != 0 {
+				t.Errorf("Transpose(%d) changed the original song", tt.n)
+			}
+		})
+	}
+}
+
+func TestWithCapo(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		capo   int
+		chords string
+		err    bool
+	}{
+		{name: "capo on open chords", text: "{key: A}\n[A]a [E]b [F#m]c", capo: 2, chords: "G D Em"},
+		{name: "capo from a written capo", text: "{key: A}\n{capo: 2}\n[G]a [D]b", capo: 0, chords: "A E"},
+		{name: "moving the capo", text: "{key: Bb}\n{capo: 1}\n[A]a", capo: 3, chords: "G"},
+		{name: "flat key", text: "{key: Eb}\n[Eb]a [Bb]b", capo: 1, chords: "D A"},
+		{name: "capo out of range", text: "[G]a", capo: 12, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.WithCapo(tt.capo)
+			if (err != nil) != tt.err {
+				t.Fatalf("WithCapo(%d) error = %v, want error %v", tt.capo, err, tt.err)
+			}
+			if tt.err {
+				if !errors.Is(err, ErrInvalidCapo) {
+					t.Errorf("WithCapo(%d) error = %v, want %v", tt.capo, err, ErrInvalidCapo)
+				}
+				return
+			}
+			if got.Capo != tt.capo || got.Key != s.Key || chords(got) != tt.chords {
+				t.Errorf("WithCapo(%d) = capo %d key %q chords %q, want capo %d key %q chords %q",
+					tt.capo, got.Capo, got.Key, chords(got), tt.capo, s.Key, tt.chords)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	s, err := Parse("{title: A & B}\n{key: C}\n\n[C]Hello [G7]world\n[Am][F]<now>\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.Lyrics(), "Hello world\n<now>"; got != want {
+		t.Errorf("Lyrics() = %q, want %q", got, want)
+	}
+
+	wantSheet := "A & B\nKey: C\n\nC     G7\nHello world\nAm F\n   <now>\n"
+	if got := s.Sheet(); got != wantSheet {
+		t.Errorf("Sheet() = %q, want %q", got, wantSheet)
+	}
+
+	html := s.HTML()
+	for _, want := range []string{`<div class="meta">A &amp; B</div>`, `<span class="chord">G7</span>`, `&lt;now&gt;`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML() = %q, want it to contain %q", html, want)
+		}
+	}
+}
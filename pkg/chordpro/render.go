@@ -0,0 +1,178 @@
+package chordpro
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lyrics renders the words only, one paragraph per block, leaving out
+// chords and comments.
+func (s Song) Lyrics() string {
+	var blocks []string
+	for _, b := range s.Blocks {
+		var lines []string
+		for _, l := range b.Lines {
+			if l.Comment == "" && l.Text() != "" {
+				lines = append(lines, l.Text())
+			}
+		}
+		if len(lines) > 0 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// Sheet renders a monospace chord sheet with chords above the words.
+func (s Song) Sheet() string {
+	var b strings.Builder
+	for _, l := range s.header() {
+		fmt.Fprintf(&b, "%s\n", l)
+	}
+
+	for i, block := range s.Blocks {
+		if i > 0 || b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		if block.Type != "" && block.Type != SectionVerse {
+			fmt.Fprintf(&b, "%s:\n", strings.ToUpper(block.Type[:1])+block.Type[1:])
+		}
+
+		for _, l := range block.Lines {
+			if l.Comment != "" {
+				fmt.Fprintf(&b, "(%s)\n", l.Comment)
+				continue
+			}
+			if !l.hasChords() {
+				fmt.Fprintf(&b, "%s\n", strings.TrimRight(l.Text(), " "))
+				continue
+			}
+
+			var chords, words strings.Builder
+			for _, seg := range l.Segments {
+				width := utf8.RuneCountInString(seg.Text)
+				name := ""
+				if seg.Chord != nil {
+					name = seg.Chord.String()
+					// Keep a space between chords sung on one syllable.
+					width = max(width, utf8.RuneCountInString(name)+1)
+				}
+				chords.WriteString(pad(name, width))
+				words.WriteString(pad(seg.Text, width))
+			}
+			fmt.Fprintf(&b, "%s\n", strings.TrimRight(chords.String(), " "))
+			if text := strings.TrimRight(words.String(), " "); text != "" {
+				fmt.Fprintf(&b, "%s\n", text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// HTML renders the chord sheet as an HTML fragment. Each chord and the
+// words sung on it share a span so the layout can be styled with CSS.
+func (s Song) HTML() string {
+	var b strings.Builder
+	b.WriteString(`<div class="chordpro">` + "\n")
+	for _, l := range s.header() {
+		fmt.Fprintf(&b, `<div class="meta">%s</div>`+"\n", html.EscapeString(l))
+	}
+
+	for _, block := range s.Blocks {
+		typ := block.Type
+		if typ == "" {
+			typ = SectionVerse
+		}
+		fmt.Fprintf(&b, `<div class="%s">`+"\n", typ)
+
+		for _, l := range block.Lines {
+			if l.Comment != "" {
+				fmt.Fprintf(&b, `<div class="comment">%s</div>`+"\n", html.EscapeString(l.Comment))
+				continue
+			}
+
+			b.WriteString(`<div class="line">`)
+			for _, seg := range l.Segments {
+				b.WriteString(`<span class="segment">`)
+				if seg.Chord != nil {
+					fmt.Fprintf(&b, `<span class="chord">%s</span>`, html.EscapeString(seg.Chord.String()))
+				}
+				fmt.Fprintf(&b, `<span class="lyrics">%s</span></span>`, html.EscapeString(seg.Text))
+			}
+			b.WriteString("</div>\n")
+		}
+		b.WriteString("</div>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+// String renders the song back as a ChordPro document.
+func (s Song) String() string {
+	var b strings.Builder
+	for _, d := range [][2]string{{"title", s.Title}, {"subtitle", s.Subtitle}, {"artist", s.Artist}, {"key", s.Key}} {
+		if d[1] != "" {
+			fmt.Fprintf(&b, "{%s: %s}\n", d[0], d[1])
+		}
+	}
+	if s.Capo > 0 {
+		fmt.Fprintf(&b, "{capo: %d}\n", s.Capo)
+	}
+
+	for _, block := range s.Blocks {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		if block.Type != "" {
+			fmt.Fprintf(&b, "{start_of_%s}\n", block.Type)
+		}
+		for _, l := range block.Lines {
+			if l.Comment != "" {
+				fmt.Fprintf(&b, "{comment: %s}\n", l.Comment)
+				continue
+			}
+			for _, seg := range l.Segments {
+				if seg.Chord != nil {
+					fmt.Fprintf(&b, "[%s]", seg.Chord)
+				}
+				b.WriteString(seg.Text)
+			}
+			b.WriteByte('\n')
+		}
+		if block.Type != "" {
+			fmt.Fprintf(&b, "{end_of_%s}\n", block.Type)
+		}
+	}
+	return b.String()
+}
+
+// header lists the title, subtitle, artist and key lines shown above a sheet.
+func (s Song) header() []string {
+	var lines []string
+	for _, v := range []string{s.Title, s.Subtitle, s.Artist} {
+		if v != "" {
+			lines = append(lines, v)
+		}
+	}
+
+	var meta []string
+	if s.Key != "" {
+		meta = append(meta, "Key: "+s.Key)
+	}
+	if s.Capo > 0 {
+		meta = append(meta, fmt.Sprintf("Capo: %d", s.Capo))
+	}
+	if len(meta) > 0 {
+		lines = append(lines, strings.Join(meta, "  "))
+	}
+	return lines
+}
+
+func pad(s string, width int) string {
+	if n := width - utf8.RuneCountInString(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
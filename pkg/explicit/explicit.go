@@ -0,0 +1,87 @@
+// Package explicit flags texts containing words from a configurable list.
+//
+// The list holds one word per line; blank lines and lines starting with
+// "#" are skipped. A trailing "*" matches every word with that prefix.
+// Matching is case-insensitive and on whole words only.
+package explicit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+type Filter struct {
+	words    map[string]bool
+	prefixes []string
+}
+
+func New(words []string) *Filter {
+	f := &Filter{words: make(map[string]bool, len(words))}
+	for _, w := range words {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w == "" || strings.HasPrefix(w, "#") {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(w, "*"); ok {
+			if prefix != "" {
+				f.prefixes = append(f.prefixes, prefix)
+			}
+			continue
+		}
+		f.words[w] = true
+	}
+	return f
+}
+
+// Load reads a word list.
+func Load(r io.Reader) (*Filter, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		words = append(words, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return New(words), nil
+}
+
+// LoadFile reads a word list from path. An empty path gives a filter that
+// flags nothing.
+func LoadFile(path string) (*Filter, error) {
+	if path == "" {
+		return New(nil), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open word list: %w", err)
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// Match reports whether text contains a listed word.
+func (f *Filter) Match(text string) bool {
+	if len(f.words) == 0 && len(f.prefixes) == 0 {
+		return false
+	}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if f.words[word] {
+			return true
+		}
+		for _, p := range f.prefixes {
+			if strings.HasPrefix(word, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
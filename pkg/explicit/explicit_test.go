@@ -0,0 +1,86 @@
+package explicit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	f := New([]string{"damn", "  Hell ", "# comment", "", "bitch*", "*", "ass"})
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "damn", want: true},
+		{text: "Damn it all", want: true},
+		{text: "DAMN!", want: true},
+		{text: "go to hell.", want: true},
+		{text: "hell's bells", want: true},
+		{text: "line one\nline two, damn", want: true},
+		{text: "bitches and bitching", want: true},
+		{text: "bitch", want: true},
+		{text: "(ass)", want: true},
+		{text: "damnation", want: false},
+		{text: "goddamn", want: false},
+		{text: "hello shell", want: false},
+		{text: "class assassin bass", want: false},
+		{text: "# comment", want: false},
+		{text: "comment", want: false},
+		{text: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := f.Match(tt.text); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMatchUnicode(t *testing.T) {
+	f := New([]string{"блин", "scheiß*"})
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "Ну, блин!", want: true},
+		{text: "БЛИН", want: true},
+		{text: "блинчики", want: false},
+		{text: "Scheißegal", want: true},
+		{text: "word1блин", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := f.Match(tt.text); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyFilter(t *testing.T) {
+	f, err := LoadFile("")
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if f.Match("damn") {
+		t.Error("Match() of an empty list = true, want false")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	f, err := Load(strings.NewReader("# words\ndamn\r\n\nhell*\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for text, want := range map[string]bool{"damn": true, "hellish": true, "words": false} {
+		if got := f.Match(text); got != want {
+			t.Errorf("Match(%q) = %v, want %v", text, got, want)
+		}
+	}
+
+	if _, err = LoadFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadFile() of a missing file error = nil")
+	}
+}
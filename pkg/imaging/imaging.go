@@ -0,0 +1,90 @@
+// Package imaging checks uploaded images and makes thumbnails of them in
+// pure Go. JPEG, PNG, GIF and WebP images are accepted; their type is
+// sniffed from the content, not taken from the client.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooManyPixels     = errors.New("image has too many pixels")
+)
+
+// formats maps the accepted content types to file extensions.
+var formats = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// Image is a decoded upload.
+type Image struct {
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+	img         image.Image
+}
+
+// Decode sniffs and decodes data, refusing images of more than maxPixels
+// pixels before decoding them, as a small file can hold a huge image.
+func Decode(data []byte, maxPixels int) (Image, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := formats[contentType]
+	if !ok {
+		return Image{}, ErrUnsupportedFormat
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, ErrUnsupportedFormat
+	}
+
+	if cfg.Width*cfg.Height > maxPixels {
+		return Image{}, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, ErrUnsupportedFormat
+	}
+
+	return Image{
+		ContentType: contentType,
+		Ext:         ext,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		img:         img,
+	}, nil
+}
+
+// WriteThumbnail writes a JPEG of the image scaled to fit a size by size
+// square, keeping its aspect ratio. Images already that small are not
+// enlarged. Transparent areas become white.
+func (i Image) WriteThumbnail(w io.Writer, size int) error {
+	width, height := i.Width, i.Height
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(height*size/width, 1)
+		} else {
+			width, height = max(width*size/height, 1), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), i.img, i.img.Bounds(), draw.Over, nil)
+	return jpeg.Encode(w, dst, &jpeg.Options{Quality: 85})
+}
@@ -0,0 +1,172 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	secret = []byte("test-secret")
+	now    = time.Unix(1_700_000_000, 0)
+)
+
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, key []byte, header, claims interface{}) string {
+	t.Helper()
+	signed := encode(t, header) + "." + encode(t, claims)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims interface{}) string {
+	t.Helper()
+	signed := encode(t, map[string]string{"alg": AlgRS256, "typ": "JWT"}) + "." + encode(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func hs256Header() map[string]string {
+	return map[string]string{"alg": AlgHS256, "typ": "JWT"}
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub": "user",
+		"iss": "issuer",
+		"aud": "library",
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func with(claims map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(claims)+1)
+	for k, v := range claims {
+		res[k] = v
+	}
+	if value == nil {
+		delete(res, key)
+	} else {
+		res[key] = value
+	}
+	return res
+}
+
+func newVerifier(opts ...Option) *Verifier {
+	v := NewVerifier(append([]Option{WithIssuer("issuer"), WithAudience("library")}, opts...)...)
+	v.now = func() time.Time { return now }
+	return v
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v := newVerifier(WithHS256Secret(secret))
+	valid := signHS256(t, secret, hs256Header(), validClaims())
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{name: "valid", token: valid},
+		{name: "bad signature", token: signHS256(t, []byte("other-secret"), hs256Header(), validClaims()), err: ErrInvalidSignature},
+		{name: "tampered claims", token: parts[0] + "." + encode(t, with(validClaims(), "sub", "admin")) + "." + parts[2], err: ErrInvalidSignature},
+		{name: "alg none", token: encode(t, map[string]string{"alg": "none"}) + "." + encode(t, validClaims()) + ".", err: ErrUnsupportedAlg},
+		{name: "unknown alg", token: signHS256(t, secret, map[string]string{"alg": "HS512"}, validClaims()), err: ErrUnsupportedAlg},
+		{name: "expired within leeway", token: signHS256(t, secret, hs256Header(), with(validClaims(), "exp", now.Add(-leeway+time.Second).Unix()))},
+		{name: "expired past leeway", token: signHS256(t, secret, hs256Header(), with(validClaims(), "exp", now.Add(-leeway-time.Second).Unix())), err: ErrTokenExpired},
+		{name: "missing exp", token: signHS256(t, secret, hs256Header(), with(validClaims(), "exp", nil)), err: ErrMissingExpiration},
+		{name: "nbf within leeway", token: signHS256(t, secret, hs256Header(), with(validClaims(), "nbf", now.Add(leeway-time.Second).Unix()))},
+		{name: "nbf past leeway", token: signHS256(t, secret, hs256Header(), with(validClaims(), "nbf", now.Add(leeway+time.Second).Unix())), err: ErrTokenNotYetValid},
+		{name: "wrong issuer", token: signHS256(t, secret, hs256Header(), with(validClaims(), "iss", "other")), err: ErrInvalidIssuer},
+		{name: "aud array", token: signHS256(t, secret, hs256Header(), with(validClaims(), "aud", []string{"other", "library"}))},
+		{name: "aud array without audience", token: signHS256(t, secret, hs256Header(), with(validClaims(), "aud", []string{"other"})), err: ErrInvalidAudience},
+		{name: "wrong aud string", token: signHS256(t, secret, hs256Header(), with(validClaims(), "aud", "other")), err: ErrInvalidAudience},
+		{name: "two segments", token: "a.b", err: ErrMalformedToken},
+		{name: "four segments", token: valid + ".x", err: ErrMalformedToken},
+		{name: "header not base64", token: "!!!." + parts[1] + "." + parts[2], err: ErrMalformedToken},
+		{name: "header not json", token: base64.RawURLEncoding.EncodeToString([]byte("{")) + ".e30.", err: ErrMalformedToken},
+		{name: "signature not base64", token: parts[0] + "." + parts[1] + ".!!!", err: ErrMalformedToken},
+		{name: "claims not json", token: signHS256(t, secret, hs256Header(), "not an object"), err: ErrMalformedToken},
+		{name: "aud of wrong type", token: signHS256(t, secret, hs256Header(), with(validClaims(), "aud", 42)), err: ErrMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && claims.Subject != "user" {
+				t.Errorf("Verify() subject = %q, want %q", claims.Subject, "user")
+			}
+		})
+	}
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := newVerifier(WithRS256PublicKey(&key.PublicKey))
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{name: "valid", token: signRS256(t, key, validClaims())},
+		{name: "signed by another key", token: signRS256(t, other, validClaims()), err: ErrInvalidSignature},
+		// An HS256 token must not be accepted when only RS256 is set up,
+		// least of all one keyed with the public key.
+		{name: "HS256 with public key as secret", token: signHS256(t, key.PublicKey.N.Bytes(), hs256Header(), validClaims()), err: ErrUnsupportedAlg},
+		{name: "HS256 with empty secret", token: signHS256(t, nil, hs256Header(), validClaims()), err: ErrUnsupportedAlg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.Verify(tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestVerifyWithoutKeys(t *testing.T) {
+	v := newVerifier()
+	if v.Enabled() {
+		t.Fatal("Enabled() = true without keys")
+	}
+
+	token := signHS256(t, nil, hs256Header(), validClaims())
+	if _, err := v.Verify(token); !errors.Is(err, ErrUnsupportedAlg) {
+		t.Fatalf("Verify() error = %v, want %v", err, ErrUnsupportedAlg)
+	}
+}
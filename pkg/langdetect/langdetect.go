@@ -0,0 +1,178 @@
+// Package langdetect guesses the language of a text offline. Texts in a
+// script used by a single language are decided by script; Latin and
+// Cyrillic texts are compared against character trigram profiles built
+// from the bundled samples.
+package langdetect
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MinLetters is the shortest text, in letters, that is classified by
+// trigrams. Texts decided by script need a single letter.
+const MinLetters = 20
+
+// minScore rejects texts whose best profile match is too weak to trust.
+const minScore = 0.05
+
+type profile map[string]float64
+
+var profiles = map[string]profile{}
+
+func init() {
+	for lang, sample := range samples {
+		profiles[lang] = normalize(trigrams(sample))
+	}
+}
+
+// Detect returns the ISO 639-1 code of the language of text, or "" when
+// the text is too short or matches no language well enough.
+func Detect(text string) string {
+	lang, _ := DetectWithScore(text)
+	return lang
+}
+
+// DetectWithScore is Detect that also reports the cosine similarity of the
+// text to the chosen profile, 1 for texts decided by script alone.
+func DetectWithScore(text string) (string, float64) {
+	counts := map[*unicode.RangeTable]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, table := range scriptOrder {
+			if unicode.Is(table, r) {
+				counts[table]++
+				break
+			}
+		}
+	}
+
+	if letters == 0 {
+		return "", 0
+	}
+
+	script, best := unicode.Latin, 0
+	for _, table := range scriptOrder {
+		if counts[table] > best {
+			script, best = table, counts[table]
+		}
+	}
+
+	switch script {
+	case unicode.Latin, unicode.Cyrillic:
+		if letters < MinLetters {
+			return "", 0
+		}
+		if script == unicode.Cyrillic {
+			return closest(text, cyrillicLanguages)
+		}
+		return closest(text, latinLanguages)
+	case unicode.Han:
+		// Japanese mixes kanji with kana, so any kana decides it.
+		if counts[unicode.Hiragana]+counts[unicode.Katakana] > 0 {
+			return "ja", 1
+		}
+		return "zh", 1
+	default:
+		return scriptLanguages[script], 1
+	}
+}
+
+var scriptOrder = []*unicode.RangeTable{
+	unicode.Latin, unicode.Cyrillic, unicode.Greek, unicode.Arabic, unicode.Hebrew,
+	unicode.Hangul, unicode.Hiragana, unicode.Katakana, unicode.Han, unicode.Thai,
+	unicode.Georgian, unicode.Armenian, unicode.Devanagari,
+}
+
+var scriptLanguages = map[*unicode.RangeTable]string{
+	unicode.Greek:      "el",
+	unicode.Arabic:     "ar",
+	unicode.Hebrew:     "he",
+	unicode.Hangul:     "ko",
+	unicode.Hiragana:   "ja",
+	unicode.Katakana:   "ja",
+	unicode.Thai:       "th",
+	unicode.Georgian:   "ka",
+	unicode.Armenian:   "hy",
+	unicode.Devanagari: "hi",
+}
+
+var (
+	latinLanguages    = []string{"en", "de", "fr", "es", "it", "pt", "nl", "pl"}
+	cyrillicLanguages = []string{"ru", "uk", "be", "bg"}
+)
+
+func closest(text string, candidates []string) (string, float64) {
+	grams := normalize(trigrams(text))
+	lang, best := "", 0.0
+	for _, c := range candidates {
+		if score := similarity(grams, profiles[c]); score > best {
+			lang, best = c, score
+		}
+	}
+
+	if best < minScore {
+		return "", best
+	}
+	return lang, best
+}
+
+// trigrams counts the letter trigrams of text, words padded with spaces.
+func trigrams(text string) map[string]float64 {
+	counts := map[string]float64{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, w := range words {
+		runes := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			counts[string(runes[i:i+3])]++
+		}
+	}
+	return counts
+}
+
+// normalize scales a trigram vector to unit length, keeping the most
+// frequent trigrams only so long texts do not dominate by noise.
+func normalize(counts map[string]float64) profile {
+	const keep = 400
+	grams := make([]string, 0, len(counts))
+	for g := range counts {
+		grams = append(grams, g)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	if len(grams) > keep {
+		grams = grams[:keep]
+	}
+
+	var norm float64
+	for _, g := range grams {
+		norm += counts[g] * counts[g]
+	}
+	norm = math.Sqrt(norm)
+
+	p := make(profile, len(grams))
+	for _, g := range grams {
+		p[g] = counts[g] / norm
+	}
+	return p
+}
+
+func similarity(a, b profile) float64 {
+	var dot float64
+	for g, v := range a {
+		dot += v * b[g]
+	}
+	return dot
+}
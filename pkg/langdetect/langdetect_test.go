@@ -0,0 +1,61 @@
+package langdetect
+
+import (
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "english", text: "I have been waiting for you all night long, and the morning will not come", want: "en"},
+		{name: "german", text: "Ich habe die ganze Nacht auf dich gewartet, und der Morgen kommt nicht", want: "de"},
+		{name: "french", text: "Je t'ai attendu toute la nuit, et le matin ne veut pas venir", want: "fr"},
+		{name: "spanish", text: "Te he esperado toda la noche, y la mañana no quiere llegar", want: "es"},
+		{name: "italian", text: "Ti ho aspettato tutta la notte, e il mattino non vuole arrivare", want: "it"},
+		{name: "portuguese", text: "Eu esperei por você a noite toda, e a manhã não quer chegar", want: "pt"},
+		{name: "dutch", text: "Ik heb de hele nacht op je gewacht, en de ochtend wil niet komen", want: "nl"},
+		{name: "polish", text: "Czekałem na ciebie całą noc, a poranek nie chce przyjść", want: "pl"},
+		{name: "russian", text: "Я ждал тебя всю ночь, а утро всё не хочет приходить", want: "ru"},
+		{name: "ukrainian", text: "Я чекав на тебе всю ніч, а ранок усе не хоче приходити", want: "uk"},
+		{name: "greek", text: "Σε περίμενα", want: "el"},
+		{name: "arabic", text: "انتظرتك", want: "ar"},
+		{name: "hebrew", text: "חיכיתי לך", want: "he"},
+		{name: "korean", text: "밤새 기다렸어", want: "ko"},
+		{name: "japanese with kanji", text: "一晩中待っていた", want: "ja"},
+		{name: "japanese kana only", text: "まっていた", want: "ja"},
+		{name: "chinese", text: "我等了你一整夜", want: "zh"},
+		{name: "georgian", text: "მთელი ღამე", want: "ka"},
+		{name: "mostly cyrillic", text: "OK, я ждал тебя всю ночь, а утро всё не хочет приходить", want: "ru"},
+		{name: "latin too short", text: "all night long", want: ""},
+		{name: "cyrillic too short", text: "всю ночь", want: ""},
+		{name: "no letters", text: "1, 2, 3 ... 42!", want: ""},
+		{name: "empty", text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectWithScore(t *testing.T) {
+	if lang, score := DetectWithScore("밤새 기다렸어"); lang != "ko" || score != 1 {
+		t.Errorf("DetectWithScore() of a script = %q, %v, want ko, 1", lang, score)
+	}
+
+	lang, score := DetectWithScore("I have been waiting for you all night long, and the morning will not come")
+	if lang != "en" || score < minScore || score > 1 {
+		t.Errorf("DetectWithScore() of trigrams = %q, %v, want en with a score from %v to 1", lang, score, minScore)
+	}
+
+	// Letters that form no trigram of any profile match nothing.
+	if lang, _ := DetectWithScore("qxz qxz qxz qxz qxz qxz qxz"); lang != "" {
+		t.Errorf("DetectWithScore() of noise = %q, want none", lang)
+	}
+}
@@ -0,0 +1,99 @@
+package langdetect
+
+// samples are everyday texts used to build the trigram profiles. They
+// favour the function words and endings song lyrics are made of.
+var samples = map[string]string{
+	"en": `I know that you are the one for me and I will never let you go. When the night is cold and the
+road is long, we are walking home together and the stars are shining in your eyes. Tell me what you
+want, tell me what you need, I would give you everything I have. There is nothing in the world that
+could take this love away from us. Every time I think about the way you looked at me, my heart is
+beating faster than it ever did before. We have been dreaming of the summer, of the sea and of the
+sun, and now the days are getting shorter but I still believe in you. Hold my hand and do not be
+afraid, because tonight the whole world belongs to the two of us and nothing else matters anymore.
+She said that they were going to the city with their friends, but he was waiting by the window.`,
+	"de": `Ich weiß, dass du die Eine für mich bist, und ich werde dich niemals gehen lassen. Wenn die
+Nacht kalt ist und der Weg lang, gehen wir zusammen nach Hause und die Sterne leuchten in deinen
+Augen. Sag mir, was du willst, sag mir, was du brauchst, ich würde dir alles geben, was ich habe. Es
+gibt nichts auf der Welt, das uns diese Liebe nehmen könnte. Jedes Mal, wenn ich daran denke, wie du
+mich angesehen hast, schlägt mein Herz schneller als je zuvor. Wir haben vom Sommer geträumt, vom
+Meer und von der Sonne, und jetzt werden die Tage kürzer, aber ich glaube immer noch an dich. Halt
+meine Hand und hab keine Angst, denn heute Nacht gehört die ganze Welt nur uns beiden allein.
+Sie sagte, dass sie mit ihren Freunden in die Stadt fahren wollten, aber er wartete am Fenster.`,
+	"fr": `Je sais que tu es celle qu'il me faut et je ne te laisserai jamais partir. Quand la nuit est
+froide et que la route est longue, nous rentrons ensemble à la maison et les étoiles brillent dans
+tes yeux. Dis-moi ce que tu veux, dis-moi ce dont tu as besoin, je te donnerais tout ce que j'ai. Il
+n'y a rien au monde qui pourrait nous enlever cet amour. Chaque fois que je pense à la façon dont tu
+m'as regardé, mon cœur bat plus vite que jamais. Nous avons rêvé de l'été, de la mer et du soleil,
+et maintenant les jours raccourcissent mais je crois encore en toi. Tiens ma main et n'aie pas peur,
+car ce soir le monde entier nous appartient et rien d'autre ne compte plus. Elle a dit qu'ils
+allaient en ville avec leurs amis, mais il attendait près de la fenêtre.`,
+	"es": `Sé que eres la única para mí y nunca te dejaré ir. Cuando la noche es fría y el camino es
+largo, caminamos juntos a casa y las estrellas brillan en tus ojos. Dime lo que quieres, dime lo que
+necesitas, te daría todo lo que tengo. No hay nada en el mundo que pueda quitarnos este amor. Cada
+vez que pienso en la forma en que me miraste, mi corazón late más rápido que nunca. Hemos soñado con
+el verano, con el mar y con el sol, y ahora los días son más cortos pero todavía creo en ti. Toma mi
+mano y no tengas miedo, porque esta noche el mundo entero nos pertenece a los dos y nada más
+importa. Ella dijo que iban a la ciudad con sus amigos, pero él estaba esperando junto a la ventana.`,
+	"it": `So che sei quella giusta per me e non ti lascerò mai andare. Quando la notte è fredda e la
+strada è lunga, camminiamo insieme verso casa e le stelle brillano nei tuoi occhi. Dimmi cosa vuoi,
+dimmi di cosa hai bisogno, ti darei tutto quello che ho. Non c'è niente al mondo che potrebbe
+toglierci questo amore. Ogni volta che penso al modo in cui mi hai guardato, il mio cuore batte più
+forte che mai. Abbiamo sognato l'estate, il mare e il sole, e adesso i giorni si accorciano ma io
+credo ancora in te. Prendi la mia mano e non avere paura, perché stanotte il mondo intero
+appartiene a noi due e nient'altro conta. Lei ha detto che andavano in città con i loro amici, ma
+lui aspettava vicino alla finestra.`,
+	"pt": `Eu sei que você é a única para mim e nunca vou deixar você ir. Quando a noite é fria e o
+caminho é longo, caminhamos juntos para casa e as estrelas brilham nos seus olhos. Diga o que você
+quer, diga do que você precisa, eu daria tudo o que tenho. Não há nada no mundo que possa tirar
+este amor de nós. Cada vez que penso no jeito que você olhou para mim, meu coração bate mais rápido
+do que nunca. Sonhamos com o verão, com o mar e com o sol, e agora os dias estão ficando mais curtos
+mas eu ainda acredito em você. Segure a minha mão e não tenha medo, porque esta noite o mundo
+inteiro pertence a nós dois e nada mais importa. Ela disse que eles iam para a cidade com os seus
+amigos, mas ele estava esperando perto da janela.`,
+	"nl": `Ik weet dat jij de ware voor mij bent en ik zal je nooit laten gaan. Als de nacht koud is en
+de weg lang, lopen we samen naar huis en schitteren de sterren in je ogen. Zeg me wat je wilt, zeg me
+wat je nodig hebt, ik zou je alles geven wat ik heb. Er is niets in de wereld dat deze liefde van
+ons kan afnemen. Elke keer als ik denk aan de manier waarop je naar me keek, klopt mijn hart sneller
+dan ooit tevoren. We hebben gedroomd van de zomer, van de zee en van de zon, en nu worden de dagen
+korter maar ik geloof nog steeds in jou. Houd mijn hand vast en wees niet bang, want vannacht is de
+hele wereld van ons tweeën en niets anders doet er nog toe. Ze zei dat ze met hun vrienden naar de
+stad gingen, maar hij wachtte bij het raam.`,
+	"pl": `Wiem, że jesteś jedyną dla mnie i nigdy nie pozwolę ci odejść. Kiedy noc jest zimna, a droga
+długa, idziemy razem do domu i gwiazdy świecą w twoich oczach. Powiedz mi, czego chcesz, powiedz
+mi, czego potrzebujesz, dałbym ci wszystko, co mam. Nie ma nic na świecie, co mogłoby nam odebrać
+tę miłość. Za każdym razem, gdy myślę o tym, jak na mnie spojrzałaś, moje serce bije szybciej niż
+kiedykolwiek. Marzyliśmy o lecie, o morzu i o słońcu, a teraz dni są coraz krótsze, ale wciąż w
+ciebie wierzę. Trzymaj mnie za rękę i nie bój się, bo dziś w nocy cały świat należy do nas dwojga i
+nic innego się nie liczy. Powiedziała, że jadą do miasta ze swoimi przyjaciółmi, ale on czekał przy
+oknie.`,
+	"ru": `Я знаю, что ты единственная для меня, и я никогда тебя не отпущу. Когда ночь холодна и
+дорога длинна, мы идём вместе домой, и звёзды сияют в твоих глазах. Скажи мне, чего ты хочешь,
+скажи, что тебе нужно, я отдал бы тебе всё, что у меня есть. Нет ничего на свете, что могло бы
+отнять у нас эту любовь. Каждый раз, когда я думаю о том, как ты на меня смотрела, моё сердце
+бьётся быстрее, чем когда-либо. Мы мечтали о лете, о море и о солнце, а теперь дни становятся
+короче, но я всё ещё верю в тебя. Держи меня за руку и не бойся, потому что этой ночью весь мир
+принадлежит только нам двоим и больше ничего не важно. Она сказала, что они поедут в город со
+своими друзьями, но он ждал у окна.`,
+	"uk": `Я знаю, що ти єдина для мене, і я ніколи тебе не відпущу. Коли ніч холодна і дорога довга,
+ми йдемо разом додому, і зорі сяють у твоїх очах. Скажи мені, чого ти хочеш, скажи, що тобі
+потрібно, я віддав би тобі все, що маю. Немає нічого на світі, що могло б відібрати в нас це
+кохання. Щоразу, коли я думаю про те, як ти на мене дивилася, моє серце б'ється швидше, ніж
+будь-коли. Ми мріяли про літо, про море і про сонце, а тепер дні стають коротшими, але я все ще
+вірю в тебе. Тримай мене за руку і не бійся, бо цієї ночі весь світ належить тільки нам двом і
+більше нічого не важливо. Вона сказала, що вони поїдуть до міста зі своїми друзями, але він чекав
+біля вікна.`,
+	"be": `Я ведаю, што ты адзіная для мяне, і я ніколі цябе не адпушчу. Калі ноч халодная і дарога
+доўгая, мы ідзём разам дадому, і зоркі ззяюць у тваіх вачах. Скажы мне, чаго ты хочаш, скажы, што
+табе трэба, я аддаў бы табе ўсё, што маю. Няма нічога на свеце, што магло б адабраць у нас гэтае
+каханне. Кожны раз, калі я думаю пра тое, як ты на мяне глядзела, маё сэрца б'ецца хутчэй, чым
+калі-небудзь. Мы марылі пра лета, пра мора і пра сонца, а цяпер дні становяцца карацейшымі, але я
+ўсё яшчэ веру ў цябе. Трымай мяне за руку і не бойся, бо гэтай ноччу ўвесь свет належыць толькі нам
+дваім. Яна сказала, што яны паедуць у горад са сваімі сябрамі, але ён чакаў ля акна.`,
+	"bg": `Знам, че ти си единствената за мен и никога няма да те пусна. Когато нощта е студена и
+пътят е дълъг, вървим заедно към вкъщи и звездите блестят в очите ти. Кажи ми какво искаш, кажи ми
+от какво имаш нужда, бих ти дал всичко, което имам. Няма нищо на света, което да ни отнеме тази
+любов. Всеки път, когато си помисля как ме погледна, сърцето ми бие по-бързо от всякога. Мечтахме
+за лятото, за морето и за слънцето, а сега дните стават по-къси, но аз все още вярвам в теб. Хвани
+ръката ми и не се страхувай, защото тази нощ целият свят принадлежи само на нас двамата и нищо
+друго няма значение. Тя каза, че те отиват в града с приятелите си, но той чакаше до прозореца.`,
+}
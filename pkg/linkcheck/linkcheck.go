@@ -0,0 +1,84 @@
+// Package linkcheck tells whether links still work. A link is requested
+// with HEAD first, and with GET when the server fails the HEAD request or
+// refuses it, as many do; GET bodies are read up to a small limit only.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxBody is the most of a GET response body read before it is dropped.
+const maxBody = 64 << 10
+
+// Result is the outcome of checking a link. RedirectURL is where the link
+// ended up after redirects, empty when it was not redirected. Err is set
+// when no response was received, in which case StatusCode is 0.
+type Result struct {
+	StatusCode  int
+	RedirectURL string
+	Err         error
+}
+
+// Broken reports whether the link failed or answered with an error status.
+func (r Result) Broken() bool {
+	return r.Err != nil || r.StatusCode >= http.StatusBadRequest
+}
+
+type Checker struct {
+	client *http.Client
+}
+
+// New creates a checker sending requests with client, following at most
+// maxRedirects redirects. The client is copied, so its own redirect
+// policy is left alone.
+func New(client *http.Client, maxRedirects int) *Checker {
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+	return &Checker{client: &c}
+}
+
+// Check requests the link and reports how it answered. Failures to get an
+// answer are reported in the result rather than returned.
+func (c *Checker) Check(ctx context.Context, rawURL string) Result {
+	res := c.do(ctx, http.MethodHead, rawURL)
+	if ctx.Err() != nil || !res.Broken() {
+		return res
+	}
+	return c.do(ctx, http.MethodGet, rawURL)
+}
+
+func (c *Checker) do(ctx context.Context, method, rawURL string) Result {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("User-Agent", "library-music-linkcheck/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The url.Error repeats the method and link, which the caller knows.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return Result{Err: err}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBody))
+
+	res := Result{StatusCode: resp.StatusCode}
+	if final := resp.Request.URL.String(); final != rawURL {
+		res.RedirectURL = final
+	}
+	return res
+}
@@ -0,0 +1,294 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// methods records the methods of the requests a test server received.
+type methods struct {
+	mu  sync.Mutex
+	got []string
+}
+
+func (m *methods) add(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.got = append(m.got, method)
+}
+
+func (m *methods) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return strings.Join(m.got, ",")
+}
+
+func TestCheckMethods(t *testing.T) {
+	tests := []struct {
+		name    string
+		head    int
+		get     int
+		status  int
+		methods string
+	}{
+		{name: "head ok", head: http.StatusOK, get: http.StatusOK, status: http.StatusOK, methods: "HEAD"},
+		{name: "head not allowed", head: http.StatusMethodNotAllowed, get: http.StatusOK, status: http.StatusOK, methods: "HEAD,GET"},
+		{name: "head failing", head: http.StatusInternalServerError, get: http.StatusOK, status: http.StatusOK, methods: "HEAD,GET"},
+		{name: "missing", head: http.StatusNotFound, get: http.StatusNotFound, status: http.StatusNotFound, methods: "HEAD,GET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got methods
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.add(r.Method)
+				if r.Method == http.MethodHead {
+					w.WriteHeader(tt.head)
+					return
+				}
+				w.WriteHeader(tt.get)
+			}))
+			defer srv.Close()
+
+			res := New(srv.Client(), 5).Check(context.Background(), srv.URL)
+			if res.Err != nil {
+				t.Fatalf("Check() error = %v", res.Err)
+			}
+			if res.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", res.StatusCode, tt.status)
+			}
+			if res.RedirectURL != "" {
+				t.Errorf("RedirectURL = %q, want none", res.RedirectURL)
+			}
+			if got.String() != tt.methods {
+				t.Errorf("methods = %s, want %s", got.String(), tt.methods)
+			}
+		})
+	}
+}
+
+// redirects serves /hop/n, redirecting to /hop/n-1 down to /hop/0.
+func redirects() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if n > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/hop/%d", n-1), http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestCheckRedirects(t *testing.T) {
+	srv := redirects()
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		hops     int
+		redirect string
+		wantErr  bool
+	}{
+		{name: "not redirected", hops: 0},
+		{name: "redirected", hops: 1, redirect: srv.URL + "/hop/0"},
+		{name: "at the limit", hops: 3, redirect: srv.URL + "/hop/0"},
+		{name: "over the limit", hops: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := New(srv.Client(), 3).Check(context.Background(), fmt.Sprintf("%s/hop/%d", srv.URL, tt.hops))
+			if tt.wantErr {
+				if res.Err == nil || !strings.Contains(res.Err.Error(), "stopped after 3 redirects") {
+					t.Fatalf("Check() error = %v, want the redirect limit", res.Err)
+				}
+				if !res.Broken() {
+					t.Error("Broken() = false, want true")
+				}
+				return
+			}
+			if res.Err != nil {
+				t.Fatalf("Check() error = %v", res.Err)
+			}
+			if res.StatusCode != http.StatusOK {
+				t.Errorf("StatusCode = %d, want %d", res.StatusCode, http.StatusOK)
+			}
+			if res.RedirectURL != tt.redirect {
+				t.Errorf("RedirectURL = %q, want %q", res.RedirectURL, tt.redirect)
+			}
+		})
+	}
+}
+
+func TestNewLeavesClientAlone(t *testing.T) {
+	client := &http.Client{}
+	New(client, 1)
+	if client.CheckRedirect != nil {
+		t.Error("New() changed the redirect policy of the client")
+	}
+}
+
+// endless is a body that never ends, counting the bytes read from it.
+type endless struct {
+	mu   sync.Mutex
+	read int
+}
+
+func (b *endless) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range p {
+		p[i] = 'x'
+	}
+	b.read += len(p)
+	return len(p), nil
+}
+
+func (b *endless) Close() error { return nil }
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestCheckBodyLimit(t *testing.T) {
+	body := &endless{}
+	client := &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}
+		switch req.Method {
+		case http.MethodHead:
+			resp.StatusCode = http.StatusMethodNotAllowed
+		case http.MethodGet:
+			resp.Body = body
+		}
+		return resp, nil
+	})}
+
+	res := New(client, 5).Check(context.Background(), "http://example.com/big")
+	if res.Err != nil || res.StatusCode != http.StatusOK {
+		t.Fatalf("Check() = %+v, want 200", res)
+	}
+	if body.read != maxBody {
+		t.Errorf("read %d bytes of the body, want %d", body.read, maxBody)
+	}
+}
+
+func TestCheckBodyLimitServer(t *testing.T) {
+	// The server would write far more than the limit; the check must not
+	// wait for all of it.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		chunk := strings.Repeat("x", 32<<10)
+		for range 64 << 10 {
+			if _, err := io.WriteString(w, chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res := New(srv.Client(), 5).Check(ctx, srv.URL)
+	if res.Err != nil || res.StatusCode != http.StatusOK {
+		t.Fatalf("Check() = %+v, want 200", res)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	t.Run("context", func(t *testing.T) {
+		var got methods
+		client := srv.Client()
+		transport := client.Transport
+		client.Transport = roundTripper(func(req *http.Request) (*http.Response, error) {
+			got.add(req.Method)
+			return transport.RoundTrip(req)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		res := New(client, 5).Check(ctx, srv.URL)
+		if !errors.Is(res.Err, context.DeadlineExceeded) {
+			t.Fatalf("Check() error = %v, want %v", res.Err, context.DeadlineExceeded)
+		}
+		if res.StatusCode != 0 {
+			t.Errorf("StatusCode = %d, want 0", res.StatusCode)
+		}
+		// A timed out HEAD is not retried with GET.
+		if got.String() != "HEAD" {
+			t.Errorf("methods = %s, want HEAD", got.String())
+		}
+	})
+
+	t.Run("client", func(t *testing.T) {
+		client := srv.Client()
+		client.Timeout = 50 * time.Millisecond
+		res := New(client, 5).Check(context.Background(), srv.URL)
+		if res.Err == nil {
+			t.Fatal("Check() error = nil, want a timeout")
+		}
+		if !res.Broken() {
+			t.Error("Broken() = false, want true")
+		}
+	})
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	res := New(http.DefaultClient, 5).Check(context.Background(), url)
+	if res.Err == nil {
+		t.Fatal("Check() error = nil, want a connection error")
+	}
+	if strings.Contains(res.Err.Error(), url) {
+		t.Errorf("error %q repeats the link", res.Err)
+	}
+}
+
+func TestResultBroken(t *testing.T) {
+	tests := []struct {
+		res  Result
+		want bool
+	}{
+		{res: Result{StatusCode: http.StatusOK}, want: false},
+		{res: Result{StatusCode: http.StatusFound}, want: false},
+		{res: Result{StatusCode: http.StatusBadRequest}, want: true},
+		{res: Result{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{res: Result{Err: errors.New("refused")}, want: true},
+	}
+	for _, tt := range tests {
+		if got := tt.res.Broken(); got != tt.want {
+			t.Errorf("%+v.Broken() = %v, want %v", tt.res, got, tt.want)
+		}
+	}
+}
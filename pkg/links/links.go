@@ -0,0 +1,175 @@
+// Package links validates links to songs on streaming and lyrics sites,
+// tells which provider a link points to and extracts the id the provider
+// gives the song, such as the video id of a YouTube link.
+package links
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Providers a link can point to. Lyrics is any site hosting the lyrics;
+// Other is any site not known to the package.
+const (
+	YouTube    = "youtube"
+	Spotify    = "spotify"
+	AppleMusic = "apple_music"
+	Bandcamp   = "bandcamp"
+	Lyrics     = "lyrics"
+	Other      = "other"
+)
+
+var (
+	ErrInvalidURL       = errors.New("invalid link")
+	ErrProviderMismatch = errors.New("link does not point to the provider")
+	ErrNoProviderID     = errors.New("link does not point to a song")
+)
+
+// Link is a parsed link. ProviderID is empty for providers without ids.
+type Link struct {
+	Provider   string
+	URL        string
+	ProviderID string
+}
+
+var (
+	youtubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	spotifyID = regexp.MustCompile(`^[A-Za-z0-9]{22}$`)
+	appleID   = regexp.MustCompile(`^\d+$`)
+	slug      = regexp.MustCompile(`^[a-z0-9-]+$`)
+)
+
+// Parse validates raw as an absolute http or https link and extracts the
+// id of the song from it. An empty provider is detected from the host;
+// otherwise the host must belong to provider, except for Lyrics and Other
+// which accept any host.
+func Parse(raw, provider string) (Link, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return Link{}, ErrInvalidURL
+	}
+
+	detected := Detect(u.Hostname())
+	if provider == "" {
+		provider = detected
+	}
+	if provider != Lyrics && provider != Other && provider != detected {
+		return Link{}, ErrProviderMismatch
+	}
+
+	link := Link{Provider: provider, URL: u.String()}
+	switch provider {
+	case YouTube:
+		link.ProviderID = youtubeVideo(u)
+	case Spotify:
+		link.ProviderID = spotifyTrack(u)
+	case AppleMusic:
+		link.ProviderID = appleSong(u)
+	case Bandcamp:
+		link.ProviderID = bandcampTrack(u)
+	default:
+		return link, nil
+	}
+
+	if link.ProviderID == "" {
+		return Link{}, ErrNoProviderID
+	}
+	return link, nil
+}
+
+// Detect returns the provider serving host, or Other.
+func Detect(host string) string {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	switch {
+	case host == "youtube.com" || host == "m.youtube.com" || host == "music.youtube.com" || host == "youtu.be":
+		return YouTube
+	case host == "open.spotify.com":
+		return Spotify
+	case host == "music.apple.com":
+		return AppleMusic
+	case host == "bandcamp.com" || strings.HasSuffix(host, ".bandcamp.com"):
+		return Bandcamp
+	default:
+		return Other
+	}
+}
+
+// youtubeVideo reads the video id of watch, short, embed and youtu.be links.
+func youtubeVideo(u *url.URL) string {
+	segments := pathSegments(u)
+	var id string
+	switch {
+	case strings.EqualFold(u.Hostname(), "youtu.be") && len(segments) > 0:
+		id = segments[0]
+	case len(segments) == 1 && segments[0] == "watch":
+		id = u.Query().Get("v")
+	case len(segments) == 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live"):
+		id = segments[1]
+	}
+
+	if !youtubeID.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// spotifyTrack reads the track id of open.spotify.com/track/<id> links,
+// also with a locale prefix such as /intl-de.
+func spotifyTrack(u *url.URL) string {
+	segments := pathSegments(u)
+	if len(segments) > 0 && strings.HasPrefix(segments[0], "intl-") {
+		segments = segments[1:]
+	}
+
+	if len(segments) != 2 || segments[0] != "track" || !spotifyID.MatchString(segments[1]) {
+		return ""
+	}
+	return segments[1]
+}
+
+// appleSong reads the song id of Apple Music links, given by the i
+// parameter of album links or last in the path of song links.
+func appleSong(u *url.URL) string {
+	if id := u.Query().Get("i"); appleID.MatchString(id) {
+		return id
+	}
+
+	segments := pathSegments(u)
+	if len(segments) < 2 || !slices.Contains(segments[:len(segments)-1], "song") {
+		return ""
+	}
+
+	if id := segments[len(segments)-1]; appleID.MatchString(id) {
+		return id
+	}
+	return ""
+}
+
+// bandcampTrack identifies a track by its artist subdomain and slug, as
+// Bandcamp links carry no numeric id: "artist.bandcamp.com/track/song"
+// has the id "artist/song".
+func bandcampTrack(u *url.URL) string {
+	artist, ok := strings.CutSuffix(strings.ToLower(u.Hostname()), ".bandcamp.com")
+	if !ok || artist == "www" {
+		return ""
+	}
+
+	segments := pathSegments(u)
+	if len(segments) != 2 || segments[0] != "track" || !slug.MatchString(segments[1]) {
+		return ""
+	}
+	return artist + "/" + segments[1]
+}
+
+func pathSegments(u *url.URL) []string {
+	var segments []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
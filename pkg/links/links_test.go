@@ -0,0 +1,213 @@
+package links
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		provider string
+		want     Link
+		err      error
+	}{
+		{
+			name: "youtube detected",
+			raw:  " https://www.youtube.com/watch?v=dQw4w9WgXcQ ",
+			want: Link{Provider: YouTube, URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", ProviderID: "dQw4w9WgXcQ"},
+		},
+		{
+			name:     "provider given",
+			raw:      "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC",
+			provider: Spotify,
+			want:     Link{Provider: Spotify, URL: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", ProviderID: "4uLU6hMCjMI75M1A2tKUQC"},
+		},
+		{
+			name: "other site",
+			raw:  "http://example.com/song",
+			want: Link{Provider: Other, URL: "http://example.com/song"},
+		},
+		{
+			name:     "lyrics on any site",
+			raw:      "https://genius.com/Queen-bohemian-rhapsody-lyrics",
+			provider: Lyrics,
+			want:     Link{Provider: Lyrics, URL: "https://genius.com/Queen-bohemian-rhapsody-lyrics"},
+		},
+		{
+			name:     "lyrics on a provider site",
+			raw:      "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			provider: Lyrics,
+			want:     Link{Provider: Lyrics, URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{name: "ftp scheme", raw: "ftp://youtube.com/watch?v=dQw4w9WgXcQ", err: ErrInvalidURL},
+		{name: "javascript scheme", raw: "javascript:alert(1)", err: ErrInvalidURL},
+		{name: "no scheme", raw: "youtube.com/watch?v=dQw4w9WgXcQ", err: ErrInvalidURL},
+		{name: "no host", raw: "https:///watch?v=dQw4w9WgXcQ", err: ErrInvalidURL},
+		{name: "not a link", raw: "https://exa mple.com", err: ErrInvalidURL},
+		{name: "empty", raw: "", err: ErrInvalidURL},
+		{
+			name:     "provider mismatch",
+			raw:      "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			provider: Spotify,
+			err:      ErrProviderMismatch,
+		},
+		{
+			name:     "lookalike host",
+			raw:      "https://youtube.com.evil.example/watch?v=dQw4w9WgXcQ",
+			provider: YouTube,
+			err:      ErrProviderMismatch,
+		},
+		{name: "youtube channel", raw: "https://www.youtube.com/@queen", err: ErrNoProviderID},
+		{name: "spotify album", raw: "https://open.spotify.com/album/6i6folBtxKV28WX3msQ4FE", err: ErrNoProviderID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw, tt.provider)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "youtube.com", want: YouTube},
+		{host: "WWW.YouTube.com", want: YouTube},
+		{host: "m.youtube.com", want: YouTube},
+		{host: "music.youtube.com", want: YouTube},
+		{host: "youtu.be", want: YouTube},
+		{host: "open.spotify.com", want: Spotify},
+		{host: "spotify.com", want: Other},
+		{host: "music.apple.com", want: AppleMusic},
+		{host: "apple.com", want: Other},
+		{host: "bandcamp.com", want: Bandcamp},
+		{host: "artist.bandcamp.com", want: Bandcamp},
+		{host: "notbandcamp.com", want: Other},
+		{host: "youtube.com.example.org", want: Other},
+		{host: "", want: Other},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.host); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestYoutubeVideo(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", want: "dQw4w9WgXcQ"},
+		{raw: "https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RD", want: "dQw4w9WgXcQ"},
+		{raw: "https://youtu.be/dQw4w9WgXcQ", want: "dQw4w9WgXcQ"},
+		{raw: "https://youtu.be/dQw4w9WgXcQ?t=42", want: "dQw4w9WgXcQ"},
+		{raw: "https://YOUTU.BE/dQw4w9WgXcQ", want: "dQw4w9WgXcQ"},
+		{raw: "https://www.youtube.com/shorts/a_b-c1D2e3F", want: "a_b-c1D2e3F"},
+		{raw: "https://www.youtube.com/embed/dQw4w9WgXcQ", want: "dQw4w9WgXcQ"},
+		{raw: "https://www.youtube.com/live/dQw4w9WgXcQ", want: "dQw4w9WgXcQ"},
+		{raw: "https://www.youtube.com/watch", want: ""},
+		{raw: "https://www.youtube.com/watch?v=short", want: ""},
+		{raw: "https://www.youtube.com/watch?v=dQw4w9WgXcQ!", want: ""},
+		{raw: "https://www.youtube.com/shorts/", want: ""},
+		{raw: "https://www.youtube.com/shorts/dQw4w9WgXcQ/extra", want: ""},
+		{raw: "https://www.youtube.com/dQw4w9WgXcQ", want: ""},
+		{raw: "https://youtu.be/", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := youtubeVideo(mustParse(t, tt.raw)); got != tt.want {
+			t.Errorf("youtubeVideo(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestSpotifyTrack(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", want: "4uLU6hMCjMI75M1A2tKUQC"},
+		{raw: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=abc", want: "4uLU6hMCjMI75M1A2tKUQC"},
+		{raw: "https://open.spotify.com/intl-de/track/4uLU6hMCjMI75M1A2tKUQC", want: "4uLU6hMCjMI75M1A2tKUQC"},
+		{raw: "https://open.spotify.com/intl-pt/track/4uLU6hMCjMI75M1A2tKUQC/", want: "4uLU6hMCjMI75M1A2tKUQC"},
+		{raw: "https://open.spotify.com/de/track/4uLU6hMCjMI75M1A2tKUQC", want: ""},
+		{raw: "https://open.spotify.com/intl-de/intl-fr/track/4uLU6hMCjMI75M1A2tKUQC", want: ""},
+		{raw: "https://open.spotify.com/album/4uLU6hMCjMI75M1A2tKUQC", want: ""},
+		{raw: "https://open.spotify.com/track/tooshort", want: ""},
+		{raw: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC/extra", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := spotifyTrack(mustParse(t, tt.raw)); got != tt.want {
+			t.Errorf("spotifyTrack(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestAppleSong(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "https://music.apple.com/us/album/bohemian-rhapsody/1440650428?i=1440650711", want: "1440650711"},
+		{raw: "https://music.apple.com/us/song/bohemian-rhapsody/1440650711", want: "1440650711"},
+		{raw: "https://music.apple.com/us/song/1440650711", want: "1440650711"},
+		// The i parameter wins over the path.
+		{raw: "https://music.apple.com/us/song/bohemian-rhapsody/1440650711?i=42", want: "42"},
+		{raw: "https://music.apple.com/us/album/bohemian-rhapsody/1440650428?i=abc", want: ""},
+		{raw: "https://music.apple.com/us/album/bohemian-rhapsody/1440650428", want: ""},
+		{raw: "https://music.apple.com/us/song/bohemian-rhapsody", want: ""},
+		{raw: "https://music.apple.com/song", want: ""},
+		{raw: "https://music.apple.com/us/artist/queen/3296287", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := appleSong(mustParse(t, tt.raw)); got != tt.want {
+			t.Errorf("appleSong(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestBandcampTrack(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "https://artist.bandcamp.com/track/some-song", want: "artist/some-song"},
+		{raw: "https://Artist.Bandcamp.com/track/some-song", want: "artist/some-song"},
+		{raw: "https://the-band.bandcamp.com/track/song-2?from=search", want: "the-band/song-2"},
+		{raw: "https://bandcamp.com/track/some-song", want: ""},
+		{raw: "https://www.bandcamp.com/track/some-song", want: ""},
+		{raw: "https://artist.bandcamp.com/album/some-album", want: ""},
+		{raw: "https://artist.bandcamp.com/track/Some_Song", want: ""},
+		{raw: "https://artist.bandcamp.com/", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := bandcampTrack(mustParse(t, tt.raw)); got != tt.want {
+			t.Errorf("bandcampTrack(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", raw, err)
+	}
+	return u
+}
@@ -0,0 +1,273 @@
+// Package lrc reads and writes time-synchronized lyrics in the LRC format,
+// including the enhanced variant with word-level "<mm:ss.xx>" timestamps,
+// and renders them as WebVTT.
+package lrc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrNoTimedLines = errors.New("no timed lines")
+
+// DefaultCueDuration is how long the last line stays on screen in WebVTT,
+// since LRC only records when a line starts.
+const DefaultCueDuration = 5 * time.Second
+
+type Word struct {
+	Start time.Duration
+	Text  string
+}
+
+type Line struct {
+	Start time.Duration
+	Text  string
+	// Words is set for enhanced LRC lines only.
+	Words []Word
+}
+
+type Lyrics struct {
+	Title  string
+	Artist string
+	Album  string
+	Lines  []Line
+}
+
+var (
+	tag       = regexp.MustCompile(`^\[([^\]]*)\]`)
+	timestamp = regexp.MustCompile(`^(\d+):(\d{1,2})(?:[.:](\d{1,3}))?$`)
+	wordStamp = regexp.MustCompile(`<(\d+:\d{1,2}(?:[.:]\d{1,3})?)>`)
+)
+
+// Parse reads LRC text. Lines carrying several timestamps are repeated at
+// each of them, the offset tag is applied and lines are sorted by time.
+// Unknown tags and untimed lines are ignored.
+func Parse(text string) (Lyrics, error) {
+	var lyrics Lyrics
+	var offset time.Duration
+	for n, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		rest := strings.TrimSpace(raw)
+		var starts []time.Duration
+		for {
+			m := tag.FindStringSubmatch(rest)
+			if m == nil {
+				break
+			}
+			rest = rest[len(m[0]):]
+
+			if timestamp.MatchString(m[1]) {
+				start, err := parseTimestamp(m[1])
+				if err != nil {
+					return Lyrics{}, fmt.Errorf("line %d: %w", n+1, err)
+				}
+				starts = append(starts, start)
+				continue
+			}
+
+			key, value, ok := strings.Cut(m[1], ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "ti":
+				lyrics.Title = value
+			case "ar":
+				lyrics.Artist = value
+			case "al":
+				lyrics.Album = value
+			case "offset":
+				ms, err := strconv.Atoi(value)
+				if err != nil {
+					return Lyrics{}, fmt.Errorf("line %d: invalid offset %q", n+1, value)
+				}
+				offset = time.Duration(ms) * time.Millisecond
+			}
+		}
+
+		if len(starts) == 0 {
+			continue
+		}
+
+		text, words, err := parseWords(rest)
+		if err != nil {
+			return Lyrics{}, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		for _, start := range starts {
+			line := Line{Start: start, Text: text}
+			// Word timestamps are absolute, so they only fit a line
+			// that is sung once.
+			if len(starts) == 1 {
+				line.Words = words
+			}
+			lyrics.Lines = append(lyrics.Lines, line)
+		}
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return Lyrics{}, ErrNoTimedLines
+	}
+
+	// A positive offset makes the lyrics appear sooner.
+	for i := range lyrics.Lines {
+		lyrics.Lines[i].Start = shift(lyrics.Lines[i].Start, offset)
+		for j := range lyrics.Lines[i].Words {
+			lyrics.Lines[i].Words[j].Start = shift(lyrics.Lines[i].Words[j].Start, offset)
+		}
+	}
+
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].Start < lyrics.Lines[j].Start
+	})
+	return lyrics, nil
+}
+
+// parseWords splits an enhanced LRC line into its timed words. Text before
+// the first word timestamp belongs to the line but is not timed.
+func parseWords(rest string) (string, []Word, error) {
+	marks := wordStamp.FindAllStringSubmatchIndex(rest, -1)
+	if marks == nil {
+		return strings.TrimSpace(rest), nil, nil
+	}
+
+	var words []Word
+	for i, m := range marks {
+		start, err := parseTimestamp(rest[m[2]:m[3]])
+		if err != nil {
+			return "", nil, err
+		}
+
+		end := len(rest)
+		if i+1 < len(marks) {
+			end = marks[i+1][0]
+		}
+		if word := rest[m[1]:end]; strings.TrimSpace(word) != "" {
+			words = append(words, Word{Start: start, Text: word})
+		}
+	}
+	return strings.TrimSpace(wordStamp.ReplaceAllString(rest, "")), words, nil
+}
+
+func parseTimestamp(s string) (time.Duration, error) {
+	m := timestamp.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	minutes, _ := strconv.Atoi(m[1])
+	seconds, _ := strconv.Atoi(m[2])
+	if seconds >= 60 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	var fraction time.Duration
+	if m[3] != "" {
+		// Two digits are hundredths, three are milliseconds.
+		n, _ := strconv.Atoi(m[3])
+		for i := len(m[3]); i < 3; i++ {
+			n *= 10
+		}
+		fraction = time.Duration(n) * time.Millisecond
+	}
+	return time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second + fraction, nil
+}
+
+func shift(d, offset time.Duration) time.Duration {
+	return max(d-offset, 0)
+}
+
+// String renders the lyrics as LRC, using word timestamps where known.
+func (l Lyrics) String() string {
+	var b strings.Builder
+	for _, t := range [][2]string{{"ti", l.Title}, {"ar", l.Artist}, {"al", l.Album}} {
+		if t[1] != "" {
+			fmt.Fprintf(&b, "[%s:%s]\n", t[0], t[1])
+		}
+	}
+
+	for _, line := range l.Lines {
+		fmt.Fprintf(&b, "[%s]", formatLRC(line.Start))
+		if len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		} else {
+			b.WriteString(line.lead())
+		}
+		for _, w := range line.Words {
+			fmt.Fprintf(&b, "<%s>%s", formatLRC(w.Start), w.Text)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// VTT renders the lyrics as WebVTT. Each line is shown until the next one
+// starts; empty lines only end the previous cue. Word timings become
+// WebVTT cue timestamps, which players use for karaoke-style highlighting.
+func (l Lyrics) VTT() string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	for i, line := range l.Lines {
+		if line.Text == "" {
+			continue
+		}
+
+		end := line.Start + DefaultCueDuration
+		if i+1 < len(l.Lines) {
+			end = l.Lines[i+1].Start
+		}
+
+		fmt.Fprintf(&b, "\n%s --> %s\n", formatVTT(line.Start), formatVTT(end))
+		if len(line.Words) == 0 {
+			b.WriteString(vttEscaper.Replace(line.Text))
+		}
+		lead := line.lead()
+		b.WriteString(vttEscaper.Replace(lead))
+		for j, w := range line.Words {
+			if j > 0 || lead != "" {
+				fmt.Fprintf(&b, "<%s>", formatVTT(w.Start))
+			}
+			b.WriteString(vttEscaper.Replace(w.Text))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// vttEscaper escapes the characters WebVTT cue text reserves for markup.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// lead returns the untimed text before the first timed word of an
+// enhanced line. It is kept in Text only, which ends with the words.
+func (l Line) lead() string {
+	if len(l.Words) == 0 {
+		return ""
+	}
+
+	var words strings.Builder
+	for _, w := range l.Words {
+		words.WriteString(w.Text)
+	}
+	timed := strings.TrimRightFunc(words.String(), unicode.IsSpace)
+	if lead, ok := strings.CutSuffix(l.Text, timed); ok {
+		return lead
+	}
+	return ""
+}
+
+func formatLRC(d time.Duration) string {
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%02d:%02d.%02d", cs/6000, cs/100%60, cs%100)
+}
+
+func formatVTT(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
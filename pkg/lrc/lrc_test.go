@@ -0,0 +1,147 @@
+package lrc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration {
+	return time.Duration(n) * time.Millisecond
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Lyrics
+		err  bool
+	}{
+		{
+			name: "tags and lines",
+			text: "[ti:Song]\n[ar: Artist ]\n[al:Album]\n[00:01.50]First\r\n[00:03.250]Second\n",
+			want: Lyrics{Title: "Song", Artist: "Artist", Album: "Album", Lines: []Line{
+				{Start: ms(1500), Text: "First"},
+				{Start: ms(3250), Text: "Second"},
+			}},
+		},
+		{
+			name: "repeated line sorted by time",
+			text: "[00:10.00][00:02.00]Chorus\n[00:05]Verse\n",
+			want: Lyrics{Lines: []Line{
+				{Start: ms(2000), Text: "Chorus"},
+				{Start: ms(5000), Text: "Verse"},
+				{Start: ms(10000), Text: "Chorus"},
+			}},
+		},
+		{
+			name: "offset moves lines sooner without going negative",
+			text: "[offset:+500]\n[00:00.20]Early\n[00:01.00]Late\n",
+			want: Lyrics{Lines: []Line{
+				{Start: 0, Text: "Early"},
+				{Start: ms(500), Text: "Late"},
+			}},
+		},
+		{
+			name: "untimed lines and unknown tags ignored",
+			text: "plain text\n[by:someone]\n[00:01.00]Line\n",
+			want: Lyrics{Lines: []Line{{Start: ms(1000), Text: "Line"}}},
+		},
+		{
+			name: "empty timed line kept",
+			text: "[00:01.00]Line\n[00:02.00]\n",
+			want: Lyrics{Lines: []Line{{Start: ms(1000), Text: "Line"}, {Start: ms(2000)}}},
+		},
+		{
+			name: "enhanced line with untimed lead",
+			text: "[00:01.00]Oh <00:01.50>hello <00:02.00>there\n",
+			want: Lyrics{Lines: []Line{{
+				Start: ms(1000),
+				Text:  "Oh hello there",
+				Words: []Word{{Start: ms(1500), Text: "hello "}, {Start: ms(2000), Text: "there"}},
+			}}},
+		},
+		{
+			name: "word timings dropped on repeated lines",
+			text: "[00:01.00][00:05.00]<00:01.00>la <00:01.50>la\n",
+			want: Lyrics{Lines: []Line{
+				{Start: ms(1000), Text: "la la"},
+				{Start: ms(5000), Text: "la la"},
+			}},
+		},
+		{name: "seconds out of range", text: "[00:61.00]Line\n", err: true},
+		{name: "invalid word timestamp", text: "[00:01.00]<00:75.00>word\n", err: true},
+		{name: "invalid offset", text: "[offset:soon]\n[00:01.00]Line\n", err: true},
+		{name: "no timed lines", text: "[ti:Song]\njust text\n", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.text)
+			if (err != nil) != tt.err {
+				t.Fatalf("Parse() error = %v, want error %v", err, tt.err)
+			}
+			if !tt.err && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse("no timestamps"); !errors.Is(err, ErrNoTimedLines) {
+		t.Errorf("Parse() error = %v, want %v", err, ErrNoTimedLines)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	texts := []string{
+		"[ti:Song]\n[ar:Artist]\n[00:01.50]First\n[00:03.25]Second\n",
+		"[00:01.00]Oh <00:01.50>hello <00:02.00>there\n[00:04.00]<00:04.00>Rock <00:04.50>& <00:05.00>Roll\n",
+		"[01:02.03]<3\n[61:00.00]Very late\n",
+	}
+
+	for _, text := range texts {
+		parsed, err := Parse(text)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", text, err)
+		}
+		if got := parsed.String(); got != text {
+			t.Errorf("String() = %q, want %q", got, text)
+		}
+
+		again, err := Parse(parsed.String())
+		if err != nil {
+			t.Fatalf("Parse(String()) error = %v", err)
+		}
+		if !reflect.DeepEqual(again, parsed) {
+			t.Errorf("Parse(String()) = %+v, want %+v", again, parsed)
+		}
+	}
+}
+
+func TestVTT(t *testing.T) {
+	lyrics, err := Parse("[00:01.00]Rock & Roll <3\n[00:03.00]\n[00:04.00]Oh <00:04.50>a<b> <00:05.00>c\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "WEBVTT\n" +
+		"\n00:00:01.000 --> 00:00:03.000\nRock &amp; Roll &lt;3\n" +
+		"\n00:00:04.000 --> 00:00:09.000\nOh <00:00:04.500>a&lt;b&gt; <00:00:05.000>c\n"
+	if got := lyrics.VTT(); got != want {
+		t.Errorf("VTT() = %q, want %q", got, want)
+	}
+}
+
+func TestVTTWordsWithoutLead(t *testing.T) {
+	lyrics, err := Parse("[00:01.00]<00:01.00>one <00:02.00>two\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := lyrics.VTT()
+	if !strings.Contains(got, "\none <00:00:02.000>two\n") {
+		t.Errorf("VTT() = %q, want the first word untimed", got)
+	}
+}
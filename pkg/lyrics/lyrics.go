@@ -0,0 +1,124 @@
+// Package lyrics splits song text into typed sections.
+//
+// Sections are separated by blank lines. A section may start with a header
+// naming its type, such as "[Chorus]", "Verse 2:" or "(Bridge)"; the header
+// is kept as the section label so the text can be reassembled unchanged.
+// Unlabelled sections are verses, unless the same block appears more than
+// once, in which case it is taken to be the chorus.
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	TypeVerse  = "verse"
+	TypeChorus = "chorus"
+	TypeBridge = "bridge"
+	TypeIntro  = "intro"
+	TypeOutro  = "outro"
+)
+
+// Types lists the section types in the order they are documented.
+var Types = []string{TypeVerse, TypeChorus, TypeBridge, TypeIntro, TypeOutro}
+
+type Section struct {
+	Type string
+	// Ordinal numbers sections of the same type from 1.
+	Ordinal int
+	// Label is the header line the section was written with, if any.
+	Label string
+	Lines []string
+}
+
+var (
+	blankLines = regexp.MustCompile(`\n([ \t]*\n)+`)
+	header     = regexp.MustCompile(`^\s*[\[(]?\s*([A-Za-z][A-Za-z -]*?)\s*(\d+)?\s*[\])]?\s*:?\s*$`)
+)
+
+var aliases = map[string]string{
+	"verse":      TypeVerse,
+	"couplet":    TypeVerse,
+	"chorus":     TypeChorus,
+	"refrain":    TypeChorus,
+	"hook":       TypeChorus,
+	"pre-chorus": TypeBridge,
+	"prechorus":  TypeBridge,
+	"bridge":     TypeBridge,
+	"middle 8":   TypeBridge,
+	"intro":      TypeIntro,
+	"outro":      TypeOutro,
+	"coda":       TypeOutro,
+}
+
+// Parse splits text into sections. Line endings are normalized to "\n".
+func Parse(text string) []Section {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+
+	var sections []Section
+	seen := make(map[string]int)
+	for _, block := range blankLines.Split(text, -1) {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		section := Section{Lines: lines}
+		if typ, ok := headerType(lines[0]); ok {
+			section.Type = typ
+			section.Label = strings.TrimSpace(lines[0])
+			section.Lines = lines[1:]
+		}
+		if len(section.Lines) == 0 && section.Label == "" {
+			continue
+		}
+		seen[strings.Join(section.Lines, "\n")]++
+		sections = append(sections, section)
+	}
+
+	ordinals := make(map[string]int)
+	for i := range sections {
+		s := &sections[i]
+		if s.Type == "" {
+			s.Type = TypeVerse
+			if seen[strings.Join(s.Lines, "\n")] > 1 {
+				s.Type = TypeChorus
+			}
+		}
+		ordinals[s.Type]++
+		s.Ordinal = ordinals[s.Type]
+	}
+	return sections
+}
+
+// Join reassembles sections into text, one blank line between sections.
+func Join(sections []Section) string {
+	blocks := make([]string, len(sections))
+	for i, s := range sections {
+		blocks[i] = s.String()
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// String renders a section with its label, if it had one.
+func (s Section) String() string {
+	if s.Label == "" {
+		return strings.Join(s.Lines, "\n")
+	}
+	return strings.Join(append([]string{s.Label}, s.Lines...), "\n")
+}
+
+// headerType reports the section type a header line names.
+func headerType(line string) (string, bool) {
+	m := header.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	// The number is part of some names, as in "Middle 8".
+	name := strings.ToLower(m[1])
+	if typ, ok := aliases[name+" "+m[2]]; ok && m[2] != "" {
+		return typ, true
+	}
+	typ, ok := aliases[name]
+	return typ, ok
+}
@@ -0,0 +1,133 @@
+package lyrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Section
+	}{
+		{name: "empty", text: " \n\t\n "},
+		{
+			name: "unlabelled verses",
+			text: "one\ntwo\n\nthree",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"one", "two"}},
+				{Type: TypeVerse, Ordinal: 2, Lines: []string{"three"}},
+			},
+		},
+		{
+			name: "blank lines with spaces and runs of them",
+			text: "one\n \t\ntwo\n\n\n\nthree\n\n  \n\nfour",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"one"}},
+				{Type: TypeVerse, Ordinal: 2, Lines: []string{"two"}},
+				{Type: TypeVerse, Ordinal: 3, Lines: []string{"three"}},
+				{Type: TypeVerse, Ordinal: 4, Lines: []string{"four"}},
+			},
+		},
+		{
+			name: "repeated block is the chorus",
+			text: "one\ntwo\n\nla la\nla\n\nthree\n\nla la\nla",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"one", "two"}},
+				{Type: TypeChorus, Ordinal: 1, Lines: []string{"la la", "la"}},
+				{Type: TypeVerse, Ordinal: 2, Lines: []string{"three"}},
+				{Type: TypeChorus, Ordinal: 2, Lines: []string{"la la", "la"}},
+			},
+		},
+		{
+			name: "labelled block keeps its type when repeated",
+			text: "[Verse 1]\nsame\n\n[Verse 2]\nsame",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Label: "[Verse 1]", Lines: []string{"same"}},
+				{Type: TypeVerse, Ordinal: 2, Label: "[Verse 2]", Lines: []string{"same"}},
+			},
+		},
+		{
+			name: "header without lines",
+			text: "[Intro]\n\none",
+			want: []Section{
+				{Type: TypeIntro, Ordinal: 1, Label: "[Intro]", Lines: []string{}},
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"one"}},
+			},
+		},
+		{
+			name: "line that only starts with a type",
+			text: "Chorus of angels\nsing",
+			want: []Section{
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"Chorus of angels", "sing"}},
+			},
+		},
+		{
+			name: "crlf line endings",
+			text: "[Chorus]\r\nla la\r\n\r\none\r\ntwo\r\n",
+			want: []Section{
+				{Type: TypeChorus, Ordinal: 1, Label: "[Chorus]", Lines: []string{"la la"}},
+				{Type: TypeVerse, Ordinal: 1, Lines: []string{"one", "two"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		header string
+		typ    string
+	}{
+		{header: "[Verse]", typ: TypeVerse},
+		{header: "[Verse 2]", typ: TypeVerse},
+		{header: "Verse 2:", typ: TypeVerse},
+		{header: "  verse 3  ", typ: TypeVerse},
+		{header: "(Couplet 1)", typ: TypeVerse},
+		{header: "[Chorus]", typ: TypeChorus},
+		{header: "CHORUS:", typ: TypeChorus},
+		{header: "Refrain", typ: TypeChorus},
+		{header: "[Hook]", typ: TypeChorus},
+		{header: "[Pre-Chorus]", typ: TypeBridge},
+		{header: "Prechorus:", typ: TypeBridge},
+		{header: "(Bridge)", typ: TypeBridge},
+		{header: "[Middle 8]", typ: TypeBridge},
+		{header: "[Intro]", typ: TypeIntro},
+		{header: "Outro:", typ: TypeOutro},
+		{header: "(Coda)", typ: TypeOutro},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got := Parse(tt.header + "\nla la")
+			want := []Section{{Type: tt.typ, Ordinal: 1, Label: strings.TrimSpace(tt.header), Lines: []string{"la la"}}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Parse() = %#v, want %#v", got, want)
+			}
+		})
+	}
+
+	for _, line := range []string{"[Solo]", "Verse of the day: it rains", "2:", "[]", "Hello"} {
+		t.Run(line, func(t *testing.T) {
+			if got := Parse(line + "\nla la"); len(got) != 1 || got[0].Label != "" || got[0].Type != TypeVerse {
+				t.Errorf("Parse() = %#v, want an unlabelled verse", got)
+			}
+		})
+	}
+}
+
+func TestJoinReassemblesText(t *testing.T) {
+	text := "[Verse 1]\none\ntwo\n\n[Chorus]\nla la\n\nthree\n\nla la"
+	if got := Join(Parse(text)); got != text {
+		t.Errorf("Join(Parse()) = %q, want %q", got, text)
+	}
+}
@@ -0,0 +1,157 @@
+package lyrics
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnknownMode = errors.New("unknown pagination mode")
+	ErrInvalidSize = errors.New("page size must be positive")
+)
+
+// Pagination modes: a fixed number of verses or lines per page, or as
+// many whole verses as fit in a character budget.
+const (
+	ModeVerses = "verses"
+	ModeLines  = "lines"
+	ModeChars  = "chars"
+)
+
+// Page is a page of text with the zero-based indices of the verses it
+// shows, fully or in part.
+type Page struct {
+	Text   string
+	Verses []int
+}
+
+type line struct {
+	verse int
+	text  string
+}
+
+// Paginate splits verses into pages of size verses, lines or characters.
+// In chars mode a verse longer than the budget is split between its lines,
+// and a line longer than the budget gets a page of its own.
+func Paginate(verses []string, mode string, size int) ([]Page, error) {
+	if size < 1 {
+		return nil, ErrInvalidSize
+	}
+
+	var chunks [][]line
+	switch mode {
+	case ModeVerses:
+		for start := 0; start < len(verses); start += size {
+			var chunk []line
+			for i := start; i < min(start+size, len(verses)); i++ {
+				chunk = append(chunk, linesOf(i, verses[i])...)
+			}
+			chunks = append(chunks, chunk)
+		}
+	case ModeLines:
+		var all []line
+		for i, v := range verses {
+			all = append(all, linesOf(i, v)...)
+		}
+		for start := 0; start < len(all); start += size {
+			chunks = append(chunks, all[start:min(start+size, len(all))])
+		}
+	case ModeChars:
+		chunks = packChars(verses, size)
+	default:
+		return nil, ErrUnknownMode
+	}
+
+	pages := make([]Page, len(chunks))
+	for i, chunk := range chunks {
+		pages[i] = render(chunk)
+	}
+	return pages, nil
+}
+
+// packChars fills pages greedily with whole verses, falling back to lines
+// for verses that do not fit on a page of their own. The length of the
+// page being filled is kept as it grows rather than rendered again.
+func packChars(verses []string, budget int) [][]line {
+	var chunks [][]line
+	var current []line
+	used := 0
+	add := func(lines []line) {
+		var last *line
+		if len(current) > 0 {
+			last = &current[len(current)-1]
+		}
+
+		n := extent(last, lines)
+		if last != nil && used+n > budget {
+			chunks = append(chunks, current)
+			current, used = nil, 0
+			n = extent(nil, lines)
+		}
+		current = append(current, lines...)
+		used += n
+	}
+
+	for i, v := range verses {
+		lines := linesOf(i, v)
+		if extent(nil, lines) <= budget {
+			add(lines)
+			continue
+		}
+		for _, l := range lines {
+			add([]line{l})
+		}
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+func linesOf(verse int, text string) []line {
+	parts := strings.Split(text, "\n")
+	lines := make([]line, len(parts))
+	for i, p := range parts {
+		lines[i] = line{verse: verse, text: p}
+	}
+	return lines
+}
+
+// render joins lines of a verse with a newline and verses with a blank line.
+func render(lines []line) Page {
+	var b strings.Builder
+	var page Page
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if i == 0 || l.verse != lines[i-1].verse {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			page.Verses = append(page.Verses, l.verse)
+		}
+		b.WriteString(l.text)
+	}
+	page.Text = b.String()
+	return page
+}
+
+// extent counts the characters lines add to a page rendered after last,
+// separators included; last is nil for an empty page.
+func extent(last *line, lines []line) int {
+	n := 0
+	for i, l := range lines {
+		if last != nil {
+			n++
+			if l.verse != last.verse {
+				n++
+			}
+		}
+		n += utf8.RuneCountInString(l.text)
+		last = &lines[i]
+	}
+	return n
+}
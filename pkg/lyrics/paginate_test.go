@@ -0,0 +1,188 @@
+package lyrics
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var verses = []string{
+	"[Verse 1]\none\ntwo",
+	"[Chorus]\nla la",
+	"three\nfour\nfive",
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		size int
+		want []Page
+	}{
+		{
+			name: "verses with a partial last page",
+			mode: ModeVerses,
+			size: 2,
+			want: []Page{
+				{Text: "[Verse 1]\none\ntwo\n\n[Chorus]\nla la", Verses: []int{0, 1}},
+				{Text: "three\nfour\nfive", Verses: []int{2}},
+			},
+		},
+		{
+			name: "verses on one page",
+			mode: ModeVerses,
+			size: 10,
+			want: []Page{{Text: "[Verse 1]\none\ntwo\n\n[Chorus]\nla la\n\nthree\nfour\nfive", Verses: []int{0, 1, 2}}},
+		},
+		{
+			name: "lines across verses with a partial last page",
+			mode: ModeLines,
+			size: 4,
+			want: []Page{
+				{Text: "[Verse 1]\none\ntwo\n\n[Chorus]", Verses: []int{0, 1}},
+				{Text: "la la\n\nthree\nfour\nfive", Verses: []int{1, 2}},
+			},
+		},
+		{
+			name: "lines filling pages exactly",
+			mode: ModeLines,
+			size: 8,
+			want: []Page{{Text: "[Verse 1]\none\ntwo\n\n[Chorus]\nla la\n\nthree\nfour\nfive", Verses: []int{0, 1, 2}}},
+		},
+		{
+			name: "chars packing whole verses",
+			mode: ModeChars,
+			size: 33,
+			want: []Page{
+				{Text: "[Verse 1]\none\ntwo\n\n[Chorus]\nla la", Verses: []int{0, 1}},
+				{Text: "three\nfour\nfive", Verses: []int{2}},
+			},
+		},
+		{
+			name: "chars one short of two verses",
+			mode: ModeChars,
+			size: 32,
+			want: []Page{
+				{Text: "[Verse 1]\none\ntwo", Verses: []int{0}},
+				{Text: "[Chorus]\nla la\n\nthree\nfour\nfive", Verses: []int{1, 2}},
+			},
+		},
+		{
+			name: "chars splitting verses longer than the budget",
+			mode: ModeChars,
+			size: 12,
+			want: []Page{
+				{Text: "[Verse 1]", Verses: []int{0}},
+				{Text: "one\ntwo", Verses: []int{0}},
+				{Text: "[Chorus]", Verses: []int{1}},
+				{Text: "la la\n\nthree", Verses: []int{1, 2}},
+				{Text: "four\nfive", Verses: []int{2}},
+			},
+		},
+		{
+			name: "chars giving a long line a page of its own",
+			mode: ModeChars,
+			size: 4,
+			want: []Page{
+				{Text: "[Verse 1]", Verses: []int{0}},
+				{Text: "one", Verses: []int{0}},
+				{Text: "two", Verses: []int{0}},
+				{Text: "[Chorus]", Verses: []int{1}},
+				{Text: "la la", Verses: []int{1}},
+				{Text: "three", Verses: []int{2}},
+				{Text: "four", Verses: []int{2}},
+				{Text: "five", Verses: []int{2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Paginate(verses, tt.mode, tt.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Paginate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginateErrors(t *testing.T) {
+	if _, err := Paginate(verses, "words", 1); !errors.Is(err, ErrUnknownMode) {
+		t.Errorf("Paginate() error = %v, want %v", err, ErrUnknownMode)
+	}
+	for _, mode := range []string{ModeVerses, ModeLines, ModeChars} {
+		if _, err := Paginate(verses, mode, 0); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("Paginate(%s, 0) error = %v, want %v", mode, err, ErrInvalidSize)
+		}
+	}
+}
+
+func TestPaginateEmpty(t *testing.T) {
+	for _, mode := range []string{ModeVerses, ModeLines, ModeChars} {
+		pages, err := Paginate(nil, mode, 3)
+		if err != nil || len(pages) != 0 {
+			t.Errorf("Paginate(nil, %s) = %v, %v, want no pages", mode, pages, err)
+		}
+	}
+}
+
+// TestPaginateCharsBudget checks that pages in chars mode stay within the
+// budget, counted in runes, unless they hold a single overlong line.
+func TestPaginateCharsBudget(t *testing.T) {
+	verses := []string{
+		"Кукушка\nПесен еще ненаписанных сколько",
+		"short",
+		strings.Repeat("строка\n", 20) + "конец",
+		"a\nb\nc",
+	}
+
+	for size := 1; size < 120; size++ {
+		pages, err := Paginate(verses, ModeChars, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var lines []string
+		for _, p := range pages {
+			if n := utf8.RuneCountInString(p.Text); n > size && strings.Contains(p.Text, "\n") {
+				t.Fatalf("size %d: page of %d characters: %q", size, n, p.Text)
+			}
+			lines = append(lines, strings.Split(p.Text, "\n")...)
+		}
+
+		// Every line is shown once, in order.
+		var want []string
+		for _, v := range verses {
+			want = append(want, strings.Split(v, "\n")...)
+		}
+		var got []string
+		for _, l := range lines {
+			if l != "" {
+				got = append(got, l)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("size %d: lines = %q, want %q", size, got, want)
+		}
+	}
+}
+
+// TestPaginateCharsLongVerse guards against re-rendering the page for
+// every line of a verse, which made long verses take quadratic time.
+func TestPaginateCharsLongVerse(t *testing.T) {
+	verse := strings.TrimSuffix(strings.Repeat("a line of lyrics\n", 6000), "\n")
+	budget := utf8.RuneCountInString(verse) - 1
+
+	pages, err := Paginate([]string{verse}, ModeChars, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 || pages[1].Text != "a line of lyrics" {
+		t.Errorf("Paginate() = %d pages, last %q", len(pages), pages[len(pages)-1].Text)
+	}
+}
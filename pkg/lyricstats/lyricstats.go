@@ -0,0 +1,85 @@
+// Package lyricstats computes word and line statistics of song lyrics.
+//
+// Section headers such as "[Chorus]" are not part of the lyrics and are
+// left out. Words are lowercased runs of letters, digits and apostrophes,
+// so "Don't" and "don't" count as the same word.
+package lyricstats
+
+import (
+	"library-music/pkg/lyrics"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Stats are additive: the statistics of several songs are the sums of
+// their counts, which is how group statistics are built.
+type Stats struct {
+	Words int
+	Lines int
+	// RepeatedLines counts the lines that repeat an earlier line of the song.
+	RepeatedLines int
+	// LineChars is the total length of the lines in characters.
+	LineChars int
+	Counts    []WordCount
+}
+
+type WordCount struct {
+	Word  string
+	Count int
+	// Stopword marks words too common in the language of the song to be
+	// telling, such as articles and pronouns.
+	Stopword bool
+}
+
+// Analyze computes the statistics of text written in lang, a BCP 47 tag;
+// stopwords are only marked for languages with a stopword list, regional
+// variants sharing the list of their language. Counts
+// are ordered from the most frequent word.
+func Analyze(text, lang string) Stats {
+	var stats Stats
+	seen := map[string]bool{}
+	counts := map[string]int{}
+	for _, section := range lyrics.Parse(text) {
+		for _, line := range section.Lines {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+
+			stats.Lines++
+			stats.LineChars += utf8.RuneCountInString(line)
+			key := strings.Join(words(line), " ")
+			if seen[key] {
+				stats.RepeatedLines++
+			}
+			seen[key] = true
+
+			for _, w := range words(line) {
+				counts[w]++
+				stats.Words++
+			}
+		}
+	}
+
+	base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	stops := stopwords[base]
+	stats.Counts = make([]WordCount, 0, len(counts))
+	for w, n := range counts {
+		stats.Counts = append(stats.Counts, WordCount{Word: w, Count: n, Stopword: stops[w]})
+	}
+	sort.Slice(stats.Counts, func(i, j int) bool {
+		if stats.Counts[i].Count != stats.Counts[j].Count {
+			return stats.Counts[i].Count > stats.Counts[j].Count
+		}
+		return stats.Counts[i].Word < stats.Counts[j].Word
+	})
+	return stats
+}
+
+func words(line string) []string {
+	return strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	})
+}
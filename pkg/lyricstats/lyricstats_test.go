@@ -0,0 +1,109 @@
+package lyricstats
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		lang string
+		want Stats
+	}{
+		{
+			name: "empty",
+			want: Stats{Counts: []WordCount{}},
+		},
+		{
+			name: "section headers left out",
+			text: "[Verse 1]\nla la\n\n[Chorus]\nla la",
+			want: Stats{Words: 4, Lines: 2, RepeatedLines: 1, LineChars: 10, Counts: []WordCount{{Word: "la", Count: 4}}},
+		},
+		{
+			name: "repeated lines compared by words",
+			text: "Stop, the music!\nstop the MUSIC\n  \nthe music stops",
+			lang: "en",
+			want: Stats{Words: 9, Lines: 3, RepeatedLines: 1, LineChars: 45, Counts: []WordCount{
+				{Word: "music", Count: 3},
+				{Word: "the", Count: 3, Stopword: true},
+				{Word: "stop", Count: 2},
+				{Word: "stops", Count: 1},
+			}},
+		},
+		{
+			name: "apostrophes kept in words",
+			text: "Don't don’t DON'T",
+			lang: "en",
+			want: Stats{Words: 3, Lines: 1, LineChars: 17, Counts: []WordCount{
+				{Word: "don't", Count: 2, Stopword: true},
+				{Word: "don’t", Count: 1},
+			}},
+		},
+		{
+			name: "digits are words",
+			text: "99 problems",
+			want: Stats{Words: 2, Lines: 1, LineChars: 11, Counts: []WordCount{
+				{Word: "99", Count: 1},
+				{Word: "problems", Count: 1},
+			}},
+		},
+		{
+			name: "characters counted as runes",
+			text: "Я тебя люблю",
+			lang: "ru",
+			want: Stats{Words: 3, Lines: 1, LineChars: 12, Counts: []WordCount{
+				{Word: "люблю", Count: 1},
+				{Word: "тебя", Count: 1, Stopword: true},
+				{Word: "я", Count: 1, Stopword: true},
+			}},
+		},
+		{
+			name: "regional variant uses the language list",
+			text: "the end",
+			lang: "en-GB",
+			want: Stats{Words: 2, Lines: 1, LineChars: 7, Counts: []WordCount{
+				{Word: "end", Count: 1},
+				{Word: "the", Count: 1, Stopword: true},
+			}},
+		},
+		{
+			name: "stopwords of another language",
+			text: "die Welt",
+			lang: "de",
+			want: Stats{Words: 2, Lines: 1, LineChars: 8, Counts: []WordCount{
+				{Word: "die", Count: 1, Stopword: true},
+				{Word: "welt", Count: 1},
+			}},
+		},
+		{
+			name: "unknown language marks no stopwords",
+			text: "the end",
+			lang: "xx",
+			want: Stats{Words: 2, Lines: 1, LineChars: 7, Counts: []WordCount{
+				{Word: "end", Count: 1},
+				{Word: "the", Count: 1},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze(tt.text, tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Stats of several songs are the sums of their counts, which group
+// statistics rely on.
+func TestAnalyzeAdditive(t *testing.T) {
+	first, second := Analyze("la la\nla la", ""), Analyze("la la", "")
+	both := Analyze("la la\nla la\n\nla la", "")
+	if first.Words+second.Words != both.Words || first.Lines+second.Lines != both.Lines ||
+		first.LineChars+second.LineChars != both.LineChars {
+		t.Errorf("Analyze() of both songs = %+v, want the sums of %+v and %+v", both, first, second)
+	}
+}
@@ -0,0 +1,41 @@
+package lyricstats
+
+import (
+	"strings"
+)
+
+// stopwords holds the most common words of the languages songs are most
+// often written in, keyed by ISO 639-1 code.
+var stopwords = map[string]map[string]bool{
+	"en": set(`a about all am an and are as at be been but by can could do don't for from
+		get got had has have he her him his how i i'm if in into is it it's its just let me
+		my no not now of oh on one or our out over she so that the their them then there
+		these they this to too up us was we were what when where which who will with would
+		yeah you you're your`),
+	"de": set(`aber alle als also am an auch auf aus bei bin bis bist da das dass dein deine dem
+		den der des dich die dir doch du ein eine einem einen einer er es für hab habe hat
+		ich ihr im in ist ja kann kein mal man mein meine mich mir mit nach nicht noch nur
+		ob oder sich sie sind so über um und uns vom von vor war was wenn wie wir wird zu zum zur`),
+	"fr": set(`à au aux avec ce ces c'est dans de des du elle en est et eux il ils j'ai je la
+		le les leur lui ma mais me mes moi mon ne nous on ou par pas pour qu'il que qui sa
+		se ses si son sur ta te tes toi ton tu un une vos votre vous y`),
+	"es": set(`a al como con de del el ella en era es esta este fue ha la las le lo los me mi
+		mis muy más no nos o para pero por que se si sin su sus te ti tu tus un una uno y ya yo`),
+	"it": set(`a al che chi ci come con da del della di e è gli ha ho i il in io la le lo ma me
+		mi ne nel non per più quando se si sono su ti tu un una uno`),
+	"pt": set(`a ao as com como da das de do dos e é ela ele em eu isso mais mas me meu minha
+		na não no nos o os ou para pela pelo por que se sem seu sua te tu um uma você`),
+	"ru": set(`а без бы был была было в вот все всё вы да для до его её если есть ещё же за и
+		из или им их к как ко когда кто ли мне мой моя мы на не нет ни но ну о об он она
+		они от по под с со так там тебе тебя то только ты у уж что это я`),
+	"uk": set(`а але без би був була в все ви від да для до є же за з і й із його її як коли
+		ми мене мені на не ні но про та так те тебе ти то тобі у хто це що я`),
+}
+
+func set(words string) map[string]bool {
+	res := map[string]bool{}
+	for _, w := range strings.Fields(words) {
+		res[w] = true
+	}
+	return res
+}
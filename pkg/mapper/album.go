@@ -0,0 +1,59 @@
+package mapper
+
+import (
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"time"
+)
+
+type AlbumMapper struct {
+}
+
+func (m *AlbumMapper) AddToAlbum(object services.AlbumToAdd) (models.Album, error) {
+	album := models.Album{
+		Title: object.Title,
+		Group: models.Group{Name: object.Group},
+	}
+
+	if object.ReleaseDate != "" {
+		date, err := time.Parse("02.01.2006", object.ReleaseDate)
+		if err != nil {
+			return models.Album{}, err
+		}
+		album.ReleaseDate = &date
+	}
+
+	if object.CoverLink != "" {
+		album.CoverLink = &object.CoverLink
+	}
+	return album, nil
+}
+
+func (m *AlbumMapper) AlbumForGet(object models.Album) services.AlbumToGet {
+	res := services.AlbumToGet{
+		Id:    object.Id,
+		Title: object.Title,
+		Group: object.Group.Name,
+	}
+
+	if object.ReleaseDate != nil {
+		res.ReleaseDate = object.ReleaseDate.Format("02.01.2006")
+	}
+
+	if object.CoverLink != nil {
+		res.CoverLink = *object.CoverLink
+	}
+
+	if len(object.Tracks) > 0 {
+		res.Tracks = make([]services.AlbumTrackToGet, len(object.Tracks))
+		for i, t := range object.Tracks {
+			res.Tracks[i] = services.AlbumTrackToGet{
+				MusicId:     t.MusicId,
+				Song:        t.Song,
+				DiscNumber:  t.DiscNumber,
+				TrackNumber: t.TrackNumber,
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,157 @@
+package mapper
+
+import (
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"library-music/pkg/lrc"
+	"library-music/pkg/lyrics"
+	"library-music/pkg/lyricstats"
+	"time"
+)
+
+func (m *MusicMapper) SectionsToLyricSections(sections []lyrics.Section) []models.LyricSection {
+	res := make([]models.LyricSection, len(sections))
+	for i, s := range sections {
+		res[i] = models.LyricSection{
+			Position: i + 1,
+			Type:     s.Type,
+			Ordinal:  s.Ordinal,
+			Label:    s.Label,
+			Lines:    s.Lines,
+		}
+	}
+	return res
+}
+
+func (m *MusicMapper) LyricSectionsToSections(sections []models.LyricSection) []lyrics.Section {
+	res := make([]lyrics.Section, len(sections))
+	for i, s := range sections {
+		res[i] = lyrics.Section{
+			Type:    s.Type,
+			Ordinal: s.Ordinal,
+			Label:   s.Label,
+			Lines:   s.Lines,
+		}
+	}
+	return res
+}
+
+func (m *MusicMapper) LyricsForGet(object models.Lyrics) services.LyricsToGet {
+	res := services.LyricsToGet{
+		MusicId:  object.MusicId,
+		Sections: make([]services.LyricSectionToGet, len(object.Sections)),
+	}
+	for i, s := range object.Sections {
+		res.Sections[i] = services.LyricSectionToGet{
+			Type:    s.Type,
+			Ordinal: s.Ordinal,
+			Lines:   s.Lines,
+		}
+	}
+	return res
+}
+
+// LyricMatchForGet locates object in the pages of countVerse verses of
+// GetTextMusic; countVerse 0 leaves the page unset.
+func (m *MusicMapper) LyricMatchForGet(object models.LyricMatch, countVerse int) services.LyricMatchToGet {
+	res := services.LyricMatchToGet{
+		MusicId:    object.MusicId,
+		Song:       object.Song,
+		Group:      object.Group,
+		VerseIndex: object.Verse - 1,
+		Line:       object.Line,
+		Text:       object.Text,
+		Before:     object.Before,
+		After:      object.After,
+	}
+	if countVerse > 0 {
+		res.Page = res.VerseIndex/countVerse + 1
+	}
+	return res
+}
+
+type LyricsMapper struct {
+}
+
+func (m *LyricsMapper) LRCToTimedLines(object lrc.Lyrics) []models.TimedLine {
+	res := make([]models.TimedLine, len(object.Lines))
+	for i, line := range object.Lines {
+		res[i] = models.TimedLine{
+			StartMs: int(line.Start.Milliseconds()),
+			Text:    line.Text,
+		}
+		for _, w := range line.Words {
+			res[i].Words = append(res[i].Words, models.TimedWord{
+				StartMs: int(w.Start.Milliseconds()),
+				Text:    w.Text,
+			})
+		}
+	}
+	return res
+}
+
+func (m *LyricsMapper) SyncedToLRC(object models.SyncedLyrics) lrc.Lyrics {
+	res := lrc.Lyrics{
+		Title:  object.Song,
+		Artist: object.Group,
+		Lines:  make([]lrc.Line, len(object.Lines)),
+	}
+	for i, line := range object.Lines {
+		res.Lines[i] = lrc.Line{
+			Start: time.Duration(line.StartMs) * time.Millisecond,
+			Text:  line.Text,
+		}
+		for _, w := range line.Words {
+			res.Lines[i].Words = append(res.Lines[i].Words, lrc.Word{
+				Start: time.Duration(w.StartMs) * time.Millisecond,
+				Text:  w.Text,
+			})
+		}
+	}
+	return res
+}
+
+func (m *LyricsMapper) SyncedForGet(object models.SyncedLyrics) services.SyncedLyricsToGet {
+	return services.SyncedLyricsToGet{
+		MusicId: object.MusicId,
+		Song:    object.Song,
+		Group:   object.Group,
+		Lines:   object.Lines,
+	}
+}
+
+func (m *MusicMapper) StatsToLyricStats(object lyricstats.Stats) models.LyricStats {
+	res := models.LyricStats{
+		Songs:         1,
+		Words:         object.Words,
+		UniqueWords:   len(object.Counts),
+		Lines:         object.Lines,
+		RepeatedLines: object.RepeatedLines,
+		LineChars:     object.LineChars,
+		Counts:        make([]models.WordCount, len(object.Counts)),
+	}
+	for i, c := range object.Counts {
+		res.Counts[i] = models.WordCount{Word: c.Word, Count: c.Count, Stopword: c.Stopword}
+	}
+	return res
+}
+
+func (m *MusicMapper) StatsForGet(object models.LyricStats) services.LyricStatsToGet {
+	res := services.LyricStatsToGet{
+		Songs:       object.Songs,
+		Words:       object.Words,
+		UniqueWords: object.UniqueWords,
+		TopWords:    object.Counts,
+	}
+	if object.Words > 0 {
+		res.Richness = float64(object.UniqueWords) / float64(object.Words)
+	}
+	if object.Lines > 0 {
+		res.RepetitionRatio = float64(object.RepeatedLines) / float64(object.Lines)
+		res.AverageLineLength = float64(object.LineChars) / float64(object.Lines)
+	}
+	if res.TopWords == nil {
+		res.TopWords = []models.WordCount{}
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package mapper
+
+import (
+	"library-music/internal/domain/models"
+	"library-music/pkg/lyricstats"
+	"testing"
+)
+
+func TestStatsForGet(t *testing.T) {
+	m := MusicMapper{}
+	tests := []struct {
+		name       string
+		stats      models.LyricStats
+		richness   float64
+		repetition float64
+		lineLength float64
+	}{
+		{
+			name:  "no lyrics",
+			stats: models.LyricStats{},
+		},
+		{
+			name:       "analyzed song",
+			stats:      m.StatsToLyricStats(lyricstats.Analyze("la la la\nla la la\nla di da", "")),
+			richness:   3.0 / 9,
+			repetition: 1.0 / 3,
+			lineLength: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.StatsForGet(tt.stats)
+			if got.Richness != tt.richness || got.RepetitionRatio != tt.repetition || got.AverageLineLength != tt.lineLength {
+				t.Errorf("StatsForGet() richness %v, repetition %v, line length %v, want %v, %v, %v",
+					got.Richness, got.RepetitionRatio, got.AverageLineLength, tt.richness, tt.repetition, tt.lineLength)
+			}
+			if got.TopWords == nil {
+				t.Error("StatsForGet() top words = nil, want an empty list")
+			}
+		})
+	}
+}
@@ -1,22 +1,41 @@
 package mapper
 
 import (
+	"golang.org/x/text/language"
 	"library-music/internal/domain/models"
 	"library-music/internal/services"
+	"library-music/pkg/links"
+	"strconv"
 	"time"
 )
 
+// MusicMapper builds the URLs of artwork under MediaURL.
 type MusicMapper struct {
+	MediaURL string
 }
 
-func (m *MusicMapper) FilterToMusic(object services.MusicFilterParams) models.Music {
+func (m *MusicMapper) FilterToMusicFilter(object services.MusicFilterParams) models.MusicFilter {
 	date, _ := time.Parse("02.01.2006", object.ReleaseDate)
-	return models.Music{
+	lang := object.Lang
+	if tag, err := language.Parse(lang); err == nil {
+		lang = tag.String()
+	}
+	return models.MusicFilter{
 		Song:        object.Song,
-		Group:       models.Group{Name: object.Group},
+		Group:       object.Group,
 		Text:        object.Text,
 		Link:        object.Link,
 		ReleaseDate: date,
+		AlbumId:     object.AlbumId,
+		GenreId:     object.GenreId,
+		Tags:        object.Tags,
+		Lang:        lang,
+		Explicit:    object.Explicit,
+		PersonId:    object.PersonId,
+		Role:        object.Role,
+		MemberId:    object.MemberId,
+		MemberYear:  object.MemberYear,
+		Provider:    object.Provider,
 	}
 }
 
@@ -27,6 +46,38 @@ func (m *MusicMapper) MusicForGet(object models.Music) services.MusicToGet {
 		Group:       object.Group,
 		Link:        object.Link,
 		ReleaseDate: object.ReleaseDate.Format("02.01.2006"),
+		Lang:        object.Lang,
+		Explicit:    object.Explicit,
+		Links:       object.Links,
+		Cover:       m.ArtworkForGet(object.Cover),
+		GroupImage:  m.ArtworkForGet(object.GroupImage),
+		Audio:       m.AudioForGet(object.Audio),
+	}
+}
+
+func (m *MusicMapper) ArtworkForGet(object *models.Artwork) *services.ImageToGet {
+	if object == nil {
+		return nil
+	}
+
+	base := m.MediaURL + "/" + object.Key + "/"
+	image := &services.ImageToGet{
+		URL:        base + "original." + object.Ext,
+		Width:      object.Width,
+		Height:     object.Height,
+		Thumbnails: make(map[string]string, len(object.Sizes)),
+	}
+	for _, size := range object.Sizes {
+		image.Thumbnails[strconv.Itoa(size)] = base + strconv.Itoa(size) + ".jpg"
+	}
+	return image
+}
+
+func (m *MusicMapper) LinkToMusicLink(object links.Link) models.MusicLink {
+	return models.MusicLink{
+		Provider:   object.Provider,
+		URL:        object.URL,
+		ProviderId: object.ProviderID,
 	}
 }
 
@@ -43,3 +94,17 @@ func (m *MusicMapper) UpdateToMusic(object services.MusicToUpdate) (models.Music
 		ReleaseDate: date,
 	}, nil
 }
+
+// AudioForGet links to the endpoint streaming the file, as audio files
+// are served to signed-in users only.
+func (m *MusicMapper) AudioForGet(object *models.Audio) *services.AudioToGet {
+	if object == nil {
+		return nil
+	}
+
+	return &services.AudioToGet{
+		URL:         "/api/audio/stream?id=" + strconv.Itoa(object.MusicId),
+		ContentType: object.ContentType,
+		Size:        object.Size,
+	}
+}
@@ -0,0 +1,61 @@
+package mapper
+
+import (
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+	"time"
+)
+
+type PersonMapper struct {
+}
+
+func (m *PersonMapper) AddToMembership(groupId int, object services.MembershipToAdd) (models.Membership, error) {
+	membership := models.Membership{
+		GroupId:  groupId,
+		PersonId: object.PersonId,
+	}
+
+	if object.From != "" {
+		date, err := time.Parse("02.01.2006", object.From)
+		if err != nil {
+			return models.Membership{}, err
+		}
+		membership.From = &date
+	}
+
+	if object.To != "" {
+		date, err := time.Parse("02.01.2006", object.To)
+		if err != nil {
+			return models.Membership{}, err
+		}
+		membership.To = &date
+	}
+	return membership, nil
+}
+
+func (m *PersonMapper) MembershipForGet(object models.Membership) services.MembershipToGet {
+	res := services.MembershipToGet{
+		Id:       object.Id,
+		GroupId:  object.GroupId,
+		Group:    object.Group,
+		PersonId: object.PersonId,
+		Person:   object.Person,
+	}
+
+	if object.From != nil {
+		res.From = object.From.Format("02.01.2006")
+	}
+
+	if object.To != nil {
+		res.To = object.To.Format("02.01.2006")
+	}
+	return res
+}
+
+func (m *PersonMapper) MembershipsForGet(objects []models.Membership) []services.MembershipToGet {
+	res := make([]services.MembershipToGet, len(objects))
+	for i, object := range objects {
+		res[i] = m.MembershipForGet(object)
+	}
+	return res
+}
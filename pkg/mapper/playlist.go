@@ -0,0 +1,40 @@
+package mapper
+
+import (
+	"library-music/internal/domain/models"
+	"library-music/internal/services"
+)
+
+type PlaylistMapper struct {
+}
+
+// PlaylistForGet hides the share token unless withToken is set,
+// so it is only disclosed to the playlist owner.
+func (m *PlaylistMapper) PlaylistForGet(object models.Playlist, withToken bool) services.PlaylistToGet {
+	res := services.PlaylistToGet{
+		Id:         object.Id,
+		Name:       object.Name,
+		Shared:     object.PublicToken != nil,
+		TrackCount: object.TrackCount,
+		CreatedAt:  object.CreatedAt,
+		UpdatedAt:  object.UpdatedAt,
+	}
+
+	if withToken && object.PublicToken != nil {
+		res.PublicToken = *object.PublicToken
+	}
+
+	if len(object.Tracks) > 0 {
+		res.Tracks = make([]services.PlaylistTrackToGet, len(object.Tracks))
+		for i, t := range object.Tracks {
+			res.Tracks[i] = services.PlaylistTrackToGet{
+				Position:  t.Position,
+				MusicId:   t.MusicId,
+				Song:      t.Song,
+				Group:     t.Group,
+				Available: t.MusicId != nil,
+			}
+		}
+	}
+	return res
+}
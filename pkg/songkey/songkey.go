@@ -0,0 +1,77 @@
+// Package songkey reduces song titles to keys that compare equal for
+// spellings of the same title, and scores how alike two keys are.
+//
+// A key is the title lowercased, without diacritics or punctuation, and
+// without the featured artists and remaster notes that vary between
+// releases: "Café (feat. X) - 2011 Remaster" has the key "cafe".
+package songkey
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// featuring drops "feat. X", "ft. X" and "featuring X" to the end of the
+	// title or of the bracket they are in.
+	featuring = regexp.MustCompile(`(?i)[(\[]?\s*\b(feat\.?|ft\.?|featuring)\s+[^)\]]*[)\]]?`)
+	// remaster drops "(Remastered 2011)", "- 2011 Remaster" and the like.
+	remaster = regexp.MustCompile(`(?i)[(\[-]\s*(\d{4}\s+)?(digital(ly)?\s+)?remaster(ed)?(\s+(version|\d{4}))?\s*[)\]]?`)
+)
+
+// Key returns the comparison key of a song title.
+func Key(title string) string {
+	title = featuring.ReplaceAllString(title, " ")
+	title = remaster.ReplaceAllString(title, " ")
+
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), title)
+	if err == nil {
+		title = stripped
+	}
+
+	title = strings.NewReplacer("'", "", "’", "").Replace(title)
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// Similarity scores two keys from 0 to 1 by the Dice coefficient of their
+// character bigrams; equal keys score 1.
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ga, gb := bigrams(a), bigrams(b)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, n := range ga {
+		total += n
+	}
+	for _, n := range gb {
+		total += n
+	}
+
+	shared := 0
+	for g, n := range ga {
+		shared += min(n, gb[g])
+	}
+	return 2 * float64(shared) / float64(total)
+}
+
+func bigrams(key string) map[string]int {
+	grams := map[string]int{}
+	r := []rune(strings.ReplaceAll(key, " ", ""))
+	for i := 0; i+2 <= len(r); i++ {
+		grams[string(r[i:i+2])]++
+	}
+	return grams
+}
@@ -0,0 +1,72 @@
+package songkey
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Yesterday", want: "yesterday"},
+		{title: "YESTERDAY", want: "yesterday"},
+		{title: "  Hey,   Jude!  ", want: "hey jude"},
+		{title: "Don't Stop Me Now", want: "dont stop me now"},
+		{title: "Don’t Stop Me Now", want: "dont stop me now"},
+		{title: "Café", want: "cafe"},
+		{title: "Ça plane pour moi", want: "ca plane pour moi"},
+		{title: "Café (feat. X) - 2011 Remaster", want: "cafe"},
+		{title: "Song ft. Someone", want: "song"},
+		{title: "Song featuring Someone", want: "song"},
+		{title: "Song [Feat. A & B]", want: "song"},
+		{title: "Song (Remastered 2009)", want: "song"},
+		{title: "Song (Digitally Remastered)", want: "song"},
+		{title: "Song - Remastered Version", want: "song"},
+		{title: "Left Feet", want: "left feet"},
+		{title: "Кукушка", want: "кукушка"},
+		{title: "1999", want: "1999"},
+		{title: "?!", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := Key(tt.title); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "equal", a: "cafe", b: "cafe", want: 1},
+		{name: "both empty", a: "", b: "", want: 1},
+		{name: "one empty", a: "cafe", b: "", want: 0},
+		{name: "single letter", a: "a", b: "ab", want: 0},
+		{name: "nothing shared", a: "abc", b: "xyz", want: 0},
+		{name: "spaces ignored", a: "hey jude", b: "heyjude", want: 1},
+		// night: ni ig gh ht, nacht: na ac ch ht; one of eight shared.
+		{name: "one bigram shared", a: "night", b: "nacht", want: 0.25},
+		// Repeated bigrams count as often as both keys have them.
+		{name: "repeated bigrams", a: "aaa", b: "aa", want: 2.0 / 3},
+		// Swapping the last two letters changes two of eight bigrams.
+		{name: "letters swapped", a: "yesterday", b: "yesterdya", want: 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := Similarity(tt.b, tt.a); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
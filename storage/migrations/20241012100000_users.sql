@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    login TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'reader',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE sessions (
+    token_hash BYTEA PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE user_favorites (
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, music_id)
+);
+
+CREATE TABLE user_library (
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, music_id)
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_user_favorites_music_id ON user_favorites(music_id);
+CREATE INDEX idx_user_library_music_id ON user_library(music_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE user_library;
+DROP TABLE user_favorites;
+DROP TABLE sessions;
+DROP TABLE users;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE playlists (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    public_token TEXT UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Tracks keep a snapshot of the song and group names, so that a playlist
+-- still shows what used to be there after the song itself is deleted.
+CREATE TABLE playlist_tracks (
+    playlist_id INTEGER NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    music_id INTEGER REFERENCES music(id) ON DELETE SET NULL,
+    song TEXT NOT NULL,
+    group_name TEXT NOT NULL,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX idx_playlists_user_id ON playlists(user_id);
+CREATE INDEX idx_playlist_tracks_music_id ON playlist_tracks(music_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE playlist_tracks;
+DROP TABLE playlists;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE albums (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    release_date DATE,
+    cover_link TEXT,
+    group_id INTEGER REFERENCES groups(id) ON DELETE SET NULL
+);
+
+CREATE TABLE album_tracks (
+    album_id INTEGER REFERENCES albums(id) ON DELETE CASCADE,
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    disc_number INTEGER NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+    track_number INTEGER NOT NULL CHECK (track_number > 0),
+    PRIMARY KEY (album_id, music_id),
+    UNIQUE (album_id, disc_number, track_number)
+);
+
+CREATE INDEX idx_albums_group_id ON albums(group_id);
+CREATE INDEX idx_album_tracks_music_id ON album_tracks(music_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE album_tracks;
+DROP TABLE albums;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE genres (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    parent_id INTEGER REFERENCES genres(id) ON DELETE RESTRICT,
+    UNIQUE NULLS NOT DISTINCT (parent_id, name)
+);
+
+CREATE TABLE music_genres (
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    genre_id INTEGER REFERENCES genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (music_id, genre_id)
+);
+
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE music_tags (
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (music_id, tag_id)
+);
+
+CREATE TABLE group_tags (
+    group_id INTEGER REFERENCES groups(id) ON DELETE CASCADE,
+    tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, tag_id)
+);
+
+CREATE INDEX idx_genres_parent_id ON genres(parent_id);
+CREATE INDEX idx_music_genres_genre_id ON music_genres(genre_id);
+CREATE INDEX idx_music_tags_tag_id ON music_tags(tag_id);
+CREATE INDEX idx_group_tags_tag_id ON group_tags(tag_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE group_tags;
+DROP TABLE music_tags;
+DROP TABLE tags;
+DROP TABLE music_genres;
+DROP TABLE genres;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE lyric_sections (
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    type TEXT NOT NULL CHECK (type IN ('verse', 'chorus', 'bridge', 'intro', 'outro')),
+    ordinal INTEGER NOT NULL CHECK (ordinal > 0),
+    label TEXT NOT NULL DEFAULT '',
+    lines TEXT[] NOT NULL,
+    PRIMARY KEY (music_id, position)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE lyric_sections;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE lyric_timings (
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    start_ms INTEGER NOT NULL CHECK (start_ms >= 0),
+    text TEXT NOT NULL,
+    words JSONB,
+    PRIMARY KEY (music_id, position)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE lyric_timings;
+-- +goose StatementEnd
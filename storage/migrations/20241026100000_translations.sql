@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE music ADD COLUMN lang TEXT;
+
+CREATE TABLE lyric_translations (
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    lang TEXT NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (music_id, lang)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE lyric_translations;
+ALTER TABLE music DROP COLUMN lang;
+-- +goose StatementEnd
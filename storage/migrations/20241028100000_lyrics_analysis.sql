@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE music ADD COLUMN explicit BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_music_lang ON music(lang);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_music_lang;
+ALTER TABLE music DROP COLUMN explicit;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE lyric_stats (
+    music_id INTEGER PRIMARY KEY REFERENCES music(id) ON DELETE CASCADE,
+    words INTEGER NOT NULL,
+    lines INTEGER NOT NULL,
+    repeated_lines INTEGER NOT NULL,
+    line_chars INTEGER NOT NULL
+);
+
+CREATE TABLE lyric_words (
+    music_id INTEGER REFERENCES lyric_stats(music_id) ON DELETE CASCADE,
+    word TEXT NOT NULL,
+    count INTEGER NOT NULL CHECK (count > 0),
+    stopword BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (music_id, word)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE lyric_words;
+DROP TABLE lyric_stats;
+-- +goose StatementEnd
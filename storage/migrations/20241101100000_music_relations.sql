@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE music_relations (
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    related_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('cover_of', 'remix_of', 'live_version_of', 'samples', 'translation_of')),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (music_id, related_id, type),
+    CHECK (music_id <> related_id)
+);
+
+CREATE INDEX idx_music_relations_related ON music_relations(related_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE music_relations;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE people (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE music_credits (
+    music_id INTEGER REFERENCES music(id) ON DELETE CASCADE,
+    person_id INTEGER REFERENCES people(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('composer', 'lyricist', 'producer', 'performer')),
+    PRIMARY KEY (music_id, person_id, role)
+);
+
+CREATE INDEX idx_music_credits_person ON music_credits(person_id, role);
+
+CREATE TABLE group_members (
+    id SERIAL PRIMARY KEY,
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    joined_on DATE,
+    left_on DATE,
+    CHECK (left_on IS NULL OR joined_on IS NULL OR left_on >= joined_on)
+);
+
+CREATE INDEX idx_group_members_group ON group_members(group_id);
+CREATE INDEX idx_group_members_person ON group_members(person_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE group_members;
+DROP TABLE music_credits;
+DROP TABLE people;
+-- +goose StatementEnd
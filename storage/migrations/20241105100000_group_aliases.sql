@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE group_aliases (
+    name TEXT NOT NULL,
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_group_aliases_name ON group_aliases(lower(name));
+CREATE INDEX idx_group_aliases_group ON group_aliases(group_id);
+
+-- group_id_by_name resolves the exact name of a group, or one of its
+-- aliases in any case, to the id of the group.
+CREATE FUNCTION group_id_by_name(group_name TEXT) RETURNS INTEGER
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(
+        (SELECT id FROM groups WHERE name = group_name),
+        (SELECT group_id FROM group_aliases WHERE lower(name) = lower(group_name))
+    )
+$$;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION group_id_by_name(TEXT);
+DROP TABLE group_aliases;
+-- +goose StatementEnd
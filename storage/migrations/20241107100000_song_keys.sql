@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE music ADD COLUMN song_key TEXT;
+
+CREATE INDEX idx_music_song_key ON music(song_key);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_music_song_key;
+ALTER TABLE music DROP COLUMN song_key;
+-- +goose StatementEnd
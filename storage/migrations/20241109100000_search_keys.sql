@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- search_key spells a name in lowercase Latin letters so Cyrillic and Latin
+-- spellings of it compare equal: "Кино" and "Kino" both become "kino".
+-- Letters that transliterations render differently are folded together
+-- (y, j and i; kh and h; w and v), so "Ария" and "Aria" match, and
+-- punctuation becomes single spaces.
+CREATE FUNCTION search_key(name TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT trim(regexp_replace(
+        replace(replace(replace(replace(replace(replace(replace(
+            translate(
+                replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+                    lower(name),
+                    'щ', 'shch'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'),
+                    'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'), 'є', 'ye'), 'ї', 'yi'),
+                'абвгґдеёзийклмнопрстуфыэіáàâäãåéèêëíìîïóòôöõúùûüýñçъь',
+                'abvggdeeziiklmnoprstufyeiaaaaaaeeeeiiiiooooouuuuync'),
+            'kh', 'h'), 'y', 'i'), 'j', 'i'), 'w', 'v'), 'x', 'ks'), 'q', 'k'), 'ii', 'i'),
+        '[^[:alnum:]]+', ' ', 'g'))
+$$;
+
+ALTER TABLE music ADD COLUMN song_search TEXT GENERATED ALWAYS AS (search_key(song)) STORED;
+ALTER TABLE groups ADD COLUMN name_search TEXT GENERATED ALWAYS AS (search_key(name)) STORED;
+
+CREATE INDEX idx_music_song_search ON music(song_search);
+CREATE INDEX idx_groups_name_search ON groups(name_search);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_groups_name_search;
+DROP INDEX idx_music_song_search;
+ALTER TABLE groups DROP COLUMN name_search;
+ALTER TABLE music DROP COLUMN song_search;
+DROP FUNCTION search_key(TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE music_links (
+    id SERIAL PRIMARY KEY,
+    music_id INTEGER NOT NULL REFERENCES music(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL CHECK (provider IN ('youtube', 'spotify', 'apple_music', 'bandcamp', 'lyrics', 'other')),
+    url TEXT NOT NULL,
+    provider_id TEXT,
+    UNIQUE (music_id, url)
+);
+
+CREATE INDEX idx_music_links_provider ON music_links(provider, provider_id);
+
+-- The existing links are carried over, recognizing YouTube and Spotify
+-- links; anything else is kept as other and can be retyped by hand.
+INSERT INTO music_links (music_id, provider, url, provider_id)
+SELECT id, provider, link, provider_id
+FROM (
+    SELECT id, link,
+        CASE
+            WHEN yt IS NOT NULL THEN 'youtube'
+            WHEN sp IS NOT NULL THEN 'spotify'
+            ELSE 'other'
+        END AS provider,
+        COALESCE(yt, sp) AS provider_id
+    FROM (
+        SELECT id, link,
+            COALESCE(
+                substring(link FROM '^https?://(?:www\.|m\.|music\.)?youtube\.com/watch\?(?:.*&)?v=([A-Za-z0-9_-]{11})(?:[&#].*)?$'),
+                substring(link FROM '^https?://youtu\.be/([A-Za-z0-9_-]{11})(?:[?#].*)?$')
+            ) AS yt,
+            substring(link FROM '^https?://open\.spotify\.com/(?:intl-[a-z-]+/)?track/([A-Za-z0-9]{22})(?:[?#].*)?$') AS sp
+        FROM music
+        WHERE link ~* '^https?://[^/]+'
+    ) matched
+) typed;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE music_links;
+-- +goose StatementEnd
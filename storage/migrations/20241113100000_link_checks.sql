@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE music_links
+    ADD COLUMN status_code INTEGER,
+    ADD COLUMN redirect_url TEXT,
+    ADD COLUMN check_error TEXT,
+    ADD COLUMN checked_at TIMESTAMP;
+
+CREATE INDEX idx_music_links_broken ON music_links(checked_at)
+    WHERE check_error IS NOT NULL OR status_code >= 400;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_music_links_broken;
+ALTER TABLE music_links
+    DROP COLUMN checked_at,
+    DROP COLUMN check_error,
+    DROP COLUMN redirect_url,
+    DROP COLUMN status_code;
+-- +goose StatementEnd
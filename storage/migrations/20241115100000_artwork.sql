@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- artwork holds the cover of a song or the image of a group. The files
+-- are kept in the blob store under key: the upload as original.<ext> and
+-- a JPEG thumbnail per size as <size>.jpg.
+CREATE TABLE artwork (
+    id SERIAL PRIMARY KEY,
+    music_id INTEGER UNIQUE REFERENCES music(id) ON DELETE CASCADE,
+    group_id INTEGER UNIQUE REFERENCES groups(id) ON DELETE CASCADE,
+    key TEXT NOT NULL UNIQUE,
+    ext TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    sizes INTEGER[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK ((music_id IS NULL) <> (group_id IS NULL))
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE artwork;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- audio_files holds the audio file of a song, kept in the blob store
+-- under key.
+CREATE TABLE audio_files (
+    music_id INTEGER PRIMARY KEY REFERENCES music(id) ON DELETE CASCADE,
+    key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audio_files;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_groups_lower_name ON groups(lower(name));
+
+-- group_id_by_name resolves the name of a group, or one of its aliases,
+-- in any case to the id of the group. An exact name wins over groups
+-- whose names differ only in case, which older rows may have.
+CREATE OR REPLACE FUNCTION group_id_by_name(group_name TEXT) RETURNS INTEGER
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(
+        (SELECT id FROM groups WHERE name = group_name),
+        (SELECT id FROM groups WHERE lower(name) = lower(group_name) ORDER BY id LIMIT 1),
+        (SELECT group_id FROM group_aliases WHERE lower(name) = lower(group_name))
+    )
+$$;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION group_id_by_name(group_name TEXT) RETURNS INTEGER
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(
+        (SELECT id FROM groups WHERE name = group_name),
+        (SELECT group_id FROM group_aliases WHERE lower(name) = lower(group_name))
+    )
+$$;
+
+DROP INDEX idx_groups_lower_name;
+-- +goose StatementEnd
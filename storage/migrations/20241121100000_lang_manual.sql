@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- lang_manual marks a language an editor set by hand, which detection
+-- from the text then leaves alone.
+ALTER TABLE music ADD COLUMN lang_manual BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE music DROP COLUMN lang_manual;
+-- +goose StatementEnd